@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/trendscout/backend/internal/metrics"
+	"github.com/trendscout/backend/internal/models"
+	"github.com/trendscout/backend/internal/trend"
+)
+
+// 全キーワード（または指定キーワード）で予測モデルのローリング・オリジン
+// バックテストを行い、モデルごとの平均精度をリーダーボードとして出力します
+//
+//	go run ./cmd/backtest
+//	go run ./cmd/backtest -keyword-id 3 -horizon 14 -folds 6 -models hw,arima
+func main() {
+	keywordID := flag.Int("keyword-id", 0, "評価するキーワードID（0 の場合は全キーワード）")
+	horizon := flag.Int("horizon", 7, "各起点から予測する日数")
+	folds := flag.Int("folds", 4, "予測起点の数")
+	modelList := flag.String("models", "", "比較するモデル（カンマ区切り、省略時は全モデル）")
+	days := flag.Int("days", 180, "使用する履歴の日数")
+	metric := flag.String("metric", metrics.Volume, "予測対象のメトリクス")
+	flag.Parse()
+
+	if !metrics.IsValid(*metric) {
+		log.Fatalf("不正なメトリクスです: %s", *metric)
+	}
+	opts := trend.BacktestOptions{Horizon: *horizon, Folds: *folds, Models: splitModels(*modelList)}
+	for _, model := range opts.Models {
+		if !trend.IsVolumeModel(model) {
+			log.Fatalf("不正なモデルです: %s", model)
+		}
+	}
+
+	// 環境変数の読み込み
+	if err := godotenv.Load(".env.local"); err != nil {
+		if err := godotenv.Load(); err != nil {
+			log.Println("Warning: .env file not found, using environment variables")
+		}
+	}
+
+	if err := models.InitDatabases(); err != nil {
+		log.Fatalf("データベース初期化エラー: %v", err)
+	}
+	defer models.CloseDatabases()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	ids := []int{*keywordID}
+	if *keywordID == 0 {
+		keywords, err := models.GetAllKeywords(ctx)
+		if err != nil {
+			log.Fatalf("キーワード取得エラー: %v", err)
+		}
+		ids = ids[:0]
+		for _, k := range keywords {
+			ids = append(ids, k.ID)
+		}
+	}
+
+	endDate := time.Now()
+	startDate := endDate.AddDate(0, 0, -*days)
+	engine := trend.NewPredictionEngine()
+	board := map[string]*standing{}
+	evaluated := 0
+
+	for _, id := range ids {
+		records, err := models.GetMetricTrendRecords(ctx, id, *metric, startDate, endDate, nil)
+		if err != nil {
+			log.Printf("キーワード %d のデータ取得エラー: %v", id, err)
+			continue
+		}
+		points := make([]trend.TrendPoint, len(records))
+		for i, r := range records {
+			points[i] = trend.TrendPoint{Date: r.Date, Volume: r.MetricValue(*metric), Sentiment: r.Sentiment}
+		}
+
+		results, err := engine.Backtest(points, opts)
+		if err != nil {
+			log.Printf("キーワード %d をスキップしました: %v", id, err)
+			continue
+		}
+		evaluated++
+		log.Printf("キーワード %d: 最良モデル %s (MASE %.3f)", id, results[0].Model, results[0].MASE)
+
+		for i, r := range results {
+			s, ok := board[r.Model]
+			if !ok {
+				s = &standing{model: r.Model}
+				board[r.Model] = s
+			}
+			s.add(r, i == 0)
+		}
+	}
+
+	if evaluated == 0 {
+		log.Fatal("評価できたキーワードがありません")
+	}
+
+	standings := make([]*standing, 0, len(board))
+	for _, s := range board {
+		standings = append(standings, s)
+	}
+	sort.Slice(standings, func(i, j int) bool {
+		a, b := standings[i].mean(standings[i].mase), standings[j].mean(standings[j].mase)
+		if math.IsNaN(b) {
+			return !math.IsNaN(a)
+		}
+		return a < b
+	})
+
+	fmt.Printf("\n%d キーワード、予測期間 %d日 × %d 起点（%s）\n\n", evaluated, opts.Horizon, opts.Folds, *metric)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "順位\tモデル\tMASE\tMAE\tRMSE\tsMAPE%\tMAPE%\t80%被覆率\t勝数\t失敗\t")
+	for i, s := range standings {
+		fmt.Fprintf(w, "%d\t%s\t%.3f\t%.2f\t%.2f\t%.1f\t%.1f\t%.2f\t%d\t%d\t\n",
+			i+1, s.model, s.mean(s.mase), s.mean(s.mae), s.mean(s.rmse), s.mean(s.smape),
+			s.mean(s.mape), s.mean(s.coverage), s.wins, s.failures)
+	}
+	w.Flush()
+}
+
+// standing はモデルごとのキーワード横断の成績です
+type standing struct {
+	model                                  string
+	mase, mae, rmse, smape, mape, coverage []float64
+	wins, failures                         int
+}
+
+// add はキーワード 1 件分の結果を加えます。未定義の指標 (NaN) は平均に含めません
+func (s *standing) add(r trend.BacktestResult, best bool) {
+	if r.Err != "" {
+		s.failures++
+		return
+	}
+	if best {
+		s.wins++
+	}
+	appendFinite(&s.mase, r.MASE)
+	appendFinite(&s.mae, r.MAE)
+	appendFinite(&s.rmse, r.RMSE)
+	appendFinite(&s.smape, r.SMAPE)
+	appendFinite(&s.mape, r.MAPE)
+	for _, c := range r.Coverage {
+		if c.Level == 0.8 {
+			appendFinite(&s.coverage, c.Coverage)
+		}
+	}
+}
+
+// mean は値の平均を返します。値がない場合は NaN です
+func (s *standing) mean(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// appendFinite は有限の値だけを追加します
+func appendFinite(values *[]float64, v float64) {
+	if !math.IsNaN(v) && !math.IsInf(v, 0) {
+		*values = append(*values, v)
+	}
+}
+
+// splitModels はカンマ区切りのモデル名を分割します
+func splitModels(list string) []string {
+	var names []string
+	for _, m := range strings.Split(list, ",") {
+		if m = strings.TrimSpace(m); m != "" {
+			names = append(names, m)
+		}
+	}
+	return names
+}
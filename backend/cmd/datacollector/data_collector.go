@@ -53,9 +53,9 @@ func main() {
 			continue
 		}
 		
-		// キーワードIDを設定
-		for i := range posts {
-			posts[i].KeywordID = keyword.ID
+		// キーワードIDを設定して social_posts に保存
+		if _, err := collector.StoreSocialMediaPosts(ctx, keyword.ID, posts); err != nil {
+			log.Printf("SNS投稿保存エラー: %v", err)
 		}
 		
 		// ブログデータの収集
@@ -9,6 +9,7 @@ import (
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
+	"github.com/trendscout/backend/internal/migrate"
 )
 
 func main() {
@@ -29,7 +30,7 @@ func main() {
 		dbUser, dbPassword, dbHost, dbName)
 
 	// データベース接続
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 	defer cancel()
 
 	config, err := pgxpool.ParseConfig(connString)
@@ -49,19 +50,26 @@ func main() {
 	}
 	log.Println("PostgreSQLに接続しました")
 
-	// 初期化スクリプトの読み込み
-	schemaFile := "configs/sql/init-scripts/01-schema.sql"
-	schemaSQL, err := os.ReadFile(schemaFile)
+	// マイグレーションの適用
+	migrator, err := migrate.NewPostgres(pgPool)
 	if err != nil {
-		log.Fatalf("スキーマファイル読み込みエラー: %v", err)
+		log.Fatalf("マイグレーション読み込みエラー: %v", err)
 	}
+	applied, err := migrator.Up(ctx, false)
+	if err != nil {
+		log.Fatalf("マイグレーション適用エラー: %v", err)
+	}
+	log.Printf("マイグレーションを%d件適用しました", len(applied))
 
-	// スキーマの適用
-	_, err = pgPool.Exec(ctx, string(schemaSQL))
+	// 開発用シードデータの投入
+	seedSQL, err := os.ReadFile("configs/sql/seed-dev.sql")
 	if err != nil {
-		log.Fatalf("スキーマ適用エラー: %v", err)
+		log.Fatalf("シードファイル読み込みエラー: %v", err)
+	}
+	if _, err := pgPool.Exec(ctx, string(seedSQL)); err != nil {
+		log.Fatalf("シード適用エラー: %v", err)
 	}
-	log.Println("スキーマを正常に適用しました")
+	log.Println("開発用シードデータを投入しました")
 
 	// ユーザーテーブルの確認
 	var count int
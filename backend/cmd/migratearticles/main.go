@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/trendscout/backend/internal/models"
+)
+
+// images コレクションに記事として保存されていた旧データを blog_articles に移行します
+//
+//	go run ./cmd/migratearticles -dry-run   # 変更内容の確認のみ
+//	go run ./cmd/migratearticles            # 移行を実行
+func main() {
+	dryRun := flag.Bool("dry-run", false, "変更を書き込まずに件数のみ表示する")
+	flag.Parse()
+
+	// 環境変数の読み込み
+	if err := godotenv.Load(".env.local"); err != nil {
+		if err := godotenv.Load(); err != nil {
+			log.Println("Warning: .env file not found, using environment variables")
+		}
+	}
+
+	if err := models.InitDatabases(); err != nil {
+		log.Fatalf("データベース初期化エラー: %v", err)
+	}
+	defer models.CloseDatabases()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	if *dryRun {
+		log.Println("ドライラン: データベースへの書き込みは行いません")
+	}
+
+	stats, err := models.MigrateLegacyArticleImages(ctx, *dryRun)
+	if err != nil {
+		log.Fatalf("移行エラー: %v", err)
+	}
+
+	log.Printf("走査: %d件", stats.Scanned)
+	log.Printf("blog_articles へ移行: %d件", stats.ArticlesCreated)
+	log.Printf("画像として保持: %d件", stats.ImagesKept)
+	log.Printf("プレースホルダー画像の削除: %d件", stats.ImagesRemoved)
+	log.Printf("解析できずスキップ: %d件", stats.Skipped)
+}
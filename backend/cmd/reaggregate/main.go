@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/trendscout/backend/internal/models"
+	"github.com/trendscout/backend/internal/scraper"
+	"github.com/trendscout/backend/internal/timezone"
+)
+
+// 保存済みの収集アイテムから現在の集計パイプラインでトレンドレコードを再構築します
+// 日付は収集タイムゾーン（COLLECTION_TIMEZONE）の日として解釈されます
+//
+//	go run ./cmd/reaggregate -keyword-id 3 -from 2024-01-01 -to 2024-03-31
+//	go run ./cmd/reaggregate -from 2024-01-01   # 全キーワード、今日まで
+func main() {
+	keywordID := flag.Int("keyword-id", 0, "再集計するキーワードID（0 の場合は全キーワード）")
+	fromStr := flag.String("from", "", "開始日 (YYYY-MM-DD)")
+	toStr := flag.String("to", "", "終了日 (YYYY-MM-DD、省略時は今日)")
+	flag.Parse()
+
+	// 環境変数の読み込み（COLLECTION_TIMEZONE は最初の参照時に固定されるため、日付の解釈より前に行う）
+	if err := godotenv.Load(".env.local"); err != nil {
+		if err := godotenv.Load(); err != nil {
+			log.Println("Warning: .env file not found, using environment variables")
+		}
+	}
+
+	if *fromStr == "" {
+		log.Fatal("-from を指定してください")
+	}
+	from, err := time.ParseInLocation("2006-01-02", *fromStr, timezone.Collection())
+	if err != nil {
+		log.Fatalf("開始日の形式が不正です: %v", err)
+	}
+	to := timezone.CollectionDay(time.Now())
+	if *toStr != "" {
+		if to, err = time.ParseInLocation("2006-01-02", *toStr, timezone.Collection()); err != nil {
+			log.Fatalf("終了日の形式が不正です: %v", err)
+		}
+	}
+	if to.Before(from) {
+		log.Fatal("終了日は開始日以降を指定してください")
+	}
+
+	if err := models.InitDatabases(); err != nil {
+		log.Fatalf("データベース初期化エラー: %v", err)
+	}
+	defer models.CloseDatabases()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	ids := []int{*keywordID}
+	if *keywordID == 0 {
+		keywords, err := models.GetAllKeywords(ctx)
+		if err != nil {
+			log.Fatalf("キーワード取得エラー: %v", err)
+		}
+		ids = ids[:0]
+		for _, k := range keywords {
+			ids = append(ids, k.ID)
+		}
+	}
+
+	log.Printf("パイプライン v%d で %s から %s までを再集計します", scraper.PipelineVersion,
+		from.Format("2006-01-02"), to.Format("2006-01-02"))
+
+	service := scraper.NewService()
+	failed := 0
+	for _, id := range ids {
+		report, err := service.Reaggregate(ctx, id, from, to)
+		if err != nil {
+			log.Printf("キーワード %d の再集計エラー: %v", id, err)
+			failed++
+			continue
+		}
+		log.Printf("キーワード %d: アイテム %d件（集計対象 %d件）、再構築 %d日、削除 %d日",
+			id, report.ItemsRescored, report.ItemsRelevant, len(report.DaysRebuilt), len(report.DaysRemoved))
+	}
+
+	if failed > 0 {
+		log.Fatalf("%d件のキーワードで再集計に失敗しました", failed)
+	}
+}
@@ -194,10 +194,11 @@ func createTrendData(ctx context.Context, keywordIDs []int) error {
 			// 既にデータが存在するか確認する方法がないため、トレンドレコードを直接作成
 			// ランダムな値を生成
 			volume := rand.Intn(1000) + 100
+			engagement := float64(volume) * (1 + rand.Float64()*4) // 1件あたり1.0〜5.0のエンゲージメント
 			sentiment := (rand.Float64() * 2) - 1 // -1.0から1.0の範囲
 
 			// トレンドレコード作成
-			record, err := models.CreateTrendRecord(ctx, keywordID, date, volume, sentiment)
+			record, err := models.CreateTrendRecord(ctx, keywordID, date, volume, engagement, sentiment, 0)
 			if err != nil {
 				log.Printf("トレンドレコード作成エラー: %v", err)
 				continue
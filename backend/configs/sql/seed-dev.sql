@@ -0,0 +1,4 @@
+-- 管理者ユーザー追加（開発用）
+INSERT INTO users (email, password_hash) 
+VALUES ('admin@example.com', '$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy') -- パスワード: password
+ON CONFLICT (email) DO NOTHING;
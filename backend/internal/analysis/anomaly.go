@@ -0,0 +1,235 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+)
+
+// Anomaly detection methods
+const (
+	MethodMAD = "mad" // robust z-score of the decomposition residuals
+	MethodIQR = "iqr" // interquartile range fences, for short series
+)
+
+// Kinds of anomalies
+const (
+	AnomalySpike = "spike"
+	AnomalyDrop  = "drop"
+)
+
+// Anomaly severities, by how far the score exceeds the threshold
+const (
+	SeverityLow    = "low"
+	SeverityMedium = "medium" // at least 1.5 times the threshold
+	SeverityHigh   = "high"   // at least twice the threshold
+)
+
+// Defaults of anomaly detection
+const (
+	// DefaultAnomalyThreshold is the robust z-score above which a residual is
+	// an anomaly, the usual cutoff for the modified z-score
+	DefaultAnomalyThreshold = 3.5
+	// DefaultIQRMultiplier places the fences of the IQR method at 1.5
+	// interquartile ranges beyond the quartiles
+	DefaultIQRMultiplier = 1.5
+	// MinDecompositionPoints is the shortest series decomposed; shorter
+	// series use the IQR method
+	MinDecompositionPoints = 14
+)
+
+// IsSeverity reports whether severity is an anomaly severity
+func IsSeverity(severity string) bool {
+	return severity == SeverityLow || severity == SeverityMedium || severity == SeverityHigh
+}
+
+// minIQRPoints is the shortest series the quartiles are estimated from
+const minIQRPoints = 5
+
+// AnomalyOptions configures anomaly detection
+type AnomalyOptions struct {
+	Period        int     // seasonal period; none when below 2
+	Threshold     float64 // robust z-score threshold; DefaultAnomalyThreshold when zero
+	IQRMultiplier float64 // IQR fence multiplier; DefaultIQRMultiplier when zero
+}
+
+// Anomaly is a point that departs from what the rest of the series implies
+type Anomaly struct {
+	Index    int
+	Value    float64
+	Expected float64 // trend plus seasonal component, or the median with the IQR method
+	Score    float64 // robust z-score, or interquartile ranges beyond the fence; negative for drops
+	Kind     string  // AnomalySpike or AnomalyDrop
+	Severity string
+	Method   string // MethodMAD or MethodIQR
+}
+
+// Decomposition splits a series into trend, seasonal and residual components
+// that add up to the values
+type Decomposition struct {
+	Trend    []float64
+	Seasonal []float64
+	Residual []float64
+}
+
+// Decompose splits a series with a centered moving median as the trend and
+// the median detrended value of each phase of the period as the seasonal
+// component. Medians keep a single spike from leaking into the trend and the
+// seasonal pattern, so it stays in the residual. Without a period the trend
+// window is DefaultMinSize and the seasonal component is zero.
+func Decompose(values []float64, period int) *Decomposition {
+	n := len(values)
+	d := &Decomposition{
+		Trend:    make([]float64, n),
+		Seasonal: make([]float64, n),
+		Residual: make([]float64, n),
+	}
+
+	// An odd window centers on the point; it is truncated at the ends
+	window := DefaultMinSize
+	if period > 1 {
+		window = period | 1
+	}
+	half := window / 2
+	buf := make([]float64, 0, window)
+	for t := range values {
+		buf = append(buf[:0], values[max(0, t-half):min(n, t+half+1)]...)
+		d.Trend[t] = median(buf)
+	}
+
+	if period > 1 && n >= 2*period {
+		phases := make([][]float64, period)
+		for t, v := range values {
+			phases[t%period] = append(phases[t%period], v-d.Trend[t])
+		}
+		seasonal := make([]float64, period)
+		mean := 0.0
+		for p := range phases {
+			seasonal[p] = median(phases[p])
+			mean += seasonal[p]
+		}
+		mean /= float64(period)
+		for t := range values {
+			d.Seasonal[t] = seasonal[t%period] - mean
+		}
+	}
+
+	for t, v := range values {
+		d.Residual[t] = v - d.Trend[t] - d.Seasonal[t]
+	}
+	return d
+}
+
+// DetectAnomalies flags spikes and drops. Series of at least
+// MinDecompositionPoints are decomposed and residuals with a robust z-score
+// beyond the threshold are anomalies; shorter series fall back to the
+// interquartile range fences of the values.
+func DetectAnomalies(values []float64, opts AnomalyOptions) []Anomaly {
+	if opts.Threshold <= 0 {
+		opts.Threshold = DefaultAnomalyThreshold
+	}
+	if opts.IQRMultiplier <= 0 {
+		opts.IQRMultiplier = DefaultIQRMultiplier
+	}
+
+	if len(values) < max(MinDecompositionPoints, 2*opts.Period) {
+		return iqrAnomalies(values, opts.IQRMultiplier)
+	}
+	return madAnomalies(values, opts)
+}
+
+// madAnomalies flags the residuals of the decomposition whose modified
+// z-score exceeds the threshold
+func madAnomalies(values []float64, opts AnomalyOptions) []Anomaly {
+	d := Decompose(values, opts.Period)
+
+	center := median(append([]float64(nil), d.Residual...))
+	deviations := make([]float64, len(values))
+	meanDeviation := 0.0
+	for i, r := range d.Residual {
+		deviations[i] = math.Abs(r - center)
+		meanDeviation += deviations[i]
+	}
+	meanDeviation /= float64(len(values))
+
+	// 1.4826 scales the median absolute deviation to a standard deviation.
+	// When most residuals are equal, as in sparse counts, the MAD is zero and
+	// the mean absolute deviation, scaled by √(π/2), takes its place.
+	scale := 1.4826 * median(deviations)
+	if scale == 0 {
+		scale = math.Sqrt(math.Pi/2) * meanDeviation
+	}
+	if scale == 0 {
+		return nil
+	}
+
+	var anomalies []Anomaly
+	for i, r := range d.Residual {
+		score := (r - center) / scale
+		if math.Abs(score) < opts.Threshold {
+			continue
+		}
+		anomalies = append(anomalies, newAnomaly(i, values[i], d.Trend[i]+d.Seasonal[i]+center, score, opts.Threshold, MethodMAD))
+	}
+	return anomalies
+}
+
+// iqrAnomalies flags values beyond the interquartile range fences. The score
+// is the distance beyond the nearer quartile in interquartile ranges.
+func iqrAnomalies(values []float64, multiplier float64) []Anomaly {
+	if len(values) < minIQRPoints {
+		return nil
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	q1, q3 := quantile(sorted, 0.25), quantile(sorted, 0.75)
+	iqr := q3 - q1
+	if iqr == 0 {
+		return nil
+	}
+	expected := quantile(sorted, 0.5)
+
+	var anomalies []Anomaly
+	for i, v := range values {
+		var score float64
+		switch {
+		case v > q3:
+			score = (v - q3) / iqr
+		case v < q1:
+			score = (v - q1) / iqr
+		}
+		if math.Abs(score) < multiplier {
+			continue
+		}
+		anomalies = append(anomalies, newAnomaly(i, v, expected, score, multiplier, MethodIQR))
+	}
+	return anomalies
+}
+
+// newAnomaly classifies a point scoring beyond the threshold
+func newAnomaly(index int, value, expected, score, threshold float64, method string) Anomaly {
+	a := Anomaly{Index: index, Value: value, Expected: expected, Score: score, Kind: AnomalySpike, Method: method}
+	if score < 0 {
+		a.Kind = AnomalyDrop
+	}
+	switch ratio := math.Abs(score) / threshold; {
+	case ratio >= 2:
+		a.Severity = SeverityHigh
+	case ratio >= 1.5:
+		a.Severity = SeverityMedium
+	default:
+		a.Severity = SeverityLow
+	}
+	return a
+}
+
+// quantile returns the q-quantile of sorted values, interpolating linearly
+// between the closest ranks
+func quantile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	lower := int(pos)
+	if lower+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	frac := pos - float64(lower)
+	return sorted[lower] + frac*(sorted[lower+1]-sorted[lower])
+}
@@ -0,0 +1,59 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+)
+
+// weekly returns a series with a weekly pattern around level plus noise
+func weekly(n int, level float64, seed int64) []float64 {
+	pattern := []float64{0, 2, 4, 6, 4, -6, -10}
+	base := make([]float64, n)
+	for i := range base {
+		base[i] = level + pattern[i%7]
+	}
+	return noisy(base, 1.5, seed)
+}
+
+func TestDetectAnomaliesSpikeAndDrop(t *testing.T) {
+	values := weekly(70, 40, 3)
+	values[30] += 25
+	values[55] -= 20
+
+	anomalies := DetectAnomalies(values, AnomalyOptions{Period: 7})
+	found := map[int]Anomaly{}
+	for _, a := range anomalies {
+		found[a.Index] = a
+	}
+	if a, ok := found[30]; !ok || a.Kind != AnomalySpike || a.Severity != SeverityHigh || a.Method != MethodMAD {
+		t.Errorf("spike at 30 = %+v, want a high severity spike", found[30])
+	}
+	if a, ok := found[55]; !ok || a.Kind != AnomalyDrop {
+		t.Errorf("drop at 55 = %+v, want a drop", found[55])
+	}
+	if math.Abs(found[30].Expected-(values[30]-25)) > 5 {
+		t.Errorf("expected value at spike = %f, want about %f", found[30].Expected, values[30]-25)
+	}
+	// The weekly pattern itself is not anomalous
+	if len(anomalies) > 4 {
+		t.Errorf("%d anomalies, want the spike and drop with few false positives: %+v", len(anomalies), anomalies)
+	}
+}
+
+func TestDetectAnomaliesShortSeriesUsesIQR(t *testing.T) {
+	values := []float64{10, 12, 11, 9, 10, 13, 11, 40, 10}
+	anomalies := DetectAnomalies(values, AnomalyOptions{Period: 7})
+	if len(anomalies) != 1 || anomalies[0].Index != 7 || anomalies[0].Method != MethodIQR {
+		t.Fatalf("anomalies = %+v, want one IQR anomaly at 7", anomalies)
+	}
+}
+
+func TestDetectAnomaliesFlatSeries(t *testing.T) {
+	values := make([]float64, 30)
+	for i := range values {
+		values[i] = 5
+	}
+	if anomalies := DetectAnomalies(values, AnomalyOptions{Period: 7}); len(anomalies) != 0 {
+		t.Errorf("anomalies = %+v, want none", anomalies)
+	}
+}
@@ -0,0 +1,318 @@
+// Package analysis detects structural changes in time series
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Changepoint detection methods
+const (
+	MethodPELT   = "pelt"   // exact optimal segmentation with pruning
+	MethodBinSeg = "binseg" // greedy binary segmentation
+)
+
+// DefaultMinSize is the default shortest segment, a week so that weekly
+// seasonality is not taken for changes
+const DefaultMinSize = 7
+
+// Options configures changepoint detection
+type Options struct {
+	Method  string  // MethodPELT (default) or MethodBinSeg
+	MinSize int     // shortest segment; DefaultMinSize when zero
+	Penalty float64 // cost of a changepoint in units of the noise variance; 3·ln(n) when zero
+	Period  int     // seasonal period removed before detection; none when below 2
+}
+
+// Segment is a regime between changepoints with the least-squares line of
+// its seasonally adjusted values
+type Segment struct {
+	Start     int // first index
+	End       int // index after the last
+	Intercept float64
+	Slope     float64 // change per step
+	Mean      float64
+}
+
+// Len returns the number of points in the segment
+func (s Segment) Len() int {
+	return s.End - s.Start
+}
+
+// ValueAt returns the fitted value of the segment at index i
+func (s Segment) ValueAt(i int) float64 {
+	return s.Intercept + s.Slope*float64(i-s.Start)
+}
+
+// Result is a segmentation of a series
+type Result struct {
+	Changepoints []int     // indices where a new segment starts, ascending
+	Segments     []Segment // in order, covering the series
+	Penalty      float64   // penalty used, in units of the noise variance
+	NoiseStdDev  float64   // robust estimate of the noise standard deviation
+}
+
+// IsMethod reports whether method is a supported detection method
+func IsMethod(method string) bool {
+	return method == MethodPELT || method == MethodBinSeg
+}
+
+// DetectChangepoints segments a series into regimes with their own level and
+// slope. The cost of a segment is the squared error of its least-squares line;
+// a changepoint is kept when it lowers the cost by more than the penalty.
+func DetectChangepoints(values []float64, opts Options) (*Result, error) {
+	if opts.Method == "" {
+		opts.Method = MethodPELT
+	}
+	if !IsMethod(opts.Method) {
+		return nil, fmt.Errorf("unknown changepoint method %q", opts.Method)
+	}
+	if opts.MinSize == 0 {
+		opts.MinSize = DefaultMinSize
+	}
+	if opts.MinSize < 3 {
+		return nil, fmt.Errorf("minimum segment size must be at least 3")
+	}
+	if opts.Penalty < 0 {
+		return nil, fmt.Errorf("penalty must not be negative")
+	}
+
+	n := len(values)
+	if n == 0 {
+		return &Result{}, nil
+	}
+	if opts.Penalty == 0 {
+		opts.Penalty = 3 * math.Log(float64(n))
+	}
+
+	if opts.Period > 1 {
+		values = Deseasonalize(values, opts.Period)
+	}
+
+	sigma := noiseStdDev(values)
+	result := &Result{Penalty: opts.Penalty, NoiseStdDev: sigma}
+	if n >= 2*opts.MinSize {
+		cost := newSegmentCost(values)
+		// Costs are squared errors, so the penalty scales with the noise variance
+		beta := opts.Penalty * math.Max(sigma*sigma, 1e-12)
+		if opts.Method == MethodBinSeg {
+			result.Changepoints = binarySegmentation(cost, 0, n, opts.MinSize, beta)
+			sort.Ints(result.Changepoints)
+		} else {
+			result.Changepoints = pelt(cost, n, opts.MinSize, beta)
+		}
+	}
+
+	bounds := append(append([]int{0}, result.Changepoints...), n)
+	for i := 0; i+1 < len(bounds); i++ {
+		result.Segments = append(result.Segments, fitSegment(values, bounds[i], bounds[i+1]))
+	}
+	return result, nil
+}
+
+// pelt finds the optimal segmentation with the pruned exact linear time method
+func pelt(cost *segmentCost, n, minSize int, beta float64) []int {
+	best := make([]float64, n+1) // optimal cost of values[:t]
+	prev := make([]int, n+1)     // start of the last segment in that optimum
+	best[0] = -beta
+	candidates := []int{0}
+
+	for t := minSize; t <= n; t++ {
+		if s := t - minSize; s >= minSize {
+			candidates = append(candidates, s)
+		}
+
+		best[t] = math.Inf(1)
+		for _, s := range candidates {
+			if c := best[s] + cost.sse(s, t) + beta; c < best[t] {
+				best[t], prev[t] = c, s
+			}
+		}
+
+		// A start that cannot beat the optimum now never will
+		kept := candidates[:0]
+		for _, s := range candidates {
+			if best[s]+cost.sse(s, t) <= best[t] {
+				kept = append(kept, s)
+			}
+		}
+		candidates = kept
+	}
+
+	var changepoints []int
+	for t := prev[n]; t > 0; t = prev[t] {
+		changepoints = append([]int{t}, changepoints...)
+	}
+	return changepoints
+}
+
+// binarySegmentation splits values[start:end] at the point that lowers the
+// cost most, recursing while a split is worth the penalty
+func binarySegmentation(cost *segmentCost, start, end, minSize int, beta float64) []int {
+	if end-start < 2*minSize {
+		return nil
+	}
+
+	whole := cost.sse(start, end)
+	split, gain := -1, beta
+	for k := start + minSize; k <= end-minSize; k++ {
+		if g := whole - cost.sse(start, k) - cost.sse(k, end); g > gain {
+			split, gain = k, g
+		}
+	}
+	if split < 0 {
+		return nil
+	}
+
+	changepoints := binarySegmentation(cost, start, split, minSize, beta)
+	changepoints = append(changepoints, split)
+	return append(changepoints, binarySegmentation(cost, split, end, minSize, beta)...)
+}
+
+// segmentCost computes the squared error of the least-squares line of any
+// segment in constant time from prefix sums
+type segmentCost struct {
+	t, tt, y, yy, ty []float64
+}
+
+func newSegmentCost(values []float64) *segmentCost {
+	n := len(values)
+	c := &segmentCost{
+		t:  make([]float64, n+1),
+		tt: make([]float64, n+1),
+		y:  make([]float64, n+1),
+		yy: make([]float64, n+1),
+		ty: make([]float64, n+1),
+	}
+	for i, v := range values {
+		x := float64(i)
+		c.t[i+1] = c.t[i] + x
+		c.tt[i+1] = c.tt[i] + x*x
+		c.y[i+1] = c.y[i] + v
+		c.yy[i+1] = c.yy[i] + v*v
+		c.ty[i+1] = c.ty[i] + x*v
+	}
+	return c
+}
+
+// sse returns the squared error of the line fitted to values[start:end]
+func (c *segmentCost) sse(start, end int) float64 {
+	n := float64(end - start)
+	st := c.t[end] - c.t[start]
+	stt := c.tt[end] - c.tt[start]
+	sy := c.y[end] - c.y[start]
+	syy := c.yy[end] - c.yy[start]
+	sty := c.ty[end] - c.ty[start]
+
+	sxx := stt - st*st/n
+	sxy := sty - st*sy/n
+	sse := syy - sy*sy/n
+	if sxx > 0 {
+		sse -= sxy * sxy / sxx
+	}
+	return math.Max(sse, 0)
+}
+
+// fitSegment fits the least-squares line of values[start:end]
+func fitSegment(values []float64, start, end int) Segment {
+	segment := Segment{Start: start, End: end}
+	n := float64(end - start)
+	meanX := (n - 1) / 2
+	for _, v := range values[start:end] {
+		segment.Mean += v
+	}
+	segment.Mean /= n
+
+	sxx, sxy := 0.0, 0.0
+	for i, v := range values[start:end] {
+		dx := float64(i) - meanX
+		sxx += dx * dx
+		sxy += dx * (v - segment.Mean)
+	}
+	if sxx > 0 {
+		segment.Slope = sxy / sxx
+	}
+	segment.Intercept = segment.Mean - segment.Slope*meanX
+	return segment
+}
+
+// noiseStdDev estimates the noise standard deviation from the median
+// absolute second difference, which neither level shifts nor trends inflate.
+// Series that are mostly flat fall back to the root mean square.
+func noiseStdDev(values []float64) float64 {
+	if len(values) < 3 {
+		return 0
+	}
+	diffs := make([]float64, len(values)-2)
+	squares := 0.0
+	for i := range diffs {
+		diffs[i] = math.Abs(values[i+2] - 2*values[i+1] + values[i])
+		squares += diffs[i] * diffs[i]
+	}
+	// The second difference of white noise has variance 6σ²; 1.4826 scales
+	// the median absolute deviation to a standard deviation
+	if sigma := 1.4826 * median(diffs) / math.Sqrt(6); sigma > 0 {
+		return sigma
+	}
+	return math.Sqrt(squares / float64(len(diffs)) / 6)
+}
+
+// Deseasonalize subtracts the average deviation of each phase of the period
+// from a centered moving average, so that a recurring pattern such as the
+// weekly cycle is not taken for changes. Series shorter than two periods are
+// returned unchanged.
+func Deseasonalize(values []float64, period int) []float64 {
+	n := len(values)
+	if period < 2 || n < 2*period {
+		return values
+	}
+
+	// Centered moving average over one period; even periods take half of
+	// the two outermost values
+	half := period / 2
+	sums := make([]float64, period)
+	counts := make([]int, period)
+	for t := half; t+half < n; t++ {
+		trend := 0.0
+		for _, v := range values[t-half : t+half+1] {
+			trend += v
+		}
+		if period%2 == 0 {
+			trend -= (values[t-half] + values[t+half]) / 2
+		}
+		trend /= float64(period)
+
+		sums[t%period] += values[t] - trend
+		counts[t%period]++
+	}
+
+	seasonal := make([]float64, period)
+	mean := 0.0
+	for p := range seasonal {
+		if counts[p] > 0 {
+			seasonal[p] = sums[p] / float64(counts[p])
+		}
+		mean += seasonal[p]
+	}
+	mean /= float64(period)
+
+	adjusted := make([]float64, n)
+	for t, v := range values {
+		adjusted[t] = v - (seasonal[t%period] - mean)
+	}
+	return adjusted
+}
+
+// median returns the median of values, reordering them
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Float64s(values)
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2
+	}
+	return values[mid]
+}
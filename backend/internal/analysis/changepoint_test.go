@@ -0,0 +1,100 @@
+package analysis
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// noisy returns values with Gaussian noise added
+func noisy(values []float64, sigma float64, seed int64) []float64 {
+	rng := rand.New(rand.NewSource(seed))
+	out := make([]float64, len(values))
+	for i, v := range values {
+		out[i] = v + rng.NormFloat64()*sigma
+	}
+	return out
+}
+
+func TestDetectLevelShift(t *testing.T) {
+	base := make([]float64, 80)
+	for i := range base {
+		base[i] = 20
+		if i >= 50 {
+			base[i] = 60
+		}
+	}
+	values := noisy(base, 3, 1)
+
+	for _, method := range []string{MethodPELT, MethodBinSeg} {
+		result, err := DetectChangepoints(values, Options{Method: method})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Changepoints) != 1 || math.Abs(float64(result.Changepoints[0]-50)) > 1 {
+			t.Errorf("%s: changepoints = %v, want [50]", method, result.Changepoints)
+			continue
+		}
+		last := result.Segments[len(result.Segments)-1]
+		if math.Abs(last.Mean-60) > 3 {
+			t.Errorf("%s: last segment mean = %f, want about 60", method, last.Mean)
+		}
+	}
+}
+
+func TestDetectSlopeChange(t *testing.T) {
+	// Flat for 60 days, then taking off by 3 a day
+	base := make([]float64, 90)
+	for i := range base {
+		base[i] = 30
+		if i >= 60 {
+			base[i] = 30 + 3*float64(i-60)
+		}
+	}
+	result, err := DetectChangepoints(noisy(base, 2, 2), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Changepoints) == 0 {
+		t.Fatal("no changepoint detected")
+	}
+	last := result.Segments[len(result.Segments)-1]
+	if last.Start < 55 || last.Start > 65 || math.Abs(last.Slope-3) > 0.5 {
+		t.Errorf("last segment = %+v, want to start about 60 with slope 3", last)
+	}
+}
+
+func TestNoChangepointsInNoise(t *testing.T) {
+	base := make([]float64, 120)
+	for i := range base {
+		base[i] = 50
+	}
+	result, err := DetectChangepoints(noisy(base, 5, 3), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Changepoints) != 0 {
+		t.Errorf("changepoints = %v, want none", result.Changepoints)
+	}
+}
+
+func TestCUSUMSignalsShift(t *testing.T) {
+	base := make([]float64, 60)
+	for i := range base {
+		base[i] = 100
+		if i >= 40 {
+			base[i] = 70
+		}
+	}
+	alarms, _, err := CUSUM(noisy(base, 4, 4), CUSUMOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alarms) != 1 {
+		t.Fatalf("alarms = %+v, want one", alarms)
+	}
+	a := alarms[0]
+	if a.Direction != DirectionDown || a.Index < 40 || a.Index > 45 || a.Start < 38 || a.Start > 41 {
+		t.Errorf("alarm = %+v, want a downward shift starting at 40", a)
+	}
+}
@@ -0,0 +1,122 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+)
+
+// CUSUM defaults, in units of the reference standard deviation
+const (
+	DefaultCUSUMDrift     = 0.5 // allowance k; shifts below it are ignored
+	DefaultCUSUMThreshold = 8   // decision interval h; higher than usual as the reference is estimated
+	DefaultCUSUMWarmup    = 28  // points that set the reference level
+)
+
+// Directions of a detected change
+const (
+	DirectionUp   = "up"
+	DirectionDown = "down"
+)
+
+// CUSUMOptions configures the CUSUM detector
+type CUSUMOptions struct {
+	Warmup    int     // points after each restart that set the reference; DefaultCUSUMWarmup when zero
+	Drift     float64 // DefaultCUSUMDrift when zero
+	Threshold float64 // DefaultCUSUMThreshold when zero
+}
+
+// CUSUMAlarm is a level shift signalled by the CUSUM detector
+type CUSUMAlarm struct {
+	Index     int     // point at which the alarm was raised
+	Start     int     // estimated start of the shift: where the statistic last left zero
+	Direction string  // DirectionUp or DirectionDown
+	Reference float64 // level before the shift
+}
+
+// CUSUMState is the detector state after the last point, for online use
+type CUSUMState struct {
+	Reference float64 // reference level; NaN while warming up
+	StdDev    float64
+	Upper     float64 // upward statistic, in standard deviations
+	Lower     float64 // downward statistic, in standard deviations
+	Threshold float64
+}
+
+// CUSUM runs Page's two-sided CUSUM over values as if they arrived one at a
+// time. The reference level and spread are estimated from the first Warmup
+// points and again from the Warmup points after every alarm.
+func CUSUM(values []float64, opts CUSUMOptions) ([]CUSUMAlarm, CUSUMState, error) {
+	if opts.Warmup == 0 {
+		opts.Warmup = DefaultCUSUMWarmup
+	}
+	if opts.Drift == 0 {
+		opts.Drift = DefaultCUSUMDrift
+	}
+	if opts.Threshold == 0 {
+		opts.Threshold = DefaultCUSUMThreshold
+	}
+	if opts.Warmup < 2 || opts.Drift < 0 || opts.Threshold <= 0 {
+		return nil, CUSUMState{}, fmt.Errorf("invalid CUSUM options")
+	}
+
+	var alarms []CUSUMAlarm
+	state := CUSUMState{Reference: math.NaN(), Threshold: opts.Threshold}
+	restart := 0
+	upStart, downStart := 0, 0
+	for i, v := range values {
+		if i-restart < opts.Warmup {
+			if i-restart == opts.Warmup-1 {
+				state.Reference, state.StdDev = referenceLevel(values[restart : i+1])
+			}
+			continue
+		}
+
+		z := (v - state.Reference) / state.StdDev
+		if state.Upper == 0 {
+			upStart = i
+		}
+		if state.Lower == 0 {
+			downStart = i
+		}
+		state.Upper = math.Max(0, state.Upper+z-opts.Drift)
+		state.Lower = math.Max(0, state.Lower-z-opts.Drift)
+
+		var alarm *CUSUMAlarm
+		if state.Upper > opts.Threshold {
+			alarm = &CUSUMAlarm{Index: i, Start: upStart, Direction: DirectionUp, Reference: state.Reference}
+		} else if state.Lower > opts.Threshold {
+			alarm = &CUSUMAlarm{Index: i, Start: downStart, Direction: DirectionDown, Reference: state.Reference}
+		}
+		if alarm != nil {
+			alarms = append(alarms, *alarm)
+			// Warm up again on the points after the alarm
+			restart = i + 1
+			state = CUSUMState{Reference: math.NaN(), Threshold: opts.Threshold}
+		}
+	}
+
+	return alarms, state, nil
+}
+
+// referenceLevel returns the mean and standard deviation of a warm-up window.
+// A flat window gets a tenth of its level, and at least 1, so that shifts
+// remain measurable.
+func referenceLevel(window []float64) (float64, float64) {
+	mean := 0.0
+	for _, v := range window {
+		mean += v
+	}
+	mean /= float64(len(window))
+
+	variance := 0.0
+	for _, v := range window {
+		variance += (v - mean) * (v - mean)
+	}
+	variance /= float64(len(window) - 1)
+
+	sd := math.Sqrt(variance)
+	if sd == 0 {
+		sd = math.Max(math.Abs(mean)*0.1, 1)
+	}
+	return mean, sd
+}
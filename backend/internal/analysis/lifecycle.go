@@ -0,0 +1,198 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Lifecycle stages of a trend
+const (
+	StageEmerging     = "emerging"     // growing steadily
+	StageAccelerating = "accelerating" // growing faster every week
+	StagePeaking      = "peaking"      // near its peak with growth fading
+	StageDeclining    = "declining"    // falling, or well below its peak
+	StageFad          = "fad"          // a short burst that has already faded
+	StageStable       = "stable"       // no significant change
+	StageUnknown      = "insufficient_data"
+)
+
+// Thresholds of the lifecycle classifier. Rates are weekly log changes, so
+// they mean the same for a keyword with 5 items a day and one with 500.
+const (
+	// MinLifecycleDays is the shortest daily series classified
+	MinLifecycleDays = 14
+	// growthThreshold is the weekly growth, about 10%, that counts as a change
+	growthThreshold = 0.095
+	// accelerationThreshold is the rise in weekly growth that counts as acceleration
+	accelerationThreshold = 0.1
+	// nearPeakDistance is how far below its peak a trend may be and still peak
+	nearPeakDistance = 0.2
+	// fadMaxDays is the longest a fad stays above half of its peak height
+	fadMaxDays = 21
+	// fadProminence is how many times the baseline a fad's peak rises above it
+	fadProminence = 1.0
+)
+
+// Lifecycle is the lifecycle stage of a daily series with the features it was
+// classified from
+type Lifecycle struct {
+	Stage            string
+	GrowthRate       float64 // average weekly change over the last two weeks, as a fraction
+	Acceleration     float64 // change in weekly log growth from the two weeks before
+	DistanceFromPeak float64 // fraction the last week's mean is below the peak
+	PeakIndex        int     // day of the peak of the weekly moving average
+	PeakWidth        int     // days around the peak above half of its height over the baseline
+	Persistence      int     // consecutive weeks, up to the last, changing in the same direction
+	Reason           string
+}
+
+// ClassifyLifecycle classifies a daily series as emerging, accelerating,
+// peaking, declining, fad or stable. Growth and acceleration compare the means
+// of the last whole weeks, so weekly seasonality cancels out; changes smaller
+// than twice their standard error under the series noise are not significant.
+func ClassifyLifecycle(values []float64) *Lifecycle {
+	n := len(values)
+	if n < MinLifecycleDays {
+		return &Lifecycle{Stage: StageUnknown, PeakIndex: -1, Reason: fmt.Sprintf("fewer than %d days of data", MinLifecycleDays)}
+	}
+
+	smoothed := movingAverage(values, DefaultMinSize)
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(n)
+	// Logs of weekly means are offset by a fraction of the mean level so that
+	// weeks without data do not make growth infinite
+	offset := 0.05*math.Abs(mean) + 1e-9
+
+	weeks := weeklyMeans(values)
+	logs := make([]float64, len(weeks))
+	for i, w := range weeks {
+		logs[i] = math.Log(math.Max(w, 0) + offset)
+	}
+	last := len(weeks) - 1
+
+	// Growth is averaged over the last two weeks, and acceleration compares it
+	// with the two weeks before, when there are enough weeks
+	span := 1
+	if last >= 2 {
+		span = 2
+	}
+	l := &Lifecycle{}
+	growth := (logs[last] - logs[last-span]) / float64(span)
+	l.GrowthRate = math.Exp(growth) - 1
+	if last >= 2*span {
+		l.Acceleration = growth - (logs[last-span]-logs[last-2*span])/float64(span)
+	}
+
+	// Standard error of the log of a weekly mean under the noise, from which
+	// growth and acceleration thresholds are scaled
+	noise := noiseStdDev(Deseasonalize(values, DefaultMinSize))
+	stderr := noise / math.Sqrt(DefaultMinSize) / (math.Abs(weeks[last]+weeks[last-span])/2 + offset)
+	threshold := math.Max(growthThreshold, 2*math.Sqrt(2)*stderr/float64(span))
+	accelThreshold := math.Max(accelerationThreshold, 2*math.Sqrt(6)*stderr/float64(span))
+	direction := 0
+	if growth >= threshold {
+		direction = 1
+	} else if growth <= -threshold {
+		direction = -1
+	}
+
+	// Weeks in a row changing like the last ones
+	weekThreshold := math.Max(growthThreshold, 2*math.Sqrt(2)*stderr)
+	for i := last; i >= 1; i-- {
+		change := logs[i] - logs[i-1]
+		if direction == 0 && math.Abs(change) >= weekThreshold || direction != 0 && change*float64(direction) <= 0 {
+			break
+		}
+		l.Persistence++
+	}
+
+	// Peak of the smoothed series, and its width at half height over the baseline
+	l.PeakIndex = 0
+	for i, v := range smoothed {
+		if v > smoothed[l.PeakIndex] {
+			l.PeakIndex = i
+		}
+	}
+	peak := smoothed[l.PeakIndex]
+	sorted := append([]float64(nil), smoothed...)
+	sort.Float64s(sorted)
+	baseline := math.Max(quantile(sorted, 0.1), 0)
+	half := baseline + (peak-baseline)/2
+	start, end := l.PeakIndex, l.PeakIndex
+	for start > 0 && smoothed[start-1] >= half {
+		start--
+	}
+	for end+1 < n && smoothed[end+1] >= half {
+		end++
+	}
+	l.PeakWidth = end - start + 1
+	if peak > 0 {
+		l.DistanceFromPeak = math.Max(0, 1-weeks[last]/peak)
+	}
+
+	prominent := peak-baseline > fadProminence*baseline+2*noise
+	switch {
+	case prominent && l.PeakWidth <= fadMaxDays && end < n-1 && smoothed[n-1] < half && direction <= 0:
+		l.Stage = StageFad
+		l.Reason = fmt.Sprintf("rose to %.1f times its baseline for %d days and has fallen back", peak/math.Max(baseline, offset), l.PeakWidth)
+	case direction > 0 && l.Acceleration >= accelThreshold:
+		l.Stage = StageAccelerating
+		l.Reason = fmt.Sprintf("weekly growth of %.0f%% is speeding up", 100*l.GrowthRate)
+	case direction > 0 && l.DistanceFromPeak < nearPeakDistance && l.Acceleration <= -accelThreshold:
+		l.Stage = StagePeaking
+		l.Reason = fmt.Sprintf("near its peak with weekly growth slowing to %.0f%%", 100*l.GrowthRate)
+	case direction > 0:
+		l.Stage = StageEmerging
+		l.Reason = fmt.Sprintf("growing %.0f%% a week for %d weeks", 100*l.GrowthRate, l.Persistence)
+	case direction < 0 && l.DistanceFromPeak < nearPeakDistance/2:
+		l.Stage = StagePeaking
+		l.Reason = fmt.Sprintf("turning down from its peak, %.0f%% this week", 100*l.GrowthRate)
+	case direction < 0:
+		l.Stage = StageDeclining
+		l.Reason = fmt.Sprintf("falling %.0f%% a week, %.0f%% below its peak", -100*l.GrowthRate, 100*l.DistanceFromPeak)
+	case prominent && l.DistanceFromPeak < nearPeakDistance && n-1-l.PeakIndex < 2*DefaultMinSize:
+		l.Stage = StagePeaking
+		l.Reason = "levelling off at its peak"
+	case prominent && l.DistanceFromPeak >= 0.5:
+		l.Stage = StageDeclining
+		l.Reason = fmt.Sprintf("settled %.0f%% below its peak", 100*l.DistanceFromPeak)
+	default:
+		l.Stage = StageStable
+		l.Reason = "no significant change in the last weeks"
+	}
+	return l
+}
+
+// weeklyMeans returns the means of the whole weeks ending at the last value,
+// oldest first; days before the first whole week are left out
+func weeklyMeans(values []float64) []float64 {
+	weeks := make([]float64, len(values)/DefaultMinSize)
+	offset := len(values) % DefaultMinSize
+	for i := range weeks {
+		for _, v := range values[offset+i*DefaultMinSize : offset+(i+1)*DefaultMinSize] {
+			weeks[i] += v
+		}
+		weeks[i] /= DefaultMinSize
+	}
+	return weeks
+}
+
+// movingAverage returns the centered moving average over an odd window,
+// truncated at the ends
+func movingAverage(values []float64, window int) []float64 {
+	n := len(values)
+	half := window / 2
+	smoothed := make([]float64, n)
+	for t := range values {
+		lo, hi := max(0, t-half), min(n, t+half+1)
+		for _, v := range values[lo:hi] {
+			smoothed[t] += v
+		}
+		smoothed[t] /= float64(hi - lo)
+	}
+	return smoothed
+}
@@ -0,0 +1,41 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+)
+
+func TestClassifyLifecycle(t *testing.T) {
+	cases := []struct {
+		name  string
+		shape func(i int) float64
+		want  string
+	}{
+		{"stable", func(i int) float64 { return 50 }, StageStable},
+		{"emerging", func(i int) float64 { return 20 + 1.2*float64(max(0, i-40)) }, StageEmerging},
+		{"accelerating", func(i int) float64 { return 10 * math.Exp(0.01*float64(i)+0.0009*float64(i*i)) }, StageAccelerating},
+		{"declining", func(i int) float64 { return 30 + 70*math.Exp(-0.03*float64(max(0, i-20))) }, StageDeclining},
+		{"fad", func(i int) float64 { return 20 + 100*math.Exp(-math.Pow(float64(i-50)/4, 2)) }, StageFad},
+		{"peaking", func(i int) float64 { return 20 + 80/(1+math.Exp(-0.15*float64(i-60))) }, StagePeaking},
+	}
+
+	for _, c := range cases {
+		// The same shape at two scales must get the same stage
+		for _, scale := range []float64{0.2, 20} {
+			base := make([]float64, 90)
+			for i := range base {
+				base[i] = scale * c.shape(i)
+			}
+			l := ClassifyLifecycle(noisy(base, 0.03*scale*c.shape(0), 7))
+			if l.Stage != c.want {
+				t.Errorf("%s at scale %g: stage = %s (%s), want %s", c.name, scale, l.Stage, l.Reason, c.want)
+			}
+		}
+	}
+}
+
+func TestClassifyLifecycleShortSeries(t *testing.T) {
+	if l := ClassifyLifecycle(make([]float64, 10)); l.Stage != StageUnknown {
+		t.Errorf("stage = %s, want %s", l.Stage, StageUnknown)
+	}
+}
@@ -0,0 +1,118 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/trendscout/backend/internal/models"
+)
+
+// BlueskyConnector はBlueskyの投稿検索APIから投稿を取得します
+type BlueskyConnector struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewBlueskyConnector は指定AppView向けのコネクタを生成します
+func NewBlueskyConnector(baseURL string, client *http.Client) *BlueskyConnector {
+	return &BlueskyConnector{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  client,
+	}
+}
+
+// Name はプラットフォーム名を返します
+func (c *BlueskyConnector) Name() string {
+	return "bluesky"
+}
+
+// blueskySearchResponse は app.bsky.feed.searchPosts のレスポンスです
+type blueskySearchResponse struct {
+	Posts []struct {
+		URI    string `json:"uri"`
+		Author struct {
+			Handle string `json:"handle"`
+		} `json:"author"`
+		Record struct {
+			Text      string    `json:"text"`
+			CreatedAt time.Time `json:"createdAt"`
+			Langs     []string  `json:"langs"`
+		} `json:"record"`
+		Embed struct {
+			Images []struct {
+				Fullsize string `json:"fullsize"`
+			} `json:"images"`
+		} `json:"embed"`
+		LikeCount   int `json:"likeCount"`
+		ReplyCount  int `json:"replyCount"`
+		RepostCount int `json:"repostCount"`
+	} `json:"posts"`
+}
+
+// FetchPosts はキーワードで最新の投稿を検索します
+func (c *BlueskyConnector) FetchPosts(ctx context.Context, keyword string) ([]models.SocialMediaPost, error) {
+	params := url.Values{}
+	params.Set("q", keyword)
+	params.Set("sort", "latest")
+	params.Set("limit", "50")
+	endpoint := fmt.Sprintf("%s/xrpc/app.bsky.feed.searchPosts?%s", c.baseURL, params.Encode())
+
+	var result blueskySearchResponse
+	if err := getJSON(ctx, c.client, endpoint, &result); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	posts := make([]models.SocialMediaPost, 0, len(result.Posts))
+	for _, p := range result.Posts {
+		post := models.SocialMediaPost{
+			Platform:     c.Name(),
+			PostID:       p.URI,
+			Username:     p.Author.Handle,
+			Caption:      p.Record.Text,
+			URL:          blueskyWebURL(p.Author.Handle, p.URI),
+			Tags:         extractHashtags(p.Record.Text),
+			LikeCount:    p.LikeCount,
+			CommentCount: p.ReplyCount,
+			RepostCount:  p.RepostCount,
+			PostDate:     p.Record.CreatedAt,
+			CreatedAt:    now,
+		}
+		if len(p.Record.Langs) > 0 {
+			post.Language = p.Record.Langs[0]
+		} else {
+			post.Language = models.DetectLanguage(post.Caption)
+		}
+		if len(p.Embed.Images) > 0 {
+			post.ImageURL = p.Embed.Images[0].Fullsize
+		}
+
+		posts = append(posts, post)
+	}
+
+	return posts, nil
+}
+
+// blueskyWebURL はAT URI (at://did/app.bsky.feed.post/rkey) をWeb URLに変換します
+func blueskyWebURL(handle, uri string) string {
+	idx := strings.LastIndex(uri, "/")
+	if idx < 0 || handle == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://bsky.app/profile/%s/post/%s", handle, uri[idx+1:])
+}
+
+// extractHashtags は本文中の #タグ を小文字で抽出します
+func extractHashtags(text string) []string {
+	var tags []string
+	for _, field := range strings.Fields(text) {
+		if strings.HasPrefix(field, "#") && len(field) > 1 {
+			tags = append(tags, strings.ToLower(strings.TrimRight(field[1:], ".,!?")))
+		}
+	}
+	return tags
+}
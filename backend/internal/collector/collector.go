@@ -4,36 +4,70 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/trendscout/backend/internal/models"
 )
 
-// CollectSocialMediaData はSNSからデータを収集します
+// CollectSocialMediaData は設定済みの全コネクタからSNSデータを収集します
 func CollectSocialMediaData(ctx context.Context, keyword string) ([]models.SocialMediaPost, error) {
 	log.Printf("キーワード '%s' のSNSデータを収集中...", keyword)
-	
-	// ここに実際のAPI呼び出しコードを実装
-	// テスト用のダミーデータを返す
-	posts := []models.SocialMediaPost{
-		{
-			ID:           fmt.Sprintf("post_%d", time.Now().Unix()),
-			KeywordID:    0, // 呼び出し元で設定される
-			Platform:     "instagram",
-			PostID:       fmt.Sprintf("ig_%d", time.Now().Unix()),
-			Username:     "fashion_user",
-			Caption:      fmt.Sprintf("Latest %s trends are amazing! #fashion #trend", keyword),
-			ImageURL:     "https://example.com/image1.jpg",
-			LikeCount:    100,
-			CommentCount: 20,
-			PostDate:     time.Now(),
-			CreatedAt:    time.Now(),
-		},
+	return CollectFromConnectors(ctx, DefaultConnectors(), keyword)
+}
+
+// CollectFromConnectors は各コネクタから投稿を収集します
+// 一部のコネクタが失敗しても他の結果は返し、全て失敗した場合のみエラーを返します
+func CollectFromConnectors(ctx context.Context, connectors []Connector, keyword string) ([]models.SocialMediaPost, error) {
+	posts, fetchErrs := CollectFromConnectorsWithStatus(ctx, connectors, keyword)
+
+	var errs []string
+	for _, connector := range connectors {
+		if err := fetchErrs[connector.Name()]; err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", connector.Name(), err))
+		}
 	}
-	
+
+	if len(connectors) > 0 && len(errs) == len(connectors) {
+		return nil, fmt.Errorf("all social connectors failed: %s", strings.Join(errs, "; "))
+	}
+
 	return posts, nil
 }
 
+// CollectFromConnectorsWithStatus は各コネクタから投稿を収集し、コネクタ名ごとの取得結果
+// （成功時は nil）も返します。フィードの障害と投稿数の減少を区別するために使います
+func CollectFromConnectorsWithStatus(ctx context.Context, connectors []Connector, keyword string) ([]models.SocialMediaPost, map[string]error) {
+	var posts []models.SocialMediaPost
+	fetchErrs := make(map[string]error, len(connectors))
+
+	for _, connector := range connectors {
+		fetched, err := connector.FetchPosts(ctx, keyword)
+		fetchErrs[connector.Name()] = err
+		if err != nil {
+			log.Printf("%s からの投稿取得に失敗しました: %v", connector.Name(), err)
+			continue
+		}
+		log.Printf("%s から %d 件の投稿を取得しました", connector.Name(), len(fetched))
+		posts = append(posts, fetched...)
+	}
+
+	return posts, fetchErrs
+}
+
+// StoreSocialMediaPosts は投稿をキーワードに紐づけて social_posts に保存します
+func StoreSocialMediaPosts(ctx context.Context, keywordID int, posts []models.SocialMediaPost) (int, error) {
+	stored := 0
+	for i := range posts {
+		posts[i].KeywordID = keywordID
+		if err := models.UpsertSocialMediaPost(ctx, &posts[i]); err != nil {
+			return stored, fmt.Errorf("failed to store %s post %s: %w", posts[i].Platform, posts[i].PostID, err)
+		}
+		stored++
+	}
+	return stored, nil
+}
+
 // CollectBlogData はブログからデータを収集します
 func CollectBlogData(ctx context.Context, keyword string) ([]models.BlogArticle, error) {
 	log.Printf("キーワード '%s' のブログデータを収集中...", keyword)
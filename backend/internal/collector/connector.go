@@ -0,0 +1,115 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/trendscout/backend/internal/models"
+)
+
+// Connector はSNSプラットフォームからキーワードに関連する投稿を取得します
+type Connector interface {
+	// Name はプラットフォーム名を返します（social_posts の platform に保存されます）
+	Name() string
+	// FetchPosts はキーワードに一致する最新の投稿を取得します
+	FetchPosts(ctx context.Context, keyword string) ([]models.SocialMediaPost, error)
+}
+
+// 各コネクタの既定エンドポイント
+const (
+	DefaultMastodonBaseURL = "https://mastodon.social"
+	DefaultBlueskyBaseURL  = "https://public.api.bsky.app"
+	DefaultRedditBaseURL   = "https://www.reddit.com"
+)
+
+// userAgent はAPI呼び出し時に送信するUser-Agentです（RedditはUAなしのリクエストを拒否します）
+const userAgent = "TrendScout/1.0 (+https://github.com/trendscout)"
+
+// DefaultConnectors は環境変数の設定に従ってコネクタを生成します
+//
+// SOCIAL_CONNECTORS でカンマ区切りの有効プラットフォームを指定でき（既定は全て）、
+// MASTODON_BASE_URL / BLUESKY_BASE_URL / REDDIT_BASE_URL でエンドポイントを差し替えられます。
+func DefaultConnectors() []Connector {
+	client := &http.Client{Timeout: 20 * time.Second}
+
+	enabled := map[string]bool{"mastodon": true, "bluesky": true, "reddit": true}
+	if list := os.Getenv("SOCIAL_CONNECTORS"); list != "" {
+		enabled = make(map[string]bool)
+		for _, name := range strings.Split(list, ",") {
+			enabled[strings.ToLower(strings.TrimSpace(name))] = true
+		}
+	}
+
+	var connectors []Connector
+	if enabled["mastodon"] {
+		connectors = append(connectors, NewMastodonConnector(envOrDefault("MASTODON_BASE_URL", DefaultMastodonBaseURL), client))
+	}
+	if enabled["bluesky"] {
+		connectors = append(connectors, NewBlueskyConnector(envOrDefault("BLUESKY_BASE_URL", DefaultBlueskyBaseURL), client))
+	}
+	if enabled["reddit"] {
+		connectors = append(connectors, NewRedditConnector(envOrDefault("REDDIT_BASE_URL", DefaultRedditBaseURL), client))
+	}
+
+	return connectors
+}
+
+// envOrDefault は環境変数が未設定の場合に既定値を返します
+func envOrDefault(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// getJSON はGETリクエストを送信し、レスポンスJSONを out にデコードします
+func getJSON(ctx context.Context, client *http.Client, endpoint string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d from %s: %s", resp.StatusCode, endpoint, strings.TrimSpace(string(body)))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// stripHTML はHTMLタグを除去し、エンティティをデコードします
+func stripHTML(s string) string {
+	s = strings.ReplaceAll(s, "</p><p>", "\n")
+	s = htmlTagPattern.ReplaceAllString(s, "")
+	return strings.TrimSpace(html.UnescapeString(s))
+}
+
+// toHashtag はキーワードをハッシュタグ形式（空白・記号なし）に変換します
+func toHashtag(keyword string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(keyword) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,164 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/trendscout/backend/internal/models"
+)
+
+func newMockServer(t *testing.T, path, body string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("User-Agent") == "" {
+			http.Error(w, "missing user agent", http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestMastodonConnectorFetchPosts(t *testing.T) {
+	srv := newMockServer(t, "/api/v1/timelines/tag/y2kfashion", `[{
+		"id": "111",
+		"created_at": "2026-09-03T10:00:00.000Z",
+		"url": "https://mastodon.example/@anna/111",
+		"content": "<p>Loving the &amp; <a href=\"#\">#Y2KFashion</a> revival</p>",
+		"favourites_count": 12,
+		"replies_count": 3,
+		"reblogs_count": 4,
+		"account": {"acct": "anna"},
+		"media_attachments": [{"type": "image", "url": "https://files.example/a.jpg"}],
+		"tags": [{"name": "Y2KFashion"}]
+	}]`)
+
+	posts, err := NewMastodonConnector(srv.URL, srv.Client()).FetchPosts(context.Background(), "Y2K Fashion")
+	if err != nil {
+		t.Fatalf("FetchPosts returned error: %v", err)
+	}
+	if len(posts) != 1 {
+		t.Fatalf("expected 1 post, got %d", len(posts))
+	}
+
+	p := posts[0]
+	if p.Platform != "mastodon" || p.PostID != "111" || p.Username != "anna" {
+		t.Fatalf("unexpected identity fields: %+v", p)
+	}
+	if p.Caption != "Loving the & #Y2KFashion revival" {
+		t.Fatalf("unexpected caption: %q", p.Caption)
+	}
+	if p.LikeCount != 12 || p.CommentCount != 3 || p.RepostCount != 4 {
+		t.Fatalf("unexpected engagement counts: %+v", p)
+	}
+	if p.ImageURL != "https://files.example/a.jpg" {
+		t.Fatalf("unexpected image url: %q", p.ImageURL)
+	}
+	if p.PostDate.IsZero() {
+		t.Fatal("post date was not parsed")
+	}
+}
+
+func TestBlueskyConnectorFetchPosts(t *testing.T) {
+	srv := newMockServer(t, "/xrpc/app.bsky.feed.searchPosts", `{"posts": [{
+		"uri": "at://did:plc:abc/app.bsky.feed.post/3kxyz",
+		"author": {"handle": "style.bsky.social"},
+		"record": {"text": "Quiet luxury is everywhere #quietluxury", "createdAt": "2026-09-03T08:30:00Z"},
+		"embed": {"images": [{"fullsize": "https://cdn.example/full.jpg"}]},
+		"likeCount": 40,
+		"replyCount": 5,
+		"repostCount": 2
+	}]}`)
+
+	posts, err := NewBlueskyConnector(srv.URL, srv.Client()).FetchPosts(context.Background(), "quiet luxury")
+	if err != nil {
+		t.Fatalf("FetchPosts returned error: %v", err)
+	}
+	if len(posts) != 1 {
+		t.Fatalf("expected 1 post, got %d", len(posts))
+	}
+
+	p := posts[0]
+	if p.URL != "https://bsky.app/profile/style.bsky.social/post/3kxyz" {
+		t.Fatalf("unexpected web url: %q", p.URL)
+	}
+	if p.LikeCount != 40 || p.CommentCount != 5 || p.RepostCount != 2 {
+		t.Fatalf("unexpected engagement counts: %+v", p)
+	}
+	if len(p.Tags) != 1 || p.Tags[0] != "quietluxury" {
+		t.Fatalf("unexpected tags: %v", p.Tags)
+	}
+}
+
+func TestRedditConnectorFetchPosts(t *testing.T) {
+	srv := newMockServer(t, "/search.json", `{"data": {"children": [{"data": {
+		"id": "abc12",
+		"title": "Is gorpcore over?",
+		"selftext": "Seeing fewer shell jackets lately",
+		"author": "threads_guy",
+		"subreddit": "malefashionadvice",
+		"permalink": "/r/malefashionadvice/comments/abc12/",
+		"url": "https://i.redd.it/x.jpg",
+		"post_hint": "image",
+		"score": 250,
+		"num_comments": 81,
+		"created_utc": 1788426000
+	}}]}}`)
+
+	posts, err := NewRedditConnector(srv.URL, srv.Client()).FetchPosts(context.Background(), "gorpcore")
+	if err != nil {
+		t.Fatalf("FetchPosts returned error: %v", err)
+	}
+	if len(posts) != 1 {
+		t.Fatalf("expected 1 post, got %d", len(posts))
+	}
+
+	p := posts[0]
+	if p.LikeCount != 250 || p.CommentCount != 81 {
+		t.Fatalf("unexpected engagement counts: %+v", p)
+	}
+	if p.ImageURL != "https://i.redd.it/x.jpg" {
+		t.Fatalf("unexpected image url: %q", p.ImageURL)
+	}
+	if p.PostDate.Unix() != 1788426000 {
+		t.Fatalf("unexpected post date: %v", p.PostDate)
+	}
+}
+
+type failingConnector struct{ name string }
+
+func (c failingConnector) Name() string { return c.name }
+
+func (c failingConnector) FetchPosts(context.Context, string) ([]models.SocialMediaPost, error) {
+	return nil, errors.New("unavailable")
+}
+
+func TestCollectFromConnectorsPartialFailure(t *testing.T) {
+	srv := newMockServer(t, "/api/v1/timelines/tag/denim", `[{"id": "1", "created_at": "2026-09-03T10:00:00Z", "content": "denim"}]`)
+
+	connectors := []Connector{
+		failingConnector{name: "reddit"},
+		NewMastodonConnector(srv.URL, srv.Client()),
+	}
+
+	posts, err := CollectFromConnectors(context.Background(), connectors, "denim")
+	if err != nil {
+		t.Fatalf("expected partial failure to be tolerated, got %v", err)
+	}
+	if len(posts) != 1 {
+		t.Fatalf("expected 1 post, got %d", len(posts))
+	}
+
+	if _, err := CollectFromConnectors(context.Background(), connectors[:1], "denim"); err == nil {
+		t.Fatal("expected an error when every connector fails")
+	}
+}
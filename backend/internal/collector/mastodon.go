@@ -0,0 +1,105 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/trendscout/backend/internal/models"
+)
+
+// MastodonConnector はMastodonのハッシュタグタイムラインから投稿を取得します
+type MastodonConnector struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewMastodonConnector は指定インスタンス向けのコネクタを生成します
+func NewMastodonConnector(baseURL string, client *http.Client) *MastodonConnector {
+	return &MastodonConnector{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  client,
+	}
+}
+
+// Name はプラットフォーム名を返します
+func (c *MastodonConnector) Name() string {
+	return "mastodon"
+}
+
+// mastodonStatus は /api/v1/timelines/tag のレスポンス要素です
+type mastodonStatus struct {
+	ID              string    `json:"id"`
+	CreatedAt       time.Time `json:"created_at"`
+	URL             string    `json:"url"`
+	Content         string    `json:"content"`
+	Language        string    `json:"language"`
+	FavouritesCount int       `json:"favourites_count"`
+	RepliesCount    int       `json:"replies_count"`
+	ReblogsCount    int       `json:"reblogs_count"`
+	Account         struct {
+		Acct string `json:"acct"`
+	} `json:"account"`
+	MediaAttachments []struct {
+		Type string `json:"type"`
+		URL  string `json:"url"`
+	} `json:"media_attachments"`
+	Tags []struct {
+		Name string `json:"name"`
+	} `json:"tags"`
+}
+
+// FetchPosts はキーワードをハッシュタグとして公開タイムラインを検索します
+func (c *MastodonConnector) FetchPosts(ctx context.Context, keyword string) ([]models.SocialMediaPost, error) {
+	tag := toHashtag(keyword)
+	if tag == "" {
+		return nil, fmt.Errorf("keyword %q cannot be used as a hashtag", keyword)
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v1/timelines/tag/%s?limit=40", c.baseURL, url.PathEscape(tag))
+
+	var statuses []mastodonStatus
+	if err := getJSON(ctx, c.client, endpoint, &statuses); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	posts := make([]models.SocialMediaPost, 0, len(statuses))
+	for _, status := range statuses {
+		post := models.SocialMediaPost{
+			Platform:     c.Name(),
+			PostID:       status.ID,
+			Username:     status.Account.Acct,
+			Caption:      stripHTML(status.Content),
+			URL:          status.URL,
+			LikeCount:    status.FavouritesCount,
+			CommentCount: status.RepliesCount,
+			RepostCount:  status.ReblogsCount,
+			Language:     status.Language,
+			PostDate:     status.CreatedAt,
+			CreatedAt:    now,
+		}
+
+		if post.Language == "" {
+			post.Language = models.DetectLanguage(post.Caption)
+		}
+
+		for _, media := range status.MediaAttachments {
+			if media.Type == "image" {
+				post.ImageURL = media.URL
+				break
+			}
+		}
+
+		for _, t := range status.Tags {
+			post.Tags = append(post.Tags, strings.ToLower(t.Name))
+		}
+
+		posts = append(posts, post)
+	}
+
+	return posts, nil
+}
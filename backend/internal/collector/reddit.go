@@ -0,0 +1,111 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/trendscout/backend/internal/models"
+)
+
+// RedditConnector はRedditのJSONリスティング（検索結果）から投稿を取得します
+type RedditConnector struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewRedditConnector は指定エンドポイント向けのコネクタを生成します
+func NewRedditConnector(baseURL string, client *http.Client) *RedditConnector {
+	return &RedditConnector{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  client,
+	}
+}
+
+// Name はプラットフォーム名を返します
+func (c *RedditConnector) Name() string {
+	return "reddit"
+}
+
+// redditListing は /search.json のレスポンスです
+type redditListing struct {
+	Data struct {
+		Children []struct {
+			Data struct {
+				ID          string  `json:"id"`
+				Title       string  `json:"title"`
+				Selftext    string  `json:"selftext"`
+				Author      string  `json:"author"`
+				Subreddit   string  `json:"subreddit"`
+				Permalink   string  `json:"permalink"`
+				URL         string  `json:"url"`
+				PostHint    string  `json:"post_hint"`
+				Score       int     `json:"score"`
+				NumComments int     `json:"num_comments"`
+				CreatedUTC  float64 `json:"created_utc"`
+				Preview     struct {
+					Images []struct {
+						Source struct {
+							URL string `json:"url"`
+						} `json:"source"`
+					} `json:"images"`
+				} `json:"preview"`
+			} `json:"data"`
+		} `json:"children"`
+	} `json:"data"`
+}
+
+// FetchPosts はキーワードで直近1週間の新着投稿を検索します
+func (c *RedditConnector) FetchPosts(ctx context.Context, keyword string) ([]models.SocialMediaPost, error) {
+	params := url.Values{}
+	params.Set("q", keyword)
+	params.Set("sort", "new")
+	params.Set("t", "week")
+	params.Set("limit", "50")
+	endpoint := fmt.Sprintf("%s/search.json?%s", c.baseURL, params.Encode())
+
+	var listing redditListing
+	if err := getJSON(ctx, c.client, endpoint, &listing); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	posts := make([]models.SocialMediaPost, 0, len(listing.Data.Children))
+	for _, child := range listing.Data.Children {
+		d := child.Data
+		caption := d.Title
+		if d.Selftext != "" {
+			caption += "\n" + d.Selftext
+		}
+
+		post := models.SocialMediaPost{
+			Platform:     c.Name(),
+			PostID:       d.ID,
+			Username:     d.Author,
+			Caption:      caption,
+			URL:          c.baseURL + d.Permalink,
+			LikeCount:    d.Score,
+			CommentCount: d.NumComments,
+			Language:     models.DetectLanguage(caption),
+			PostDate:     time.Unix(int64(d.CreatedUTC), 0).UTC(),
+			CreatedAt:    now,
+		}
+		if d.Subreddit != "" {
+			post.Tags = []string{strings.ToLower(d.Subreddit)}
+		}
+		if d.PostHint == "image" {
+			post.ImageURL = d.URL
+		} else if len(d.Preview.Images) > 0 {
+			// プレビューURLはHTMLエスケープされた状態で返されます
+			post.ImageURL = html.UnescapeString(d.Preview.Images[0].Source.URL)
+		}
+
+		posts = append(posts, post)
+	}
+
+	return posts, nil
+}
@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trendscout/backend/internal/auth"
+	"github.com/trendscout/backend/internal/models"
+	"github.com/trendscout/backend/internal/views"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// ContentController handles requests for collected articles, posts and images
+type ContentController struct{}
+
+// NewContentController creates a new content controller
+func NewContentController() *ContentController {
+	return &ContentController{}
+}
+
+// SearchContent handles full-text search over the caller's collected content
+func (c *ContentController) SearchContent(ctx *gin.Context) {
+	// Get authenticated user ID
+	userID, exists := auth.GetUserID(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	query := models.ContentSearchQuery{
+		Text:       strings.TrimSpace(ctx.Query("q")),
+		Sources:    splitList(ctx.Query("source")),
+		Types:      splitList(ctx.Query("type")),
+		Language:   ctx.Query("lang"),
+		Provenance: ctx.Query("provenance"),
+		Limit:      defaultSearchLimit,
+	}
+
+	for _, t := range query.Types {
+		if t != models.ContentTypeArticle && t != models.ContentTypePost && t != models.ContentTypeImage {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid type: must be article, post or image"})
+			return
+		}
+	}
+
+	// Restrict the search to keywords owned by the user
+	keywords, err := models.GetKeywordsForUser(ctx, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get keywords"})
+		return
+	}
+	owned := make(map[int]bool, len(keywords))
+	for _, k := range keywords {
+		owned[k.ID] = true
+	}
+
+	if keywordIDs := splitList(ctx.Query("keyword_id")); len(keywordIDs) > 0 {
+		for _, idStr := range keywordIDs {
+			keywordID, err := strconv.Atoi(idStr)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid keyword_id"})
+				return
+			}
+			if !owned[keywordID] {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "Keyword not found"})
+				return
+			}
+			query.KeywordIDs = append(query.KeywordIDs, keywordID)
+		}
+	} else {
+		for _, k := range keywords {
+			query.KeywordIDs = append(query.KeywordIDs, k.ID)
+		}
+	}
+
+	// Parse date range in the user's time zone; "to" includes the whole day
+	loc := userLocation(ctx, userID)
+	if fromStr := ctx.Query("from"); fromStr != "" {
+		query.From, err = parseDate(fromStr, loc)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date format"})
+			return
+		}
+	}
+	if toStr := ctx.Query("to"); toStr != "" {
+		to, err := parseDate(toStr, loc)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date format"})
+			return
+		}
+		query.To = endOfDay(to)
+	}
+
+	if limitStr := ctx.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxSearchLimit {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit: must be between 1 and 100"})
+			return
+		}
+		query.Limit = limit
+	}
+
+	if cursorStr := ctx.Query("cursor"); cursorStr != "" {
+		query.Cursor, err = models.DecodeContentCursor(cursorStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
+	}
+
+	if len(query.KeywordIDs) == 0 {
+		ctx.JSON(http.StatusOK, views.NewContentSearchResponse(query.Text, nil, nil))
+		return
+	}
+
+	results, next, err := models.SearchContent(ctx, query)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search content"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, views.NewContentSearchResponse(query.Text, results, next))
+}
+
+// splitList splits a comma-separated query parameter, ignoring empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -2,13 +2,15 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"log"
 	"net/http"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/trendscout/backend/internal/auth"
+	"github.com/trendscout/backend/internal/deletion"
 	"github.com/trendscout/backend/internal/models"
 	"github.com/trendscout/backend/internal/scraper"
 	"github.com/trendscout/backend/internal/views"
@@ -16,13 +18,15 @@ import (
 
 // KeywordController handles keyword-related requests
 type KeywordController struct {
-	scraperService *scraper.Service
+	scraperService  *scraper.Service
+	deletionService *deletion.Service
 }
 
 // NewKeywordController creates a new keyword controller
 func NewKeywordController() *KeywordController {
 	return &KeywordController{
-		scraperService: scraper.NewService(),
+		scraperService:  scraper.NewService(),
+		deletionService: deletion.NewService(),
 	}
 }
 
@@ -31,6 +35,13 @@ type KeywordCreateRequest struct {
 	Keyword string `json:"keyword" binding:"required,min=1,max=100"`
 }
 
+// KeywordUpdateRequest represents the request for changing a keyword. With
+// Reaggregate, the stored history is rescored against the new text.
+type KeywordUpdateRequest struct {
+	Keyword     string `json:"keyword" binding:"required,min=1,max=100"`
+	Reaggregate bool   `json:"reaggregate"`
+}
+
 // GetKeywords handles retrieving all keywords for the authenticated user
 func (c *KeywordController) GetKeywords(ctx *gin.Context) {
 	// Get authenticated user ID
@@ -81,127 +92,65 @@ func (c *KeywordController) CreateKeyword(ctx *gin.Context) {
 	ctx.JSON(http.StatusCreated, views.NewKeywordResponse(keyword))
 }
 
-// collectHistoricalData collects historical data for a new keyword
+// backfillDays is how far back historical data is collected for a new keyword
+const backfillDays = 90
+
+// collectHistoricalData collects real historical data for a new keyword
 func (c *KeywordController) collectHistoricalData(keywordID int, keywordText string) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 	defer cancel()
 
-	// Collect data for the past 90 days
-	now := time.Now()
-	startDate := now.AddDate(0, 0, -90)
+	since := time.Now().AddDate(0, 0, -backfillDays)
+	report, err := c.scraperService.BackfillKeyword(ctx, keywordID, keywordText, since)
+	if err != nil {
+		log.Printf("Historical data collection for %q failed: %v", keywordText, err)
+		return
+	}
 
-	// Simulate historical data collection by running multiple scraping sessions
-	// In a real implementation, you might want to use different date ranges or historical APIs
-	for days := 0; days < 90; days += 7 { // Collect data weekly for past 90 days
-		targetDate := startDate.AddDate(0, 0, days)
-		
-		items, err := c.scraperService.ScrapeKeyword(ctx, keywordText)
-		if err != nil {
-			continue // Skip failed collections
-		}
+	log.Printf("Historical data collection for %q stored %d items (%d undated items dropped)",
+		keywordText, report.ItemsStored, report.ItemsUndated)
+}
 
-		// Store items with backdated timestamps
-		if err := c.storeHistoricalItems(ctx, keywordID, items, targetDate); err != nil {
-			continue // Skip failed storage
-		}
+// GetBackfillCoverage handles retrieving the per-day historical collection coverage of a keyword
+func (c *KeywordController) GetBackfillCoverage(ctx *gin.Context) {
+	// Get authenticated user ID
+	userID, exists := auth.GetUserID(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	// Parse keyword ID from URL
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid keyword ID"})
+		return
+	}
 
-		// Rate limiting to avoid overwhelming the sources
-		time.Sleep(2 * time.Second)
+	// Check if keyword exists and belongs to user
+	keyword, err := models.GetKeywordByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get keyword"})
+		return
 	}
-}
 
-// storeHistoricalItems stores scraped items with historical dates
-func (c *KeywordController) storeHistoricalItems(ctx context.Context, keywordID int, items []scraper.ScrapedItem, targetDate time.Time) error {
-	// Group items by simulated date intervals
-	itemsByDate := make(map[time.Time][]scraper.ScrapedItem)
-	
-	// Distribute items across the week leading up to targetDate
-	for i, item := range items {
-		// Distribute items across 7 days before target date
-		dateOffset := i % 7
-		itemDate := time.Date(targetDate.Year(), targetDate.Month(), targetDate.Day()-dateOffset, 0, 0, 0, 0, time.UTC)
-		itemsByDate[itemDate] = append(itemsByDate[itemDate], item)
-	}
-
-	// Store items in MongoDB and update trend records
-	for date, dateItems := range itemsByDate {
-		// Skip future dates
-		if date.After(time.Now()) {
-			continue
-		}
-
-		// Calculate volume and sentiment
-		volume := len(dateItems)
-		sentiment := calculateSentiment(dateItems)
-
-		// Store trend record in PostgreSQL
-		_, err := models.CreateTrendRecord(ctx, keywordID, date, volume, sentiment)
-		if err != nil {
-			continue // Skip duplicate dates
-		}
-
-		// Store items in MongoDB with historical dates
-		for _, item := range dateItems {
-			image := &models.Image{
-				KeywordID: keywordID,
-				ImageURL:  item.ImageURL,
-				Caption:   item.Title + " - " + item.Content,
-				Tags:      item.Tags,
-				FetchedAt: date, // Use historical date instead of current time
-			}
-
-			models.CreateImage(ctx, image) // Ignore errors for historical data
-		}
-	}
-
-	return nil
-}
+	if keyword == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Keyword not found"})
+		return
+	}
 
-// calculateSentiment calculates sentiment from scraped items
-func calculateSentiment(items []scraper.ScrapedItem) float64 {
-	if len(items) == 0 {
-		return 0.5 // neutral
-	}
-	
-	positiveWords := []string{"amazing", "beautiful", "stunning", "gorgeous", "elegant", "chic", "trendy", "stylish", "love", "perfect", "fabulous", "hot", "cool", "awesome"}
-	negativeWords := []string{"ugly", "terrible", "awful", "disappointing", "boring", "outdated", "hate", "worst", "bad", "horrible"}
-	
-	var totalScore float64
-	var count int
-	
-	for _, item := range items {
-		content := strings.ToLower(item.Title + " " + item.Content)
-		var score float64 = 0.5 // neutral baseline
-		
-		for _, word := range positiveWords {
-			if strings.Contains(content, word) {
-				score += 0.1
-			}
-		}
-		
-		for _, word := range negativeWords {
-			if strings.Contains(content, word) {
-				score -= 0.1
-			}
-		}
-		
-		// Clamp score between 0 and 1
-		if score > 1.0 {
-			score = 1.0
-		}
-		if score < 0.0 {
-			score = 0.0
-		}
-		
-		totalScore += score
-		count++
-	}
-	
-	if count == 0 {
-		return 0.5
-	}
-	
-	return totalScore / float64(count)
+	if keyword.UserID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+		return
+	}
+
+	days, err := models.GetBackfillCoverage(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get backfill coverage"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, views.NewBackfillCoverageResponse(id, days))
 }
 
 // UpdateKeyword handles updating a keyword
@@ -239,14 +188,19 @@ func (c *KeywordController) UpdateKeyword(ctx *gin.Context) {
 	}
 
 	// Parse request body
-	var req KeywordCreateRequest
+	var req KeywordUpdateRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Update keyword in database
-	if err := models.UpdateKeyword(ctx, id, req.Keyword); err != nil {
+	// Update keyword in database; a changed text becomes a new version
+	changed, err := models.UpdateKeyword(ctx, id, req.Keyword)
+	if errors.Is(err, models.ErrKeywordConflict) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Keyword already exists"})
+		return
+	}
+	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update keyword"})
 		return
 	}
@@ -258,11 +212,76 @@ func (c *KeywordController) UpdateKeyword(ctx *gin.Context) {
 		return
 	}
 
+	// Rescore the history against the new definition in background
+	reaggregating := changed && req.Reaggregate
+	if reaggregating {
+		go c.reaggregateHistory(id)
+	}
+
 	// Return updated keyword
-	ctx.JSON(http.StatusOK, views.NewKeywordResponse(updatedKeyword))
+	ctx.JSON(http.StatusOK, views.NewKeywordUpdateResponse(updatedKeyword, changed, reaggregating))
+}
+
+// reaggregateHistory rebuilds a keyword's trend records from its stored
+// items under its current definition. Items reach back to the backfill of
+// the keyword's first version.
+func (c *KeywordController) reaggregateHistory(keywordID int) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	versions, err := models.GetKeywordVersions(ctx, keywordID)
+	if err != nil || len(versions) == 0 {
+		log.Printf("Reaggregation of keyword %d skipped: no versions (%v)", keywordID, err)
+		return
+	}
+
+	from := versions[0].EffectiveFrom.AddDate(0, 0, -backfillDays)
+	if _, err := c.scraperService.Reaggregate(ctx, keywordID, from, time.Now()); err != nil {
+		log.Printf("Reaggregation of keyword %d failed: %v", keywordID, err)
+	}
+}
+
+// GetKeywordVersions handles retrieving the definition history of a keyword
+func (c *KeywordController) GetKeywordVersions(ctx *gin.Context) {
+	userID, exists := auth.GetUserID(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid keyword ID"})
+		return
+	}
+
+	keyword, err := models.GetKeywordByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get keyword"})
+		return
+	}
+
+	if keyword == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Keyword not found"})
+		return
+	}
+
+	if keyword.UserID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+		return
+	}
+
+	versions, err := models.GetKeywordVersions(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get keyword versions"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, views.NewKeywordVersionListResponse(id, versions))
 }
 
-// DeleteKeyword handles deleting a keyword
+// DeleteKeyword handles deleting a keyword. The keyword is soft-deleted and
+// can be restored until the grace period ends; its data is purged afterwards.
 func (c *KeywordController) DeleteKeyword(ctx *gin.Context) {
 	// Get authenticated user ID
 	userID, exists := auth.GetUserID(ctx)
@@ -296,12 +315,88 @@ func (c *KeywordController) DeleteKeyword(ctx *gin.Context) {
 		return
 	}
 
-	// Delete keyword from database
-	if err := models.DeleteKeyword(ctx, id); err != nil {
+	// Soft-delete the keyword
+	restoreUntil, err := c.deletionService.Delete(ctx, id)
+	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete keyword"})
 		return
 	}
 
 	// Return success response
-	ctx.JSON(http.StatusOK, gin.H{"message": "Keyword deleted successfully"})
-} 
\ No newline at end of file
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":       "Keyword deleted successfully",
+		"keyword_id":    id,
+		"restore_until": restoreUntil,
+	})
+}
+
+// GetDeletedKeywords handles listing the authenticated user's deleted keywords
+// that can still be restored
+func (c *KeywordController) GetDeletedKeywords(ctx *gin.Context) {
+	userID, exists := auth.GetUserID(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	keywords, err := c.deletionService.DeletedKeywords(ctx, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get deleted keywords"})
+		return
+	}
+
+	response := &views.DeletedKeywordListResponse{Keywords: []*views.DeletedKeywordResponse{}}
+	for _, keyword := range keywords {
+		restoreUntil := c.deletionService.RestoreDeadline(*keyword.DeletedAt)
+		response.Keywords = append(response.Keywords, views.NewDeletedKeywordResponse(keyword, restoreUntil))
+	}
+	response.Count = len(response.Keywords)
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// RestoreKeyword handles restoring a deleted keyword within the grace period
+func (c *KeywordController) RestoreKeyword(ctx *gin.Context) {
+	userID, exists := auth.GetUserID(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid keyword ID"})
+		return
+	}
+
+	keyword, err := models.GetDeletedKeywordByID(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get keyword"})
+		return
+	}
+
+	if keyword == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Deleted keyword not found"})
+		return
+	}
+
+	if keyword.UserID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+		return
+	}
+
+	switch err := c.deletionService.Restore(ctx, id); {
+	case errors.Is(err, deletion.ErrGracePeriodExpired):
+		ctx.JSON(http.StatusGone, gin.H{"error": "Keyword can no longer be restored"})
+		return
+	case errors.Is(err, models.ErrKeywordConflict):
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Keyword has been registered again"})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore keyword"})
+		return
+	}
+
+	keyword.DeletedAt = nil
+	ctx.JSON(http.StatusOK, views.NewKeywordResponse(keyword))
+}
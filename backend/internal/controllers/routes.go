@@ -15,6 +15,8 @@ func SetupRoutes(r *gin.Engine) {
 	keywordController := NewKeywordController()
 	trendController := NewTrendController()
 	dataController := NewDataController()
+	contentController := NewContentController()
+	userController := NewUserController()
 
 	// Initialize auth middleware
 	authMiddleware := auth.NewMiddleware(authService)
@@ -34,21 +36,38 @@ func SetupRoutes(r *gin.Engine) {
 		// Auth routes
 		protected.POST("/auth/logout", authController.Logout)
 
+		// User routes
+		protected.GET("/users/me/preferences", userController.GetPreferences)
+		protected.PUT("/users/me/preferences", userController.UpdatePreferences)
+
 		// Keyword routes
 		protected.GET("/keywords", keywordController.GetKeywords)
 		protected.POST("/keywords", keywordController.CreateKeyword)
 		protected.PUT("/keywords/:id", keywordController.UpdateKeyword)
 		protected.DELETE("/keywords/:id", keywordController.DeleteKeyword)
+		protected.GET("/keywords/deleted", keywordController.GetDeletedKeywords)
+		protected.GET("/keywords/:id/versions", keywordController.GetKeywordVersions)
+		protected.POST("/keywords/:id/restore", keywordController.RestoreKeyword)
+		protected.GET("/keywords/:id/backfill", keywordController.GetBackfillCoverage)
 
 		// Trend routes
 		protected.GET("/trends/", trendController.GetTrendData)
 		protected.POST("/trends/analysis", trendController.GetTrendAnalysis)
 		protected.POST("/trends/prediction", trendController.GetTrendPrediction)
 		protected.POST("/trends/predict", trendController.GetTrendPrediction) // Legacy alias
+		protected.POST("/trends/backtest", trendController.BacktestTrendPrediction)
 		protected.POST("/trends/sentiment", trendController.GetSentimentAnalysis)
 		protected.GET("/trends/comparison", trendController.GetMultiKeywordComparison)
+		protected.GET("/trends/:keyword_id/points/:date/items", trendController.GetTrendPointItems)
+		protected.GET("/trends/:keyword_id/forecasts", trendController.GetForecastOverlay)
+		protected.GET("/trends/:keyword_id/changepoints", trendController.GetChangepoints)
+		protected.GET("/trends/:keyword_id/anomalies", trendController.GetAnomalies)
+		protected.GET("/trends/:keyword_id/peak", trendController.GetPeakEstimate)
 
 		// Data collection routes
 		protected.POST("/data/collect/:id", dataController.CollectKeywordData)
+
+		// Content routes
+		protected.GET("/content/search", contentController.SearchContent)
 	}
 } 
\ No newline at end of file
@@ -2,13 +2,20 @@ package controllers
 
 import (
 	"fmt"
+	"log"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/trendscout/backend/internal/analysis"
 	"github.com/trendscout/backend/internal/auth"
+	"github.com/trendscout/backend/internal/metrics"
 	"github.com/trendscout/backend/internal/models"
+	"github.com/trendscout/backend/internal/prediction"
+	"github.com/trendscout/backend/internal/series"
 	"github.com/trendscout/backend/internal/trend"
 	"github.com/trendscout/backend/internal/views"
 )
@@ -38,6 +45,28 @@ func (c *TrendController) GetTrendData(ctx *gin.Context) {
 	keywordIDStr := ctx.Query("q")
 	fromStr := ctx.Query("from")
 	toStr := ctx.Query("to")
+	sources := splitList(ctx.Query("source"))
+
+	metric, ok := c.parseMetric(ctx, ctx.Query("metric"))
+	if !ok {
+		return
+	}
+
+	granularity := ctx.DefaultQuery("granularity", series.Day)
+	if !series.IsGranularity(granularity) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid granularity (hour, day, week or month)"})
+		return
+	}
+	aggregation := ctx.DefaultQuery("agg", metrics.Aggregation(metric))
+	if !series.IsAggregation(aggregation) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid agg (sum or avg)"})
+		return
+	}
+	fill := ctx.DefaultQuery("fill", series.FillNone)
+	if !series.IsFillPolicy(fill) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid fill (none, zero, null, ffill or interpolate)"})
+		return
+	}
 
 	if keywordIDStr == "" {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "keyword_id (q) parameter is required"})
@@ -55,37 +84,65 @@ func (c *TrendController) GetTrendData(ctx *gin.Context) {
 		return
 	}
 
-	// Parse dates with defaults
+	// Parse dates in the user's time zone with defaults; "to" includes the whole day
+	loc := userLocation(ctx, userID)
 	var startDate, endDate time.Time
 	if fromStr != "" {
-		startDate, err = time.Parse("2006-01-02", fromStr)
+		startDate, err = parseDate(fromStr, loc)
 		if err != nil {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date format"})
 			return
 		}
 	} else {
-		startDate = time.Now().AddDate(0, 0, -30) // Default: 30 days ago
+		startDate = time.Now().In(loc).AddDate(0, 0, -30) // Default: 30 days ago
 	}
 
 	if toStr != "" {
-		endDate, err = time.Parse("2006-01-02", toStr)
+		endDate, err = parseDate(toStr, loc)
 		if err != nil {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date format"})
 			return
 		}
+		endDate = endOfDay(endDate)
 	} else {
-		endDate = time.Now() // Default: today
+		endDate = time.Now().In(loc) // Default: today
 	}
 
-	// Get trend records
-	records, err := models.GetTrendRecords(ctx, keywordID, startDate, endDate)
+	// Get trend records; hourly buckets come from the metric series store
+	var records []models.TrendRecord
+	if granularity == series.Hour {
+		records, err = models.GetHourlyTrendRecords(ctx, keywordID, metric, startDate, endDate, sources)
+	} else {
+		records, err = models.GetMetricTrendRecords(ctx, keywordID, metric, startDate, endDate, sources)
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get trend data"})
 		return
 	}
 
-	// Convert to response format using the helper function
-	response := views.NewTrendRecordListResponse(records)
+	breakdown, err := models.GetTrendRecordSources(ctx, keywordID, startDate, endDate, sources)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get source breakdown"})
+		return
+	}
+
+	versions, err := models.GetKeywordVersions(ctx, keywordID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get keyword versions"})
+		return
+	}
+
+	// Convert to response format, resampling unless raw daily records were requested
+	var response views.TrendRecordListResponse
+	if granularity == series.Day && fill == series.FillNone && aggregation == metrics.Aggregation(metric) {
+		response = views.NewTrendRecordListResponse(records, metric, loc)
+	} else {
+		response = views.NewResampledTrendRecordListResponse(records, metric, granularity, aggregation, fill, startDate, endDate, loc)
+	}
+	response.Source = sources
+	response.Sources = views.NewSourceSeriesResponses(breakdown, metric, loc)
+	response.DefinitionChanges = views.NewDefinitionChangeResponses(versions, granularity, startDate, endDate, loc)
+	response.Lifecycle = views.NewLifecycleResponse(trend.ClassifyLifecycle(toTrendPoints(records, metric)), loc)
 
 	ctx.JSON(http.StatusOK, response)
 }
@@ -95,20 +152,126 @@ type TrendAnalysisRequest struct {
 	KeywordID int    `json:"keyword_id" binding:"required"`
 	StartDate string `json:"start_date" binding:"required"`
 	EndDate   string `json:"end_date" binding:"required"`
+	Metric    string `json:"metric"` // volume (default), engagement, sentiment or any stored metric
+	Source    string `json:"source"` // comma-separated source filter
 }
 
 // TrendPredictionRequest represents the request for trend prediction
 type TrendPredictionRequest struct {
-	KeywordID int `json:"keyword_id" binding:"required"`
-	Days      int `json:"days" binding:"required,min=1,max=60"`
+	KeywordID   int       `json:"keyword_id" binding:"required"`
+	Days        int       `json:"days" binding:"required,min=1,max=60"`
+	Metric      string    `json:"metric"`       // volume (default), engagement, sentiment or any stored metric
+	Source      string    `json:"source"`       // comma-separated source filter
+	Model       string    `json:"model"`        // auto (default), ensemble or a volume model such as hw or arima
+	Levels      []float64 `json:"levels"`       // prediction interval levels in percent (default 80 and 95)
+	FullHistory bool      `json:"full_history"` // train on the whole history instead of the current regime
+}
+
+// TrendBacktestRequest represents the request for forecast backtesting
+type TrendBacktestRequest struct {
+	KeywordID int       `json:"keyword_id" binding:"required"`
+	Horizon   int       `json:"horizon" binding:"omitempty,min=1,max=30"` // days forecast per fold (default 7)
+	Folds     int       `json:"folds" binding:"omitempty,min=1,max=20"`   // forecast origins (default 4)
+	Models    []string  `json:"models"`                                   // volume models to compare (default all)
+	Levels    []float64 `json:"levels"`                                   // prediction interval levels in percent (default 80 and 95)
+	Metric    string    `json:"metric"`                                   // volume (default), engagement, sentiment or any stored metric
+	Source    string    `json:"source"`                                   // comma-separated source filter
 }
 
+// backtestHistoryDays is how much history a backtest request evaluates
+const backtestHistoryDays = 180
+
+// maxIntervalLevels limits the prediction interval levels of a request
+const maxIntervalLevels = 5
+
 // TrendSentimentRequest represents the request for sentiment analysis
 type TrendSentimentRequest struct {
 	KeywordID int `json:"keyword_id" binding:"required"`
 	Period    int `json:"period" binding:"required,min=1,max=90"`
 }
 
+// GetTrendPointItems handles drilling down from a trend data point to the
+// articles, posts and images counted in it. The date is a day in the user's
+// time zone, as shown on the chart, or an RFC3339 hour with granularity=hour.
+func (c *TrendController) GetTrendPointItems(ctx *gin.Context) {
+	userID, exists := auth.GetUserID(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	keywordID, err := strconv.Atoi(ctx.Param("keyword_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid keyword_id"})
+		return
+	}
+
+	granularity := ctx.DefaultQuery("granularity", series.Day)
+	if !series.IsGranularity(granularity) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid granularity (hour, day, week or month)"})
+		return
+	}
+	orderBy := ctx.DefaultQuery("sort", models.TrendItemOrderRelevance)
+	if !models.IsTrendItemOrder(orderBy) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort (relevance, engagement, positive or negative)"})
+		return
+	}
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit (1-200)"})
+		return
+	}
+	offset, err := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+		return
+	}
+
+	keyword, ok := c.ownedKeyword(ctx, keywordID, userID)
+	if !ok {
+		return
+	}
+
+	// Resolve the point to its bucket in the user's time zone
+	loc := userLocation(ctx, userID)
+	date := ctx.Param("date")
+	var start time.Time
+	if granularity == series.Hour {
+		start, err = time.Parse(time.RFC3339, date)
+	} else {
+		start, err = parseDate(date, loc)
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format"})
+		return
+	}
+	start = series.BucketStart(start.In(loc), granularity)
+
+	query := models.TrendPointQuery{
+		KeywordID: keywordID,
+		Start:     start,
+		End:       series.NextBucket(start, granularity),
+		Hourly:    granularity == series.Hour,
+		Kinds:     splitList(ctx.Query("kind")),
+		OrderBy:   orderBy,
+		Limit:     limit,
+		Offset:    offset,
+	}
+
+	summaries, err := models.GetTrendPointSummary(ctx, query)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get trend point items"})
+		return
+	}
+	items, err := models.GetTrendPointItems(ctx, query)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get trend point items"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, views.NewTrendPointItemsResponse(query, date, granularity, keyword.Keyword, summaries, items, loc))
+}
+
 // GetTrendAnalysis handles trend analysis requests
 func (c *TrendController) GetTrendAnalysis(ctx *gin.Context) {
 	// Get authenticated user ID
@@ -125,41 +288,48 @@ func (c *TrendController) GetTrendAnalysis(ctx *gin.Context) {
 		return
 	}
 
+	metric, ok := c.parseMetric(ctx, req.Metric)
+	if !ok {
+		return
+	}
+
 	// Verify keyword ownership
 	if !c.verifyKeywordOwnership(ctx, req.KeywordID, userID) {
 		return
 	}
 
-	// Parse dates
-	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	// Parse dates in the user's time zone; the end date includes the whole day
+	loc := userLocation(ctx, userID)
+	startDate, err := parseDate(req.StartDate, loc)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format"})
 		return
 	}
 
-	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	endDate, err := parseDate(req.EndDate, loc)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date format"})
 		return
 	}
+	endDate = endOfDay(endDate)
 
 	// Get trend data
-	trends, err := models.GetTrendRecords(ctx, req.KeywordID, startDate, endDate)
+	sources := splitList(req.Source)
+	trends, err := models.GetMetricTrendRecords(ctx, req.KeywordID, metric, startDate, endDate, sources)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get trend data"})
 		return
 	}
 
-	// Convert to trend points for analysis
-	var trendPoints []trend.TrendPoint
-	for _, t := range trends {
-		trendPoints = append(trendPoints, trend.TrendPoint{
-			Date:      t.Date,
-			Volume:    float64(t.Volume),
-			Sentiment: t.Sentiment,
-		})
+	breakdown, err := models.GetTrendRecordSources(ctx, req.KeywordID, startDate, endDate, sources)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get source breakdown"})
+		return
 	}
 
+	// Convert to trend points for analysis
+	trendPoints := toTrendPoints(trends, metric)
+
 	// Generate insights
 	var insights map[string]interface{}
 	if len(trendPoints) > 0 {
@@ -175,7 +345,11 @@ func (c *TrendController) GetTrendAnalysis(ctx *gin.Context) {
 		KeywordID: req.KeywordID,
 		StartDate: req.StartDate,
 		EndDate:   req.EndDate,
-		Data:      trends,
+		Metric:    metric,
+		Source:    sources,
+		Data:      inLocation(trends, loc),
+		Sources:   views.NewSourceSeriesResponses(breakdown, metric, loc),
+		Lifecycle: views.NewLifecycleResponse(trend.ClassifyLifecycle(trendPoints), loc),
 		Insights:  insights,
 	}
 
@@ -198,16 +372,37 @@ func (c *TrendController) GetTrendPrediction(ctx *gin.Context) {
 		return
 	}
 
+	metric, ok := c.parseMetric(ctx, req.Metric)
+	if !ok {
+		return
+	}
+
+	if req.Model == "" {
+		req.Model = trend.ModelAuto
+	}
+	if !trend.IsModelChoice(req.Model) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid model %q (auto, ensemble or one of %s)",
+			req.Model, strings.Join(trend.VolumeModels(), ", "))})
+		return
+	}
+
+	opts := trend.PredictionOptions{Model: req.Model, FullHistory: req.FullHistory}
+	if opts.IntervalLevels, ok = c.parseIntervalLevels(ctx, req.Levels); !ok {
+		return
+	}
+
 	// Verify keyword ownership
 	if !c.verifyKeywordOwnership(ctx, req.KeywordID, userID) {
 		return
 	}
 
 	// Get historical data (last 90 days for better prediction)
+	loc := userLocation(ctx, userID)
 	endDate := time.Now()
 	startDate := endDate.AddDate(0, 0, -90)
 
-	trends, err := models.GetTrendRecords(ctx, req.KeywordID, startDate, endDate)
+	sources := splitList(req.Source)
+	trends, err := models.GetMetricTrendRecords(ctx, req.KeywordID, metric, startDate, endDate, sources)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get historical data"})
 		return
@@ -215,24 +410,17 @@ func (c *TrendController) GetTrendPrediction(ctx *gin.Context) {
 
 	if len(trends) < 7 {
 		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error":          "Insufficient data for prediction. At least 7 data points required.",
+			"error":            "Insufficient data for prediction. At least 7 data points required.",
 			"available_points": len(trends),
 		})
 		return
 	}
 
 	// Convert to trend points
-	var trendPoints []trend.TrendPoint
-	for _, t := range trends {
-		trendPoints = append(trendPoints, trend.TrendPoint{
-			Date:      t.Date,
-			Volume:    float64(t.Volume),
-			Sentiment: t.Sentiment,
-		})
-	}
+	trendPoints := toTrendPoints(trends, metric)
 
 	// Generate predictions
-	predictions, err := c.predictionEngine.PredictTrend(trendPoints, req.Days)
+	predictions, fit, err := c.predictionEngine.PredictTrendWithOptions(trendPoints, req.Days, opts)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Prediction failed: %v", err)})
 		return
@@ -242,9 +430,12 @@ func (c *TrendController) GetTrendPrediction(ctx *gin.Context) {
 	var predictionData []views.PredictionData
 	for _, pred := range predictions {
 		predictionData = append(predictionData, views.PredictionData{
-			Date:           pred.Date.Format("2006-01-02"),
-			Volume:         int(pred.Volume),
+			Date:           pred.Date.In(loc).Format("2006-01-02"),
+			Volume:         int(math.Round(pred.Volume)),
+			Value:          pred.Volume,
 			Sentiment:      pred.Sentiment,
+			StdErr:         pred.VolumeStdErr,
+			Intervals:      views.NewPredictionIntervalResponses(pred.Intervals),
 			Confidence:     pred.Confidence,
 			TrendDirection: pred.TrendDirection,
 		})
@@ -253,9 +444,19 @@ func (c *TrendController) GetTrendPrediction(ctx *gin.Context) {
 	// Generate insights
 	insights := c.predictionEngine.GetTrendInsights(trendPoints, predictions)
 
+	// Keep the forecast to score it once the actual values are collected
+	forecast := trend.NewForecastRecord(req.KeywordID, metric, sources, req.Model, fit, predictions)
+	if err := models.SaveForecast(ctx, forecast); err != nil {
+		log.Printf("Failed to save forecast for keyword %d: %v", req.KeywordID, err)
+	}
+
 	// Return response
 	response := views.TrendPredictionResponse{
 		KeywordID:   req.KeywordID,
+		Metric:      metric,
+		Source:      sources,
+		ForecastID:  forecast.ID,
+		ModelFit:    views.NewModelFitResponse(fit, loc),
 		Predictions: predictionData,
 		Insights:    insights,
 	}
@@ -263,6 +464,324 @@ func (c *TrendController) GetTrendPrediction(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, response)
 }
 
+// BacktestTrendPrediction handles rolling-origin backtesting of the volume
+// models on a keyword's history
+func (c *TrendController) BacktestTrendPrediction(ctx *gin.Context) {
+	userID, exists := auth.GetUserID(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req TrendBacktestRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	metric, ok := c.parseMetric(ctx, req.Metric)
+	if !ok {
+		return
+	}
+
+	opts := trend.BacktestOptions{Horizon: req.Horizon, Folds: req.Folds, Models: req.Models}
+	if opts.Horizon == 0 {
+		opts.Horizon = 7
+	}
+	if opts.Folds == 0 {
+		opts.Folds = 4
+	}
+	for _, model := range opts.Models {
+		if !trend.IsVolumeModel(model) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid model %q (one of %s)",
+				model, strings.Join(trend.VolumeModels(), ", "))})
+			return
+		}
+	}
+	if opts.IntervalLevels, ok = c.parseIntervalLevels(ctx, req.Levels); !ok {
+		return
+	}
+
+	if !c.verifyKeywordOwnership(ctx, req.KeywordID, userID) {
+		return
+	}
+
+	endDate := time.Now()
+	startDate := endDate.AddDate(0, 0, -backtestHistoryDays)
+	sources := splitList(req.Source)
+	trends, err := models.GetMetricTrendRecords(ctx, req.KeywordID, metric, startDate, endDate, sources)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get historical data"})
+		return
+	}
+
+	results, err := c.predictionEngine.Backtest(toTrendPoints(trends, metric), opts)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":            fmt.Sprintf("Backtest failed: %v", err),
+			"available_points": len(trends),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, views.NewBacktestResponse(req.KeywordID, metric, sources, opts.Horizon, opts.Folds, results))
+}
+
+// GetForecastOverlay handles requests for past forecasts of a keyword overlaid
+// on the realized values, with the rolling accuracy of each model
+func (c *TrendController) GetForecastOverlay(ctx *gin.Context) {
+	userID, exists := auth.GetUserID(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	keywordID, err := strconv.Atoi(ctx.Param("keyword_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid keyword_id"})
+		return
+	}
+
+	metric, ok := c.parseMetric(ctx, ctx.Query("metric"))
+	if !ok {
+		return
+	}
+	days, err := strconv.Atoi(ctx.DefaultQuery("days", "90"))
+	if err != nil || days < 1 || days > 365 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid days parameter (1-365)"})
+		return
+	}
+	window, err := strconv.Atoi(ctx.DefaultQuery("window", "28"))
+	if err != nil || window < 1 || window > 90 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid window parameter (1-90)"})
+		return
+	}
+
+	if !c.verifyKeywordOwnership(ctx, keywordID, userID) {
+		return
+	}
+
+	loc := userLocation(ctx, userID)
+	endDate := time.Now()
+	startDate := endDate.AddDate(0, 0, -days)
+	sources := splitList(ctx.Query("source"))
+
+	forecasts, err := models.GetForecasts(ctx, models.ForecastQuery{
+		KeywordID: keywordID,
+		Metric:    metric,
+		Sources:   sources,
+		Models:    splitList(ctx.Query("model")),
+		From:      startDate,
+		To:        endDate,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get forecasts"})
+		return
+	}
+
+	actuals, err := models.GetMetricTrendRecords(ctx, keywordID, metric, startDate, endDate, sources)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get trend data"})
+		return
+	}
+
+	accuracy := trend.MeasureForecastAccuracy(forecasts, window)
+	ctx.JSON(http.StatusOK, views.NewForecastOverlayResponse(keywordID, metric, sources, startDate, endDate, window,
+		actuals, forecasts, accuracy, loc))
+}
+
+// GetChangepoints handles requests for the regimes of a keyword's trend:
+// changepoints in level and slope, and the online CUSUM detector
+func (c *TrendController) GetChangepoints(ctx *gin.Context) {
+	userID, exists := auth.GetUserID(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	keywordID, err := strconv.Atoi(ctx.Param("keyword_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid keyword_id"})
+		return
+	}
+
+	metric, ok := c.parseMetric(ctx, ctx.Query("metric"))
+	if !ok {
+		return
+	}
+	days, err := strconv.Atoi(ctx.DefaultQuery("days", "90"))
+	if err != nil || days < 14 || days > 365 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid days parameter (14-365)"})
+		return
+	}
+	opts := analysis.Options{Method: ctx.DefaultQuery("method", analysis.MethodPELT)}
+	if !analysis.IsMethod(opts.Method) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid method (pelt or binseg)"})
+		return
+	}
+	if value := ctx.Query("penalty"); value != "" {
+		if opts.Penalty, err = strconv.ParseFloat(value, 64); err != nil || opts.Penalty <= 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid penalty (positive number)"})
+			return
+		}
+	}
+	if value := ctx.Query("min_size"); value != "" {
+		if opts.MinSize, err = strconv.Atoi(value); err != nil || opts.MinSize < 3 || opts.MinSize > 60 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid min_size (3-60)"})
+			return
+		}
+	}
+
+	if !c.verifyKeywordOwnership(ctx, keywordID, userID) {
+		return
+	}
+
+	loc := userLocation(ctx, userID)
+	endDate := time.Now()
+	startDate := endDate.AddDate(0, 0, -days)
+	sources := splitList(ctx.Query("source"))
+	trends, err := models.GetMetricTrendRecords(ctx, keywordID, metric, startDate, endDate, sources)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get trend data"})
+		return
+	}
+	if len(trends) == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "No trend data in the period", "available_points": 0})
+		return
+	}
+
+	regimes, err := trend.DetectRegimes(toTrendPoints(trends, metric), opts)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Changepoint detection failed: %v", err)})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, views.NewChangepointResponse(keywordID, metric, sources, opts.Method, regimes, loc))
+}
+
+// GetAnomalies handles requests for the spikes and drops detected in a
+// keyword's volume and sentiment, with the items that explain each. Anomalies
+// are detected after each scheduled collection.
+func (c *TrendController) GetAnomalies(ctx *gin.Context) {
+	userID, exists := auth.GetUserID(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	keywordID, err := strconv.Atoi(ctx.Param("keyword_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid keyword_id"})
+		return
+	}
+
+	metricFilter := splitList(ctx.Query("metric"))
+	for _, metric := range metricFilter {
+		if !trend.IsAnomalyMetric(metric) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid metric (" + strings.Join(trend.AnomalyMetrics, " or ") + ")"})
+			return
+		}
+	}
+	severities := splitList(ctx.Query("severity"))
+	for _, severity := range severities {
+		if !analysis.IsSeverity(severity) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid severity (low, medium or high)"})
+			return
+		}
+	}
+	days, err := strconv.Atoi(ctx.DefaultQuery("days", "30"))
+	if err != nil || days < 1 || days > 365 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid days parameter (1-365)"})
+		return
+	}
+
+	keyword, ok := c.ownedKeyword(ctx, keywordID, userID)
+	if !ok {
+		return
+	}
+
+	loc := userLocation(ctx, userID)
+	endDate := time.Now()
+	startDate := endDate.AddDate(0, 0, -days)
+	anomalies, err := models.GetAnomalies(ctx, models.AnomalyQuery{
+		KeywordID:  keywordID,
+		Metrics:    metricFilter,
+		Severities: severities,
+		Start:      startDate,
+		End:        endDate,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get anomalies"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, views.NewAnomalyListResponse(keywordID, keyword.Keyword, startDate, endDate, anomalies, loc))
+}
+
+// GetPeakEstimate handles requests for when a keyword's trend peaks, how
+// high and where its cumulative volume saturates, from Bass, logistic and
+// Gompertz curves fitted to its cumulative volume
+func (c *TrendController) GetPeakEstimate(ctx *gin.Context) {
+	userID, exists := auth.GetUserID(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	keywordID, err := strconv.Atoi(ctx.Param("keyword_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid keyword_id"})
+		return
+	}
+
+	metric, ok := c.parseMetric(ctx, ctx.Query("metric"))
+	if !ok {
+		return
+	}
+	// Cumulative volume is only meaningful for metrics that add up
+	if metrics.Aggregation(metric) != metrics.AggregateSum {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Peak estimates need a summed metric such as volume"})
+		return
+	}
+	days, err := strconv.Atoi(ctx.DefaultQuery("days", "180"))
+	if err != nil || days < 21 || days > 730 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid days parameter (21-730)"})
+		return
+	}
+	horizon, err := strconv.Atoi(ctx.DefaultQuery("horizon", "90"))
+	if err != nil || horizon < 1 || horizon > 365 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid horizon parameter (1-365)"})
+		return
+	}
+	model := ctx.DefaultQuery("model", trend.ModelAuto)
+	if !trend.IsDiffusionChoice(model) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid model (auto, " + strings.Join(prediction.DiffusionKinds, ", ") + ")"})
+		return
+	}
+
+	if !c.verifyKeywordOwnership(ctx, keywordID, userID) {
+		return
+	}
+
+	loc := userLocation(ctx, userID)
+	endDate := time.Now()
+	startDate := endDate.AddDate(0, 0, -days)
+	sources := splitList(ctx.Query("source"))
+	trends, err := models.GetMetricTrendRecords(ctx, keywordID, metric, startDate, endDate, sources)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get trend data"})
+		return
+	}
+
+	estimate, err := trend.EstimatePeak(toTrendPoints(trends, metric), model, horizon)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Peak estimation failed: %v", err), "available_points": len(trends)})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, views.NewPeakEstimateResponse(keywordID, metric, sources, startDate, endDate, estimate, loc))
+}
+
 // GetSentimentAnalysis handles sentiment analysis requests
 func (c *TrendController) GetSentimentAnalysis(ctx *gin.Context) {
 	// Get authenticated user ID
@@ -329,7 +848,7 @@ func (c *TrendController) GetSentimentAnalysis(ctx *gin.Context) {
 		PositiveCount:    positiveCount,
 		NegativeCount:    negativeCount,
 		NeutralCount:     neutralCount,
-		Data:             trends,
+		Data:             inLocation(trends, userLocation(ctx, userID)),
 		Images:           images,
 	}
 
@@ -348,6 +867,12 @@ func (c *TrendController) GetMultiKeywordComparison(ctx *gin.Context) {
 	// Get query parameters
 	keywordIDsStr := ctx.Query("keyword_ids")
 	daysStr := ctx.DefaultQuery("days", "30")
+	sources := splitList(ctx.Query("source"))
+
+	metric, ok := c.parseMetric(ctx, ctx.Query("metric"))
+	if !ok {
+		return
+	}
 
 	if keywordIDsStr == "" {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "keyword_ids parameter is required"})
@@ -380,7 +905,8 @@ func (c *TrendController) GetMultiKeywordComparison(ctx *gin.Context) {
 	}
 
 	// Get comparison data
-	endDate := time.Now()
+	loc := userLocation(ctx, userID)
+	endDate := time.Now().In(loc)
 	startDate := endDate.AddDate(0, 0, -days)
 
 	var comparisonData []views.KeywordComparisonData
@@ -392,88 +918,175 @@ func (c *TrendController) GetMultiKeywordComparison(ctx *gin.Context) {
 		}
 
 		// Get trend data
-		trends, err := models.GetTrendRecords(ctx, keywordID, startDate, endDate)
+		trends, err := models.GetMetricTrendRecords(ctx, keywordID, metric, startDate, endDate, sources)
+		if err != nil {
+			continue
+		}
+
+		breakdown, err := models.GetTrendRecordSources(ctx, keywordID, startDate, endDate, sources)
+		if err != nil {
+			continue
+		}
+
+		versions, err := models.GetKeywordVersions(ctx, keywordID)
 		if err != nil {
 			continue
 		}
 
 		// Calculate metrics
 		var totalVolume int
-		var totalSentiment float64
+		var totalEngagement, totalValue, totalSentiment float64
 		for _, trend := range trends {
 			totalVolume += trend.Volume
+			totalEngagement += trend.Engagement
+			totalValue += trend.MetricValue(metric)
 			totalSentiment += trend.Sentiment
 		}
 
 		var avgSentiment float64
 		if len(trends) > 0 {
 			avgSentiment = totalSentiment / float64(len(trends))
+			if metrics.Aggregation(metric) == metrics.AggregateAvg {
+				totalValue /= float64(len(trends)) // totals of ratio metrics are averages
+			}
 		}
 
 		comparisonData = append(comparisonData, views.KeywordComparisonData{
-			KeywordID:    keywordID,
-			Keyword:      keyword.Keyword,
-			TotalVolume:  totalVolume,
-			AvgSentiment: avgSentiment,
-			DataPoints:   len(trends),
-			Trends:       trends,
+			KeywordID:         keywordID,
+			Keyword:           keyword.Keyword,
+			TotalVolume:       totalVolume,
+			TotalEngagement:   totalEngagement,
+			TotalValue:        totalValue,
+			AvgSentiment:      avgSentiment,
+			DataPoints:        len(trends),
+			Lifecycle:         views.NewLifecycleResponse(trend.ClassifyLifecycle(toTrendPoints(trends, metric)), loc),
+			Trends:            inLocation(trends, loc),
+			Sources:           views.NewSourceSeriesResponses(breakdown, metric, loc),
+			DefinitionChanges: views.NewDefinitionChangeResponses(versions, series.Day, startDate, endDate, loc),
 		})
 	}
 
 	// Return response
 	response := views.MultiKeywordComparisonResponse{
-		Period:     days,
-		StartDate:  startDate.Format("2006-01-02"),
-		EndDate:    endDate.Format("2006-01-02"),
-		Keywords:   comparisonData,
-		Insights:   c.generateComparisonInsights(comparisonData),
+		Period:    days,
+		Metric:    metric,
+		Source:    sources,
+		StartDate: startDate.Format("2006-01-02"),
+		EndDate:   endDate.Format("2006-01-02"),
+		Keywords:  comparisonData,
+		Insights:  c.generateComparisonInsights(comparisonData, metric),
 	}
 
 	ctx.JSON(http.StatusOK, response)
 }
 
+// parseMetric validates the metric selector, writing a 400 response for unknown metrics
+func (c *TrendController) parseMetric(ctx *gin.Context, metric string) (string, bool) {
+	metric = metrics.OrDefault(metric)
+	if !metrics.IsValid(metric) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid metric: %s", metric)})
+		return "", false
+	}
+	if metrics.IsBuiltin(metric) {
+		return metric, true
+	}
+
+	// Other metrics only exist once a series of them has been stored
+	exists, err := models.MetricExists(ctx, metric)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check metric"})
+		return "", false
+	}
+	if !exists {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown metric: %s", metric)})
+		return "", false
+	}
+	return metric, true
+}
+
+// toTrendPoints converts trend records to prediction input using the selected metric as volume
+func toTrendPoints(records []models.TrendRecord, metric string) []trend.TrendPoint {
+	var points []trend.TrendPoint
+	for _, r := range records {
+		points = append(points, trend.TrendPoint{
+			Date:      r.Date,
+			Volume:    r.MetricValue(metric),
+			Sentiment: r.Sentiment,
+		})
+	}
+	return points
+}
+
+// parseIntervalLevels converts prediction interval levels in percent to
+// fractions, writing an error response when they are invalid
+func (c *TrendController) parseIntervalLevels(ctx *gin.Context, percents []float64) ([]float64, bool) {
+	if len(percents) > maxIntervalLevels {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("At most %d interval levels", maxIntervalLevels)})
+		return nil, false
+	}
+
+	var levels []float64
+	for _, level := range percents {
+		if level <= 0 || level >= 100 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid interval level (between 0 and 100)"})
+			return nil, false
+		}
+		levels = append(levels, level/100)
+	}
+	return levels, true
+}
+
 // verifyKeywordOwnership checks if a keyword belongs to the user
 func (c *TrendController) verifyKeywordOwnership(ctx *gin.Context, keywordID, userID int) bool {
+	_, ok := c.ownedKeyword(ctx, keywordID, userID)
+	return ok
+}
+
+// ownedKeyword loads a keyword belonging to the user, writing an error
+// response when it is missing or belongs to someone else
+func (c *TrendController) ownedKeyword(ctx *gin.Context, keywordID, userID int) (*models.Keyword, bool) {
 	keyword, err := models.GetKeywordByID(ctx, keywordID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify keyword ownership"})
-		return false
+		return nil, false
 	}
 
 	if keyword == nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Keyword not found"})
-		return false
+		return nil, false
 	}
 
 	if keyword.UserID != userID {
 		ctx.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
-		return false
+		return nil, false
 	}
 
-	return true
+	return keyword, true
 }
 
 // generateComparisonInsights generates insights for multi-keyword comparison
-func (c *TrendController) generateComparisonInsights(data []views.KeywordComparisonData) map[string]interface{} {
+func (c *TrendController) generateComparisonInsights(data []views.KeywordComparisonData, metric string) map[string]interface{} {
 	insights := make(map[string]interface{})
 
 	if len(data) == 0 {
 		return insights
 	}
 
-	// Find highest performing keyword
+	// Find highest performing keyword by the selected metric
 	var topKeyword views.KeywordComparisonData
-	maxVolume := 0
+	maxValue := 0.0
 
 	for _, keyword := range data {
-		if keyword.TotalVolume > maxVolume {
-			maxVolume = keyword.TotalVolume
+		if keyword.TotalValue > maxValue {
+			maxValue = keyword.TotalValue
 			topKeyword = keyword
 		}
 	}
 
+	insights["metric"] = metric
 	insights["top_keyword"] = topKeyword.Keyword
-	insights["top_volume"] = maxVolume
+	insights["top_volume"] = topKeyword.TotalVolume
+	insights["top_value"] = maxValue
 
 	// Calculate average sentiment across all keywords
 	var totalSentiment float64
@@ -498,4 +1111,4 @@ func (c *TrendController) generateComparisonInsights(data []views.KeywordCompari
 	insights["total_data_points"] = totalDataPoints
 
 	return insights
-} 
\ No newline at end of file
+}
@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trendscout/backend/internal/auth"
+	"github.com/trendscout/backend/internal/models"
+	"github.com/trendscout/backend/internal/timezone"
+	"github.com/trendscout/backend/internal/views"
+)
+
+// UserController handles user preference requests
+type UserController struct{}
+
+// NewUserController creates a new user controller
+func NewUserController() *UserController {
+	return &UserController{}
+}
+
+// PreferencesUpdateRequest represents the request for updating preferences
+type PreferencesUpdateRequest struct {
+	Timezone string `json:"timezone" binding:"required,max=64"` // IANA name, e.g. Asia/Tokyo
+}
+
+// GetPreferences handles retrieving the authenticated user's preferences
+func (c *UserController) GetPreferences(ctx *gin.Context) {
+	user, ok := c.currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, views.NewPreferencesResponse(user))
+}
+
+// UpdatePreferences handles updating the authenticated user's preferences
+func (c *UserController) UpdatePreferences(ctx *gin.Context) {
+	var req PreferencesUpdateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := timezone.Load(req.Timezone); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timezone"})
+		return
+	}
+
+	user, ok := c.currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	if err := user.UpdateTimezone(ctx, req.Timezone); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update preferences"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, views.NewPreferencesResponse(user))
+}
+
+// currentUser loads the authenticated user, writing an error response on failure
+func (c *UserController) currentUser(ctx *gin.Context) (*models.User, bool) {
+	userID, exists := auth.GetUserID(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+
+	user, err := models.GetUserByID(ctx, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		return nil, false
+	}
+	if user == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return nil, false
+	}
+
+	return user, true
+}
+
+// userLocation returns the user's preferred time zone, falling back to the
+// default zone when the user cannot be loaded
+func userLocation(ctx *gin.Context, userID int) *time.Location {
+	user, err := models.GetUserByID(ctx, userID)
+	if err != nil || user == nil {
+		return timezone.LoadOrDefault(timezone.DefaultUser)
+	}
+	return timezone.LoadOrDefault(user.Timezone)
+}
+
+// parseDate parses a YYYY-MM-DD date as midnight in loc
+func parseDate(value string, loc *time.Location) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", value, loc)
+}
+
+// endOfDay returns the last instant of the day starting at day
+func endOfDay(day time.Time) time.Time {
+	return day.AddDate(0, 0, 1).Add(-time.Nanosecond)
+}
+
+// inLocation returns the records with their dates expressed in loc
+func inLocation(records []models.TrendRecord, loc *time.Location) []models.TrendRecord {
+	for i := range records {
+		records[i].Date = records[i].Date.In(loc)
+	}
+	return records
+}
@@ -0,0 +1,169 @@
+// Package deletion implements keyword deletion: a soft delete that can be
+// undone during a grace period, followed by a purge of the keyword's data
+// from every store.
+package deletion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/trendscout/backend/internal/models"
+)
+
+// defaultGracePeriod is used when KEYWORD_DELETE_GRACE_PERIOD is not set
+const defaultGracePeriod = 7 * 24 * time.Hour
+
+// ErrGracePeriodExpired is returned when restoring a keyword deleted too long ago
+var ErrGracePeriodExpired = errors.New("grace period expired")
+
+// Service deletes, restores and purges keywords
+type Service struct {
+	gracePeriod time.Duration
+}
+
+// PurgeReport summarises the data removed with a keyword. Images are stored as
+// source URLs only, so there are no archived blobs to remove.
+type PurgeReport struct {
+	KeywordID    int
+	Articles     int64
+	Posts        int64
+	Images       int64
+	CacheEntries int64
+}
+
+// NewService creates a new deletion service
+func NewService() *Service {
+	return &Service{gracePeriod: gracePeriod()}
+}
+
+// gracePeriod reads KEYWORD_DELETE_GRACE_PERIOD (a Go duration such as "168h")
+func gracePeriod() time.Duration {
+	value := os.Getenv("KEYWORD_DELETE_GRACE_PERIOD")
+	if value == "" {
+		return defaultGracePeriod
+	}
+
+	period, err := time.ParseDuration(value)
+	if err != nil || period < 0 {
+		log.Printf("Invalid KEYWORD_DELETE_GRACE_PERIOD %q, using %s", value, defaultGracePeriod)
+		return defaultGracePeriod
+	}
+	return period
+}
+
+// RestoreDeadline returns the last moment a deleted keyword can be restored
+func (s *Service) RestoreDeadline(deletedAt time.Time) time.Time {
+	return deletedAt.Add(s.gracePeriod)
+}
+
+// Delete soft-deletes a keyword and returns its restore deadline. The keyword
+// disappears from every listing and is no longer collected.
+func (s *Service) Delete(ctx context.Context, keywordID int) (time.Time, error) {
+	deletedAt, err := models.SoftDeleteKeyword(ctx, keywordID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return s.RestoreDeadline(deletedAt), nil
+}
+
+// Restore undoes the deletion of a keyword within the grace period. It returns
+// models.ErrKeywordConflict when the user has registered the keyword again.
+func (s *Service) Restore(ctx context.Context, keywordID int) error {
+	return restoreError(models.RestoreKeyword(ctx, keywordID, time.Now().Add(-s.gracePeriod)))
+}
+
+// restoreError maps the error of restoring a keyword: a keyword that is not
+// found among the restorable ones has outlived its grace period
+func restoreError(err error) error {
+	if errors.Is(err, models.ErrKeywordNotFound) {
+		return ErrGracePeriodExpired
+	}
+	return err
+}
+
+// DeletedKeywords lists a user's deleted keywords that can still be restored
+func (s *Service) DeletedKeywords(ctx context.Context, userID int) ([]*models.Keyword, error) {
+	return models.GetDeletedKeywordsForUser(ctx, userID, time.Now().Add(-s.gracePeriod))
+}
+
+// Purge permanently removes a deleted keyword: its MongoDB content, cached
+// Redis entries and finally its PostgreSQL rows. The keyword row goes last,
+// so a purge interrupted by an error stays marked deleted and is retried.
+// A collection still running when the keyword was deleted can write content
+// after the first sweep, so MongoDB and Redis are swept again once the
+// keyword row is gone and nothing can be collected for it any more.
+func (s *Service) Purge(ctx context.Context, keywordID int) (*PurgeReport, error) {
+	keyword, err := models.GetDeletedKeywordByID(ctx, keywordID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get keyword: %w", err)
+	}
+	if keyword == nil {
+		return nil, fmt.Errorf("keyword %d is not deleted", keywordID)
+	}
+
+	report := &PurgeReport{KeywordID: keywordID}
+	if err := purgeContent(ctx, keywordID, report); err != nil {
+		return report, err
+	}
+	if err := models.PurgeKeyword(ctx, keywordID); err != nil {
+		return report, fmt.Errorf("failed to delete keyword data: %w", err)
+	}
+	if err := purgeContent(ctx, keywordID, report); err != nil {
+		return report, fmt.Errorf("keyword purged, but content written meanwhile remains: %w", err)
+	}
+
+	return report, nil
+}
+
+// purgeContent deletes a keyword's MongoDB content and Redis cache entries,
+// adding the removed counts to the report
+func purgeContent(ctx context.Context, keywordID int, report *PurgeReport) error {
+	articles, err := models.DeleteBlogArticlesForKeyword(ctx, keywordID)
+	report.Articles += articles
+	if err != nil {
+		return fmt.Errorf("failed to delete blog articles: %w", err)
+	}
+	posts, err := models.DeleteSocialMediaPostsForKeyword(ctx, keywordID)
+	report.Posts += posts
+	if err != nil {
+		return fmt.Errorf("failed to delete social media posts: %w", err)
+	}
+	images, err := models.DeleteImagesForKeyword(ctx, keywordID)
+	report.Images += images
+	if err != nil {
+		return fmt.Errorf("failed to delete images: %w", err)
+	}
+	entries, err := models.DelPrefix(ctx, models.KeywordCachePrefix(keywordID))
+	report.CacheEntries += entries
+	if err != nil {
+		return fmt.Errorf("failed to delete cache entries: %w", err)
+	}
+	return nil
+}
+
+// PurgeExpired purges every keyword whose grace period has ended and returns
+// how many were purged
+func (s *Service) PurgeExpired(ctx context.Context) (int, error) {
+	keywords, err := models.GetKeywordsDeletedBefore(ctx, time.Now().Add(-s.gracePeriod))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get deleted keywords: %w", err)
+	}
+
+	purged := 0
+	for _, keyword := range keywords {
+		report, err := s.Purge(ctx, keyword.ID)
+		if err != nil {
+			log.Printf("Failed to purge keyword %q (ID: %d): %v", keyword.Keyword, keyword.ID, err)
+			continue
+		}
+		log.Printf("Purged keyword %q (ID: %d): %d articles, %d posts, %d images, %d cache entries",
+			keyword.Keyword, keyword.ID, report.Articles, report.Posts, report.Images, report.CacheEntries)
+		purged++
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,68 @@
+package deletion
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/trendscout/backend/internal/models"
+)
+
+func TestGracePeriod(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", defaultGracePeriod},
+		{"48h", 48 * time.Hour},
+		{"90m", 90 * time.Minute},
+		// Zero purges at the next run
+		{"0s", 0},
+		{"7d", defaultGracePeriod},
+		{"soon", defaultGracePeriod},
+		{"-1h", defaultGracePeriod},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("KEYWORD_DELETE_GRACE_PERIOD", tt.value)
+		if got := gracePeriod(); got != tt.want {
+			t.Errorf("gracePeriod(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestRestoreDeadline(t *testing.T) {
+	deletedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	s := &Service{gracePeriod: 48 * time.Hour}
+	if got, want := s.RestoreDeadline(deletedAt), deletedAt.Add(48*time.Hour); !got.Equal(want) {
+		t.Errorf("RestoreDeadline = %s, want %s", got, want)
+	}
+
+	s = &Service{}
+	if got := s.RestoreDeadline(deletedAt); !got.Equal(deletedAt) {
+		t.Errorf("RestoreDeadline without grace period = %s, want %s", got, deletedAt)
+	}
+}
+
+func TestRestoreError(t *testing.T) {
+	other := errors.New("connection refused")
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"restored", nil, nil},
+		{"not restorable", models.ErrKeywordNotFound, ErrGracePeriodExpired},
+		{"wrapped not found", fmt.Errorf("restore: %w", models.ErrKeywordNotFound), ErrGracePeriodExpired},
+		{"registered again", models.ErrKeywordConflict, models.ErrKeywordConflict},
+		{"other error", other, other},
+	}
+
+	for _, tt := range tests {
+		if got := restoreError(tt.err); got != tt.want {
+			t.Errorf("%s: restoreError(%v) = %v, want %v", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"math"
+	"strings"
+)
+
+// Interaction weights: a comment or repost signals more interest than a like
+const (
+	commentWeight = 2.0
+	repostWeight  = 3.0
+)
+
+// sourceWeights scales the engagement of an item by how much the source
+// says about a fashion trend. Editorial sources without public engagement
+// counts are weighted up so a published article is not drowned out by
+// individual posts; synthetic or low-signal sources are weighted down.
+var sourceWeights = map[string]float64{
+	"vogue.com":         1.5,
+	"elle.com":          1.3,
+	"wwd.com":           1.3,
+	"harpersbazaar.com": 1.3,
+	"hypebeast.com":     1.2,
+	"fashionista.com":   1.1,
+	"refinery29.com":    1.0,
+	"popsugar.com":      0.9,
+	"mastodon":          1.0,
+	"bluesky":           1.0,
+	"reddit":            0.8,
+	"runway.fashion":    0.5,
+}
+
+// defaultSourceWeight applies to sources not listed in sourceWeights
+const defaultSourceWeight = 1.0
+
+// SourceWeight returns the engagement weight for a source
+func SourceWeight(source string) float64 {
+	if w, ok := sourceWeights[strings.ToLower(source)]; ok {
+		return w
+	}
+	return defaultSourceWeight
+}
+
+// EngagementScore returns the log-scaled, source-weighted engagement of a
+// single item. An item without any interactions scores its source weight,
+// so the metric degrades to a weighted count for sources like RSS feeds.
+func EngagementScore(source string, likes, comments, reposts int) float64 {
+	interactions := float64(max(likes, 0)) +
+		commentWeight*float64(max(comments, 0)) +
+		repostWeight*float64(max(reposts, 0))
+
+	return SourceWeight(source) * (1 + math.Log1p(interactions))
+}
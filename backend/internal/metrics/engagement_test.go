@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEngagementScore(t *testing.T) {
+	tests := []struct {
+		source                   string
+		likes, comments, reposts int
+		want                     float64
+	}{
+		// Without interactions an item scores its source weight
+		{"vogue.com", 0, 0, 0, 1.5},
+		{"elle.com", 0, 0, 0, 1.3},
+		{"wwd.com", 0, 0, 0, 1.3},
+		{"harpersbazaar.com", 0, 0, 0, 1.3},
+		{"hypebeast.com", 0, 0, 0, 1.2},
+		{"fashionista.com", 0, 0, 0, 1.1},
+		{"refinery29.com", 0, 0, 0, 1.0},
+		{"popsugar.com", 0, 0, 0, 0.9},
+		{"mastodon", 0, 0, 0, 1.0},
+		{"bluesky", 0, 0, 0, 1.0},
+		{"reddit", 0, 0, 0, 0.8},
+		{"runway.fashion", 0, 0, 0, 0.5},
+		{"unknown.example", 0, 0, 0, 1.0},
+		// Source names are matched case-insensitively
+		{"Vogue.com", 0, 0, 0, 1.5},
+		// 10 likes + 2×2 comments + 3×1 repost = 17 interactions
+		{"reddit", 10, 2, 1, 0.8 * (1 + math.Log(18))},
+		{"bluesky", 99, 0, 0, 1 + math.Log(100)},
+		{"runway.fashion", 1, 2, 0, 0.5 * (1 + math.Log(6))},
+		{"vogue.com", 0, 0, 2, 1.5 * (1 + math.Log(7))},
+		// Negative counts are treated as zero
+		{"mastodon", -5, -1, -2, 1.0},
+	}
+
+	for _, tt := range tests {
+		got := EngagementScore(tt.source, tt.likes, tt.comments, tt.reposts)
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("EngagementScore(%q, %d, %d, %d) = %v, want %v", tt.source, tt.likes, tt.comments, tt.reposts, got, tt.want)
+		}
+	}
+}
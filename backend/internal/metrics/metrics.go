@@ -0,0 +1,68 @@
+package metrics
+
+import "regexp"
+
+// Names of the built-in metrics. Any other well-formed name can be stored in
+// the metric series store and selected by trend queries.
+const (
+	// Volume is the raw number of collected items
+	Volume = "volume"
+	// Engagement is the sum of EngagementScore over the collected items
+	Engagement = "engagement"
+	// Sentiment is the average sentiment of the collected items (0-1)
+	Sentiment = "sentiment"
+	// ArticleCount is the number of collected editorial articles
+	ArticleCount = "article_count"
+	// PostCount is the number of collected social media posts
+	PostCount = "post_count"
+	// ImageCount is the number of collected items with a real image
+	ImageCount = "image_count"
+)
+
+// Granularities of a metric series. Hourly buckets are rolled up to days
+// when a daily series is read.
+const (
+	GranularityHour = "hour"
+	GranularityDay  = "day"
+)
+
+// Ways of combining several values of a metric into one
+const (
+	AggregateSum = "sum"
+	AggregateAvg = "avg"
+)
+
+// namePattern restricts metric names to lower-case identifiers
+var namePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// IsValid reports whether name is a well-formed metric name. A name that is
+// not built in is only selectable once a series of it has been stored.
+func IsValid(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// IsBuiltin reports whether name is one of the built-in metrics
+func IsBuiltin(name string) bool {
+	switch name {
+	case Volume, Engagement, Sentiment, ArticleCount, PostCount, ImageCount:
+		return true
+	}
+	return false
+}
+
+// Aggregation returns how values of the metric are combined across
+// dimensions or buckets: averages for ratios, sums for everything else
+func Aggregation(name string) string {
+	if name == Sentiment {
+		return AggregateAvg
+	}
+	return AggregateSum
+}
+
+// OrDefault returns name, or Volume when name is empty
+func OrDefault(name string) string {
+	if name == "" {
+		return Volume
+	}
+	return name
+}
@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsValid(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{Volume, true},
+		{ArticleCount, true},
+		{"saves_7d", true},
+		{"a", true},
+		{"", false},
+		{"Volume", false},
+		{"7day", false},
+		{"_volume", false},
+		{"page-views", false},
+		{"volume;drop", false},
+		{"a" + strings.Repeat("b", 63), true},
+		{"a" + strings.Repeat("b", 64), false},
+	}
+
+	for _, tt := range tests {
+		if got := IsValid(tt.name); got != tt.want {
+			t.Errorf("IsValid(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIsBuiltin(t *testing.T) {
+	for _, name := range []string{Volume, Engagement, Sentiment, ArticleCount, PostCount, ImageCount} {
+		if !IsBuiltin(name) {
+			t.Errorf("IsBuiltin(%q) = false, want true", name)
+		}
+	}
+	// Well-formed names are not built in until they are listed
+	if IsBuiltin("saves_7d") {
+		t.Errorf("IsBuiltin(%q) = true, want false", "saves_7d")
+	}
+}
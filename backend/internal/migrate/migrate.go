@@ -0,0 +1,239 @@
+// Package migrate applies the versioned schema migrations embedded in the
+// binary to PostgreSQL and MongoDB.
+//
+// Migrations live in migrations/<database>/ as NNNN_name.up.<ext> with an
+// optional NNNN_name.down.<ext>. Each database records applied versions with
+// the checksum of their up script in a schema_migrations table (collection
+// for MongoDB); an applied migration whose script has since changed stops
+// further migration until the difference is resolved.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+//go:embed migrations
+var embedded embed.FS
+
+// Migration is a single versioned schema change
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string // empty when the migration cannot be rolled back
+	Checksum string // SHA-256 of Up
+}
+
+// Record is an applied migration as stored in the database
+type Record struct {
+	Version   int64
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Status describes a known or applied migration
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	Modified  bool // the embedded up script differs from the applied one
+	Missing   bool // applied but not embedded in this binary
+}
+
+// Migrator applies migrations to one database
+type Migrator interface {
+	// Database names the database, e.g. "postgres"
+	Database() string
+	// Status lists every embedded or applied migration, oldest first
+	Status(ctx context.Context) ([]Status, error)
+	// Up applies all pending migrations, oldest first, and returns them.
+	// With dryRun it only returns what would be applied.
+	Up(ctx context.Context, dryRun bool) ([]Migration, error)
+	// Down rolls back the latest steps applied migrations, newest first,
+	// and returns them. With dryRun it only returns what would be rolled back.
+	Down(ctx context.Context, steps int, dryRun bool) ([]Migration, error)
+}
+
+// Migrators returns the migrators for both databases
+func Migrators(pool *pgxpool.Pool, db *mongo.Database) ([]Migrator, error) {
+	pg, err := NewPostgres(pool)
+	if err != nil {
+		return nil, err
+	}
+	mg, err := NewMongo(db)
+	if err != nil {
+		return nil, err
+	}
+	return []Migrator{pg, mg}, nil
+}
+
+// Run applies all pending migrations to both databases
+func Run(ctx context.Context, pool *pgxpool.Pool, db *mongo.Database) error {
+	migrators, err := Migrators(pool, db)
+	if err != nil {
+		return err
+	}
+	for _, m := range migrators {
+		if _, err := m.Up(ctx, false); err != nil {
+			return fmt.Errorf("%s: %w", m.Database(), err)
+		}
+	}
+	return nil
+}
+
+// migrationFile matches NNNN_name.up.ext and NNNN_name.down.ext
+var migrationFile = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.([a-z]+)$`)
+
+// load reads the migrations in dir of fsys with the given file extension,
+// ordered by version
+func load(fsys fs.FS, dir, ext string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := migrationFile.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil || match[4] != ext {
+			return nil, fmt.Errorf("unexpected file in %s: %s", dir, entry.Name())
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version: %s", entry.Name())
+		}
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, m.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			m.Up = string(content)
+			sum := sha256.Sum256(content)
+			m.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// status merges the embedded migrations with the applied records
+func status(migrations []Migration, applied []Record) []Status {
+	records := make(map[int64]Record, len(applied))
+	for _, r := range applied {
+		records[r.Version] = r
+	}
+
+	var statuses []Status
+	known := make(map[int64]bool, len(migrations))
+	for _, m := range migrations {
+		known[m.Version] = true
+		s := Status{Version: m.Version, Name: m.Name}
+		if r, ok := records[m.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = r.AppliedAt
+			s.Modified = r.Checksum != m.Checksum
+		}
+		statuses = append(statuses, s)
+	}
+	for _, r := range applied {
+		if !known[r.Version] {
+			statuses = append(statuses, Status{Version: r.Version, Name: r.Name, Applied: true, AppliedAt: r.AppliedAt, Missing: true})
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses
+}
+
+// pending verifies the applied records against the embedded migrations and
+// returns the migrations still to apply
+func pending(migrations []Migration, applied []Record) ([]Migration, error) {
+	for _, s := range status(migrations, applied) {
+		switch {
+		case s.Missing:
+			return nil, fmt.Errorf("applied migration %d_%s is not known to this binary", s.Version, s.Name)
+		case s.Modified:
+			return nil, fmt.Errorf("migration %d_%s was modified after it was applied (checksum mismatch)", s.Version, s.Name)
+		}
+	}
+
+	done := make(map[int64]bool, len(applied))
+	for _, r := range applied {
+		done[r.Version] = true
+	}
+	var todo []Migration
+	for _, m := range migrations {
+		if !done[m.Version] {
+			todo = append(todo, m)
+		}
+	}
+	return todo, nil
+}
+
+// rollbacks returns the latest steps applied migrations, newest first
+func rollbacks(migrations []Migration, applied []Record, steps int) ([]Migration, error) {
+	byVersion := make(map[int64]Migration, len(migrations))
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+
+	sorted := append([]Record(nil), applied...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version > sorted[j].Version
+	})
+	if steps > len(sorted) {
+		steps = len(sorted)
+	}
+
+	var todo []Migration
+	for _, r := range sorted[:steps] {
+		m, ok := byVersion[r.Version]
+		if !ok {
+			return nil, fmt.Errorf("applied migration %d_%s is not known to this binary", r.Version, r.Name)
+		}
+		if m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s cannot be rolled back", m.Version, m.Name)
+		}
+		todo = append(todo, m)
+	}
+	return todo, nil
+}
@@ -0,0 +1,147 @@
+package migrate
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"m/0002_add_column.up.sql":   {Data: []byte("ALTER TABLE t ADD COLUMN c INT;")},
+		"m/0002_add_column.down.sql": {Data: []byte("ALTER TABLE t DROP COLUMN c;")},
+		"m/0001_create_table.up.sql": {Data: []byte("CREATE TABLE t (id INT);")},
+		"m/0003_backfill.up.sql":     {Data: []byte("UPDATE t SET c = 1;")},
+	}
+}
+
+func TestLoad(t *testing.T) {
+	migrations, err := load(testFS(), "m", "sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(migrations) != 3 {
+		t.Fatalf("got %d migrations, want 3", len(migrations))
+	}
+	for i, want := range []string{"create_table", "add_column", "backfill"} {
+		if migrations[i].Version != int64(i+1) || migrations[i].Name != want {
+			t.Errorf("migration %d = %d_%s, want %d_%s", i, migrations[i].Version, migrations[i].Name, i+1, want)
+		}
+		if len(migrations[i].Checksum) != 64 {
+			t.Errorf("migration %d checksum = %q", i, migrations[i].Checksum)
+		}
+	}
+	if migrations[1].Down == "" || migrations[2].Down != "" {
+		t.Errorf("down scripts not loaded as expected")
+	}
+}
+
+func TestLoadRejectsInvalidFiles(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"no up script":   {"m/0001_a.down.sql": {Data: []byte("x")}},
+		"bad name":       {"m/0001-a.up.sql": {Data: []byte("x")}},
+		"wrong type":     {"m/0001_a.up.json": {Data: []byte("[]")}},
+		"version clash":  {"m/0001_a.up.sql": {Data: []byte("x")}, "m/0001_b.up.sql": {Data: []byte("y")}},
+		"zero version":   {"m/0000_a.up.sql": {Data: []byte("x")}},
+		"stray document": {"m/README.md": {Data: []byte("x")}},
+	}
+	for name, fsys := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := load(fsys, "m", "sql"); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestPending(t *testing.T) {
+	migrations, _ := load(testFS(), "m", "sql")
+
+	todo, err := pending(migrations, []Record{{Version: 1, Name: "create_table", Checksum: migrations[0].Checksum}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(todo) != 2 || todo[0].Version != 2 || todo[1].Version != 3 {
+		t.Errorf("pending = %v, want versions 2 and 3", todo)
+	}
+
+	_, err = pending(migrations, []Record{{Version: 1, Name: "create_table", Checksum: "changed"}})
+	if err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Errorf("modified migration: err = %v", err)
+	}
+
+	_, err = pending(migrations, []Record{{Version: 9, Name: "from_newer_binary"}})
+	if err == nil {
+		t.Error("unknown applied migration: expected an error")
+	}
+}
+
+func TestStatus(t *testing.T) {
+	migrations, _ := load(testFS(), "m", "sql")
+	statuses := status(migrations, []Record{
+		{Version: 1, Checksum: migrations[0].Checksum},
+		{Version: 2, Checksum: "changed"},
+		{Version: 7, Name: "gone"},
+	})
+
+	if len(statuses) != 4 {
+		t.Fatalf("got %d statuses, want 4", len(statuses))
+	}
+	if !statuses[0].Applied || statuses[0].Modified {
+		t.Errorf("version 1: %+v", statuses[0])
+	}
+	if !statuses[1].Modified {
+		t.Errorf("version 2 should be modified: %+v", statuses[1])
+	}
+	if statuses[2].Applied {
+		t.Errorf("version 3 should be pending: %+v", statuses[2])
+	}
+	if !statuses[3].Missing || statuses[3].Version != 7 {
+		t.Errorf("version 7 should be missing: %+v", statuses[3])
+	}
+}
+
+func TestRollbacks(t *testing.T) {
+	migrations, _ := load(testFS(), "m", "sql")
+	applied := []Record{{Version: 1}, {Version: 2}}
+
+	todo, err := rollbacks(migrations, applied, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(todo) != 1 || todo[0].Version != 2 {
+		t.Errorf("rollbacks = %v, want version 2", todo)
+	}
+
+	// Version 1 has no down script
+	if _, err := rollbacks(migrations, applied, 5); err == nil {
+		t.Error("expected an error for a migration without down script")
+	}
+}
+
+func TestParseCommands(t *testing.T) {
+	commands, err := parseCommands(`[{"createIndexes": "c", "indexes": [{"key": {"b": 1, "a": -1}, "name": "b_1_a_-1"}]}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commands) != 1 || commands[0][0].Key != "createIndexes" {
+		t.Errorf("commands = %v", commands)
+	}
+
+	if _, err := parseCommands(`[{}]`); err == nil {
+		t.Error("expected an error for an empty command")
+	}
+	if _, err := parseCommands(`{"create": "c"}`); err == nil {
+		t.Error("expected an error for a script that is not an array")
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	if _, err := NewPostgres(nil); err != nil {
+		t.Error(err)
+	}
+	if _, err := NewMongo(nil); err != nil {
+		t.Error(err)
+	}
+}
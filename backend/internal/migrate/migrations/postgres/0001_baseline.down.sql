@@ -0,0 +1,10 @@
+-- Drops every table of the baseline schema, with all collected data
+DROP TABLE IF EXISTS backfill_coverage;
+DROP TABLE IF EXISTS trend_record_items;
+DROP TABLE IF EXISTS trend_items;
+DROP TABLE IF EXISTS metric_series;
+DROP TABLE IF EXISTS trend_record_sources;
+DROP TABLE IF EXISTS trend_records;
+DROP TABLE IF EXISTS keywords;
+DROP TABLE IF EXISTS auth_tokens;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,203 @@
+-- Baseline schema. Databases created by the former init scripts are adopted
+-- by this migration: tables are only created when missing, and the blocks
+-- below bring older layouts up to date.
+
+CREATE TABLE IF NOT EXISTS users (
+    id SERIAL PRIMARY KEY,
+    email VARCHAR(255) UNIQUE NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Tokyo', -- IANA time zone used to interpret and present dates
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS auth_tokens (
+    token_id UUID PRIMARY KEY,
+    user_id INT REFERENCES users(id),
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_user ON auth_tokens(user_id);
+
+CREATE TABLE IF NOT EXISTS keywords (
+    id SERIAL PRIMARY KEY,
+    user_id INT REFERENCES users(id),
+    keyword VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE(user_id, keyword)
+);
+
+-- record_date is the start of the bucket in the collection time zone;
+-- pipeline_version 0 marks records computed before item-level storage
+CREATE TABLE IF NOT EXISTS trend_records (
+    id BIGSERIAL PRIMARY KEY,
+    keyword_id INT REFERENCES keywords(id),
+    record_date TIMESTAMPTZ NOT NULL,
+    volume INT NOT NULL,
+    engagement FLOAT NOT NULL DEFAULT 0,
+    sentiment FLOAT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    pipeline_version INT NOT NULL DEFAULT 0,
+    UNIQUE(keyword_id, record_date)
+);
+
+-- fetch_ok = FALSE marks a day the source could not be fetched, as opposed
+-- to a day it had nothing
+CREATE TABLE IF NOT EXISTS trend_record_sources (
+    keyword_id INT REFERENCES keywords(id) ON DELETE CASCADE,
+    record_date TIMESTAMPTZ NOT NULL,
+    source VARCHAR(100) NOT NULL,
+    volume INT NOT NULL DEFAULT 0,
+    engagement FLOAT NOT NULL DEFAULT 0,
+    sentiment FLOAT NOT NULL DEFAULT 0.5,
+    fetch_ok BOOLEAN NOT NULL DEFAULT TRUE,
+    fetch_error TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (keyword_id, record_date, source)
+);
+
+-- dimensions holds the breakdown (e.g. {"source": "wwd.com"}); '{}' is the
+-- keyword total. Hourly values are rolled up to days by daily queries.
+CREATE TABLE IF NOT EXISTS metric_series (
+    keyword_id INT REFERENCES keywords(id) ON DELETE CASCADE,
+    metric VARCHAR(64) NOT NULL,
+    granularity VARCHAR(10) NOT NULL DEFAULT 'day',
+    bucket_start TIMESTAMPTZ NOT NULL,
+    value DOUBLE PRECISION NOT NULL,
+    dimensions JSONB NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE(keyword_id, metric, granularity, bucket_start, dimensions)
+);
+
+CREATE INDEX IF NOT EXISTS idx_metric_series_lookup ON metric_series(keyword_id, metric, granularity, bucket_start);
+
+-- Collected items that trend records are aggregated from; relevance 0 means
+-- excluded, pipeline_version is the rule set that scored the item
+CREATE TABLE IF NOT EXISTS trend_items (
+    id BIGSERIAL PRIMARY KEY,
+    keyword_id INT REFERENCES keywords(id) ON DELETE CASCADE,
+    item_key VARCHAR(40) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    source VARCHAR(100) NOT NULL,
+    url TEXT NOT NULL DEFAULT '',
+    external_id VARCHAR(255) NOT NULL DEFAULT '',
+    title TEXT NOT NULL DEFAULT '',
+    content TEXT NOT NULL DEFAULT '',
+    image_url TEXT NOT NULL DEFAULT '',
+    author VARCHAR(255) NOT NULL DEFAULT '',
+    language VARCHAR(10) NOT NULL DEFAULT '',
+    published_at TIMESTAMPTZ NOT NULL,
+    bucket_start TIMESTAMPTZ NOT NULL,
+    like_count INT NOT NULL DEFAULT 0,
+    comment_count INT NOT NULL DEFAULT 0,
+    repost_count INT NOT NULL DEFAULT 0,
+    relevance FLOAT NOT NULL DEFAULT 0,
+    sentiment FLOAT NOT NULL DEFAULT 0.5,
+    engagement FLOAT NOT NULL DEFAULT 0,
+    provenance VARCHAR(20) NOT NULL DEFAULT '',
+    pipeline_version INT NOT NULL DEFAULT 0,
+    collected_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE(keyword_id, item_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_trend_items_bucket ON trend_items(keyword_id, bucket_start);
+CREATE INDEX IF NOT EXISTS idx_trend_items_published ON trend_items(keyword_id, published_at);
+
+-- Items counted in each trend record, for drilling down from a data point
+CREATE TABLE IF NOT EXISTS trend_record_items (
+    keyword_id INT REFERENCES keywords(id) ON DELETE CASCADE,
+    record_date TIMESTAMPTZ NOT NULL,
+    item_id BIGINT REFERENCES trend_items(id) ON DELETE CASCADE,
+    PRIMARY KEY (keyword_id, record_date, item_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_trend_record_items_item ON trend_record_items(item_id);
+
+-- status: collected (items found), empty (reachable but nothing matched) or
+-- uncovered (no source reached back that far)
+CREATE TABLE IF NOT EXISTS backfill_coverage (
+    keyword_id INT REFERENCES keywords(id) ON DELETE CASCADE,
+    coverage_date DATE NOT NULL,
+    item_count INT NOT NULL DEFAULT 0,
+    sources TEXT[] NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL,
+    updated_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (keyword_id, coverage_date)
+);
+
+-- Upgrade layouts created by earlier init scripts
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns
+                   WHERE table_name = 'users'
+                   AND column_name = 'timezone') THEN
+        ALTER TABLE users ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Tokyo';
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns
+                   WHERE table_name = 'trend_records'
+                   AND column_name = 'updated_at') THEN
+        ALTER TABLE trend_records ADD COLUMN updated_at TIMESTAMP DEFAULT NOW();
+    END IF;
+
+    -- Existing rows have no engagement data, so they fall back to their raw volume
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns
+                   WHERE table_name = 'trend_records'
+                   AND column_name = 'engagement') THEN
+        ALTER TABLE trend_records ADD COLUMN engagement FLOAT NOT NULL DEFAULT 0;
+        UPDATE trend_records SET engagement = volume;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns
+                   WHERE table_name = 'trend_records'
+                   AND column_name = 'pipeline_version') THEN
+        ALTER TABLE trend_records ADD COLUMN pipeline_version INT NOT NULL DEFAULT 0;
+    END IF;
+
+    -- Calendar dates become bucket start instants at midnight UTC, their previous meaning
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'trend_records'
+               AND column_name = 'record_date'
+               AND data_type = 'date') THEN
+        ALTER TABLE trend_records
+            ALTER COLUMN record_date TYPE TIMESTAMPTZ USING record_date::timestamp AT TIME ZONE 'UTC';
+    END IF;
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'trend_record_sources'
+               AND column_name = 'record_date'
+               AND data_type = 'date') THEN
+        ALTER TABLE trend_record_sources
+            ALTER COLUMN record_date TYPE TIMESTAMPTZ USING record_date::timestamp AT TIME ZONE 'UTC';
+    END IF;
+END $$;
+
+-- Copy trend records and their per-source breakdown into metric_series
+INSERT INTO metric_series (keyword_id, metric, granularity, bucket_start, value, dimensions)
+SELECT keyword_id, m.metric, 'day', record_date, m.value, '{}'::jsonb
+FROM trend_records
+CROSS JOIN LATERAL (VALUES
+    ('volume', volume::double precision),
+    ('engagement', engagement),
+    ('sentiment', sentiment)
+) AS m(metric, value)
+ON CONFLICT (keyword_id, metric, granularity, bucket_start, dimensions) DO NOTHING;
+
+INSERT INTO metric_series (keyword_id, metric, granularity, bucket_start, value, dimensions)
+SELECT keyword_id, m.metric, 'day', record_date, m.value, jsonb_build_object('source', source)
+FROM trend_record_sources
+CROSS JOIN LATERAL (VALUES
+    ('volume', volume::double precision),
+    ('engagement', engagement),
+    ('sentiment', sentiment)
+) AS m(metric, value)
+ON CONFLICT (keyword_id, metric, granularity, bucket_start, dimensions) DO NOTHING;
+
+-- Link items stored before lineage was recorded to the records of their buckets
+INSERT INTO trend_record_items (keyword_id, record_date, item_id)
+SELECT i.keyword_id, i.bucket_start, i.id
+FROM trend_items i
+JOIN trend_records r ON r.keyword_id = i.keyword_id AND r.record_date = i.bucket_start
+WHERE i.relevance > 0 AND r.pipeline_version > 0
+ON CONFLICT DO NOTHING;
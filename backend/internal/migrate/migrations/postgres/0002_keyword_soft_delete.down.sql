@@ -0,0 +1,12 @@
+-- Soft-deleted keywords cannot be represented without deleted_at; purge them
+DELETE FROM keywords WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE trend_records DROP CONSTRAINT IF EXISTS trend_records_keyword_id_fkey;
+ALTER TABLE trend_records
+    ADD CONSTRAINT trend_records_keyword_id_fkey FOREIGN KEY (keyword_id) REFERENCES keywords(id);
+
+DROP INDEX IF EXISTS idx_keywords_deleted_at;
+DROP INDEX IF EXISTS idx_keywords_user_keyword_active;
+ALTER TABLE keywords ADD CONSTRAINT keywords_user_id_keyword_key UNIQUE (user_id, keyword);
+
+ALTER TABLE keywords DROP COLUMN deleted_at;
@@ -0,0 +1,12 @@
+-- Soft delete of keywords. A deleted keyword keeps its data until it is
+-- purged after the grace period, and its text can be reused meanwhile.
+ALTER TABLE keywords ADD COLUMN deleted_at TIMESTAMPTZ;
+
+ALTER TABLE keywords DROP CONSTRAINT IF EXISTS keywords_user_id_keyword_key;
+CREATE UNIQUE INDEX idx_keywords_user_keyword_active ON keywords(user_id, keyword) WHERE deleted_at IS NULL;
+CREATE INDEX idx_keywords_deleted_at ON keywords(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Trend records were the only keyword data not removed with the keyword
+ALTER TABLE trend_records DROP CONSTRAINT IF EXISTS trend_records_keyword_id_fkey;
+ALTER TABLE trend_records
+    ADD CONSTRAINT trend_records_keyword_id_fkey FOREIGN KEY (keyword_id) REFERENCES keywords(id) ON DELETE CASCADE;
@@ -0,0 +1,14 @@
+-- Keyword definitions over time. A rename adds a version instead of silently
+-- continuing the series under a different query.
+CREATE TABLE keyword_versions (
+    id SERIAL PRIMARY KEY,
+    keyword_id INT NOT NULL REFERENCES keywords(id) ON DELETE CASCADE,
+    version INT NOT NULL,
+    keyword VARCHAR(100) NOT NULL,
+    effective_from TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE(keyword_id, version)
+);
+
+-- Existing keywords start with their current text as the first version
+INSERT INTO keyword_versions (keyword_id, version, keyword, effective_from)
+SELECT id, 1, keyword, COALESCE(created_at, NOW()) FROM keywords;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS forecast_points;
+DROP TABLE IF EXISTS forecasts;
@@ -0,0 +1,35 @@
+-- Forecasts issued by the prediction endpoint, kept to be scored against the
+-- actual values. issue_date is the last day of the data the forecast was fitted
+-- on; a forecast issued again for the same day and settings replaces the earlier one.
+CREATE TABLE forecasts (
+    id SERIAL PRIMARY KEY,
+    keyword_id INT NOT NULL REFERENCES keywords(id) ON DELETE CASCADE,
+    metric VARCHAR(100) NOT NULL,
+    sources TEXT[] NOT NULL DEFAULT '{}',
+    requested_model VARCHAR(20) NOT NULL,
+    model VARCHAR(20) NOT NULL,
+    spec VARCHAR(200) NOT NULL,
+    issue_date TIMESTAMPTZ NOT NULL,
+    horizon INT NOT NULL,
+    issued_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE(keyword_id, metric, sources, requested_model, issue_date, horizon)
+);
+
+CREATE INDEX idx_forecasts_keyword_issue ON forecasts(keyword_id, issue_date);
+
+-- target_date is the start of the forecast day in the collection time zone.
+-- intervals holds [{"level", "lower", "upper"}]; actual is set once the day
+-- has been collected.
+CREATE TABLE forecast_points (
+    forecast_id INT NOT NULL REFERENCES forecasts(id) ON DELETE CASCADE,
+    target_date TIMESTAMPTZ NOT NULL,
+    step INT NOT NULL,
+    value FLOAT NOT NULL,
+    std_err FLOAT NOT NULL,
+    intervals JSONB NOT NULL DEFAULT '[]',
+    actual FLOAT,
+    scored_at TIMESTAMPTZ,
+    PRIMARY KEY (forecast_id, target_date)
+);
+
+CREATE INDEX idx_forecast_points_unscored ON forecast_points(target_date) WHERE actual IS NULL;
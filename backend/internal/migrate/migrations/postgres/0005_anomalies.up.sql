@@ -0,0 +1,21 @@
+-- Spikes and drops detected in a keyword's daily metrics. anomaly_date is the
+-- start of the day in the collection time zone; item_ids lists the items that
+-- explain the anomaly, most contributing first. Detection replaces the
+-- anomalies of the days it covers.
+CREATE TABLE anomalies (
+    id SERIAL PRIMARY KEY,
+    keyword_id INT NOT NULL REFERENCES keywords(id) ON DELETE CASCADE,
+    metric VARCHAR(100) NOT NULL,
+    anomaly_date TIMESTAMPTZ NOT NULL,
+    kind VARCHAR(10) NOT NULL,
+    severity VARCHAR(10) NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    value FLOAT NOT NULL,
+    expected FLOAT NOT NULL,
+    score FLOAT NOT NULL,
+    item_ids BIGINT[] NOT NULL DEFAULT '{}',
+    detected_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE(keyword_id, metric, anomaly_date)
+);
+
+CREATE INDEX idx_anomalies_keyword_date ON anomalies(keyword_id, anomaly_date);
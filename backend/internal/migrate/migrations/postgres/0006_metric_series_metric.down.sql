@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_metric_series_metric;
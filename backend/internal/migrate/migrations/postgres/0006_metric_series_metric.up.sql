@@ -0,0 +1,2 @@
+-- Lets a metric name be checked against the stored series without a keyword
+CREATE INDEX idx_metric_series_metric ON metric_series(metric);
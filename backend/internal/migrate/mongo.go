@@ -0,0 +1,180 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoDB error codes that make a migration command a no-op
+const (
+	mongoNamespaceNotFound = 26
+	mongoIndexNotFound     = 27
+	mongoNamespaceExists   = 48
+)
+
+// Mongo applies the migrations in migrations/mongo. Each script is a JSON
+// array of database commands in MongoDB extended JSON, run in order with
+// runCommand. Creating an existing collection and dropping a missing
+// collection or index are not errors, so scripts can adopt databases set up
+// before migrations existed. Rollbacks drop indexes but keep collections,
+// which hold collected content.
+type Mongo struct {
+	db         *mongo.Database
+	migrations []Migration
+}
+
+// mongoRecord is a document of the schema_migrations collection
+type mongoRecord struct {
+	Version   int64     `bson:"_id"`
+	Name      string    `bson:"name"`
+	Checksum  string    `bson:"checksum"`
+	AppliedAt time.Time `bson:"applied_at"`
+}
+
+// NewMongo creates a migrator for the embedded MongoDB migrations
+func NewMongo(db *mongo.Database) (*Mongo, error) {
+	migrations, err := load(embedded, "migrations/mongo", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load mongo migrations: %w", err)
+	}
+	for _, m := range migrations {
+		if _, err := parseCommands(m.Up); err != nil {
+			return nil, fmt.Errorf("invalid mongo migration %d_%s: %w", m.Version, m.Name, err)
+		}
+		if _, err := parseCommands(m.Down); m.Down != "" && err != nil {
+			return nil, fmt.Errorf("invalid mongo migration %d_%s: %w", m.Version, m.Name, err)
+		}
+	}
+	return &Mongo{db: db, migrations: migrations}, nil
+}
+
+// Database implements Migrator
+func (m *Mongo) Database() string {
+	return "mongo"
+}
+
+// Status implements Migrator
+func (m *Mongo) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return status(m.migrations, applied), nil
+}
+
+// Up implements Migrator
+func (m *Mongo) Up(ctx context.Context, dryRun bool) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	todo, err := pending(m.migrations, applied)
+	if err != nil || dryRun {
+		return todo, err
+	}
+
+	for _, migration := range todo {
+		log.Printf("Applying mongo migration %d_%s", migration.Version, migration.Name)
+		if err := m.run(ctx, migration.Up); err != nil {
+			return todo, fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+		record := mongoRecord{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			Checksum:  migration.Checksum,
+			AppliedAt: time.Now(),
+		}
+		if _, err := m.db.Collection("schema_migrations").InsertOne(ctx, record); err != nil {
+			return todo, fmt.Errorf("failed to record migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+	}
+	return todo, nil
+}
+
+// Down implements Migrator
+func (m *Mongo) Down(ctx context.Context, steps int, dryRun bool) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	todo, err := rollbacks(m.migrations, applied, steps)
+	if err != nil || dryRun {
+		return todo, err
+	}
+
+	for _, migration := range todo {
+		log.Printf("Rolling back mongo migration %d_%s", migration.Version, migration.Name)
+		if err := m.run(ctx, migration.Down); err != nil {
+			return todo, fmt.Errorf("rollback of %d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+		if _, err := m.db.Collection("schema_migrations").DeleteOne(ctx, bson.M{"_id": migration.Version}); err != nil {
+			return todo, fmt.Errorf("failed to unrecord migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+	}
+	return todo, nil
+}
+
+// run executes the commands of a script
+func (m *Mongo) run(ctx context.Context, script string) error {
+	commands, err := parseCommands(script)
+	if err != nil {
+		return err
+	}
+	for _, command := range commands {
+		err := m.db.RunCommand(ctx, command).Err()
+		var cmdErr mongo.CommandError
+		if errors.As(err, &cmdErr) {
+			switch cmdErr.Code {
+			case mongoNamespaceExists, mongoNamespaceNotFound, mongoIndexNotFound:
+				continue
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("command %s: %w", command[0].Key, err)
+		}
+	}
+	return nil
+}
+
+// applied reads the schema_migrations collection
+func (m *Mongo) applied(ctx context.Context) ([]Record, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := m.db.Collection("schema_migrations").Find(ctx, bson.D{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var docs []mongoRecord
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, len(docs))
+	for i, d := range docs {
+		records[i] = Record{Version: d.Version, Name: d.Name, Checksum: d.Checksum, AppliedAt: d.AppliedAt}
+	}
+	return records, nil
+}
+
+// parseCommands parses a script into its commands, keeping key order since
+// the command name must come first
+func parseCommands(script string) ([]bson.D, error) {
+	var wrapper struct {
+		Commands []bson.D `bson:"commands"`
+	}
+	if err := bson.UnmarshalExtJSON([]byte(`{"commands":`+script+`}`), false, &wrapper); err != nil {
+		return nil, err
+	}
+	for i, command := range wrapper.Commands {
+		if len(command) == 0 {
+			return nil, fmt.Errorf("command %d is empty", i)
+		}
+	}
+	return wrapper.Commands, nil
+}
@@ -0,0 +1,154 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// postgresLockID is the advisory lock held while migrating, so that several
+// backend instances starting together migrate one at a time
+const postgresLockID = 7_346_020_138
+
+// Postgres applies the SQL migrations in migrations/postgres
+type Postgres struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+}
+
+// NewPostgres creates a migrator for the embedded PostgreSQL migrations
+func NewPostgres(pool *pgxpool.Pool) (*Postgres, error) {
+	migrations, err := load(embedded, "migrations/postgres", "sql")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load postgres migrations: %w", err)
+	}
+	return &Postgres{pool: pool, migrations: migrations}, nil
+}
+
+// Database implements Migrator
+func (p *Postgres) Database() string {
+	return "postgres"
+}
+
+// Status implements Migrator
+func (p *Postgres) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := p.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := p.applied(ctx, conn)
+		statuses = status(p.migrations, applied)
+		return err
+	})
+	return statuses, err
+}
+
+// Up implements Migrator. Each migration runs in its own transaction
+// together with its schema_migrations row.
+func (p *Postgres) Up(ctx context.Context, dryRun bool) ([]Migration, error) {
+	var todo []Migration
+	err := p.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := p.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if todo, err = pending(p.migrations, applied); err != nil || dryRun {
+			return err
+		}
+
+		for _, m := range todo {
+			log.Printf("Applying postgres migration %d_%s", m.Version, m.Name)
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, m.Up); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+					m.Version, m.Name, m.Checksum)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %d_%s failed: %w", m.Version, m.Name, err)
+			}
+		}
+		return nil
+	})
+	return todo, err
+}
+
+// Down implements Migrator
+func (p *Postgres) Down(ctx context.Context, steps int, dryRun bool) ([]Migration, error) {
+	var todo []Migration
+	err := p.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := p.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if todo, err = rollbacks(p.migrations, applied, steps); err != nil || dryRun {
+			return err
+		}
+
+		for _, m := range todo {
+			log.Printf("Rolling back postgres migration %d_%s", m.Version, m.Name)
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, m.Down); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, m.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("rollback of %d_%s failed: %w", m.Version, m.Name, err)
+			}
+		}
+		return nil
+	})
+	return todo, err
+}
+
+// withLock runs fn on a dedicated connection holding the migration lock,
+// after making sure the schema_migrations table exists
+func (p *Postgres) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := p.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, postgresLockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, postgresLockID)
+
+	if _, err := conn.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			checksum CHAR(64) NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// applied reads the schema_migrations table
+func (p *Postgres) applied(ctx context.Context, conn *pgxpool.Conn) ([]Record, error) {
+	rows, err := conn.Query(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations ORDER BY version`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []Record
+	for rows.Next() {
+		var r Record
+		if err := rows.Scan(&r.Version, &r.Name, &r.Checksum, &r.AppliedAt); err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
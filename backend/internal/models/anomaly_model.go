@@ -0,0 +1,137 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Anomaly is a spike or drop detected in a keyword's daily metric
+type Anomaly struct {
+	ID         int         `json:"id" db:"id"`
+	KeywordID  int         `json:"keyword_id" db:"keyword_id"`
+	Metric     string      `json:"metric" db:"metric"`
+	Date       time.Time   `json:"date" db:"anomaly_date"` // start of the day in the collection time zone
+	Kind       string      `json:"kind" db:"kind"`         // spike or drop
+	Severity   string      `json:"severity" db:"severity"` // low, medium or high
+	Method     string      `json:"method" db:"method"`     // detection method, mad or iqr
+	Value      float64     `json:"value" db:"value"`
+	Expected   float64     `json:"expected" db:"expected"`
+	Score      float64     `json:"score" db:"score"`
+	ItemIDs    []int64     `json:"item_ids" db:"item_ids"` // items explaining the anomaly, most contributing first
+	DetectedAt time.Time   `json:"detected_at" db:"detected_at"`
+	Items      []TrendItem `json:"items"` // loaded by GetAnomalies, in the order of ItemIDs
+}
+
+// ReplaceAnomalies stores the anomalies detected in a keyword's metric over
+// the days in [start, end), replacing those detected there before
+func ReplaceAnomalies(ctx context.Context, keywordID int, metric string, start, end time.Time, anomalies []Anomaly) error {
+	tx, err := PgPool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM anomalies
+		WHERE keyword_id = $1 AND metric = $2 AND anomaly_date >= $3 AND anomaly_date < $4
+	`, keywordID, metric, start, end); err != nil {
+		return err
+	}
+
+	batch := &pgx.Batch{}
+	for _, a := range anomalies {
+		itemIDs := a.ItemIDs
+		if itemIDs == nil {
+			itemIDs = []int64{}
+		}
+		batch.Queue(`
+			INSERT INTO anomalies (keyword_id, metric, anomaly_date, kind, severity, method, value, expected, score, item_ids)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			ON CONFLICT (keyword_id, metric, anomaly_date) DO UPDATE SET
+				kind = EXCLUDED.kind,
+				severity = EXCLUDED.severity,
+				method = EXCLUDED.method,
+				value = EXCLUDED.value,
+				expected = EXCLUDED.expected,
+				score = EXCLUDED.score,
+				item_ids = EXCLUDED.item_ids,
+				detected_at = NOW()
+		`, keywordID, metric, a.Date, a.Kind, a.Severity, a.Method, a.Value, a.Expected, a.Score, itemIDs)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// AnomalyQuery selects the anomalies of a keyword on days in [Start, End)
+type AnomalyQuery struct {
+	KeywordID  int
+	Metrics    []string // all metrics when empty
+	Severities []string // all severities when empty
+	Start      time.Time
+	End        time.Time
+}
+
+// GetAnomalies retrieves the anomalies matching q with their items, newest first
+func GetAnomalies(ctx context.Context, q AnomalyQuery) ([]Anomaly, error) {
+	metrics := append([]string{}, q.Metrics...)
+	severities := append([]string{}, q.Severities...)
+
+	rows, err := PgPool.Query(ctx, `
+		SELECT id, keyword_id, metric, anomaly_date, kind, severity, method, value, expected, score, item_ids, detected_at
+		FROM anomalies
+		WHERE keyword_id = $1 AND anomaly_date >= $2 AND anomaly_date < $3
+			AND (cardinality($4::text[]) = 0 OR metric = ANY($4))
+			AND (cardinality($5::text[]) = 0 OR severity = ANY($5))
+		ORDER BY anomaly_date DESC, metric
+	`, q.KeywordID, q.Start, q.End, metrics, severities)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var anomalies []Anomaly
+	var itemIDs []int64
+	for rows.Next() {
+		var a Anomaly
+		if err := rows.Scan(&a.ID, &a.KeywordID, &a.Metric, &a.Date, &a.Kind, &a.Severity, &a.Method,
+			&a.Value, &a.Expected, &a.Score, &a.ItemIDs, &a.DetectedAt); err != nil {
+			return nil, err
+		}
+		anomalies = append(anomalies, a)
+		itemIDs = append(itemIDs, a.ItemIDs...)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(itemIDs) == 0 {
+		return anomalies, nil
+	}
+
+	// Items are loaded in one query and matched to their anomalies
+	items, err := queryTrendItems(ctx, `
+		SELECT `+trendItemColumns+`
+		FROM trend_items
+		WHERE keyword_id = $1 AND id = ANY($2)
+	`, q.KeywordID, itemIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]TrendItem, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+	for i := range anomalies {
+		for _, id := range anomalies[i].ItemIDs {
+			if item, ok := byID[id]; ok {
+				anomalies[i].Items = append(anomalies[i].Items, item)
+			}
+		}
+	}
+
+	return anomalies, nil
+}
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// LegacyArticleMigrationStats summarises a run of MigrateLegacyArticleImages
+type LegacyArticleMigrationStats struct {
+	Scanned         int `json:"scanned"`
+	ArticlesCreated int `json:"articles_created"`
+	ImagesKept      int `json:"images_kept"`
+	ImagesRemoved   int `json:"images_removed"`
+	Skipped         int `json:"skipped"`
+}
+
+// Captions written by the scraper before articles had their own collection:
+// "[source] title - content" from storeScrapedItems, and "title - content"
+// from the historical collection in KeywordController.
+var (
+	legacySourceCaption = regexp.MustCompile(`(?s)^\[([^\]]+)\] (.*?) - (.*)$`)
+	legacyPlainCaption  = regexp.MustCompile(`(?s)^(.+?) - (.*)$`)
+)
+
+// parseLegacyCaption splits a legacy caption into source, title and content.
+// "[source] title - content" is unambiguous, but " - " also appears in
+// ordinary captions, so a plain "title - content" caption is only split on
+// documents that carry the other marks of the historical collection.
+func parseLegacyCaption(image *Image) (source, title, content string, ok bool) {
+	if m := legacySourceCaption.FindStringSubmatch(image.Caption); m != nil {
+		return m[1], strings.TrimSpace(m[2]), strings.TrimSpace(m[3]), true
+	}
+	if !hasLegacyMarkers(image) {
+		return "", "", "", false
+	}
+	if m := legacyPlainCaption.FindStringSubmatch(image.Caption); m != nil {
+		return "", strings.TrimSpace(m[1]), strings.TrimSpace(m[2]), true
+	}
+	return "", "", "", false
+}
+
+// hasLegacyMarkers reports whether an image document looks like one written
+// by the historical collection: a placeholder image, or none of the fields
+// the current scraper records about where an image came from
+func hasLegacyMarkers(image *Image) bool {
+	if IsPlaceholderImageURL(image.ImageURL) {
+		return true
+	}
+	return image.PageURL == "" && image.Language == "" && image.Provenance == ""
+}
+
+// MigrateLegacyArticleImages moves articles that were stored as documents in
+// the images collection into blog_articles. Images with a real image URL are
+// kept with the article title as caption; placeholder images are removed.
+// Documents that already have a source (written by the current scraper),
+// whose caption cannot be parsed or that do not look like legacy documents
+// are left untouched. With dryRun set nothing is written and the stats
+// describe what would change.
+func MigrateLegacyArticleImages(ctx context.Context, dryRun bool) (*LegacyArticleMigrationStats, error) {
+	stats := &LegacyArticleMigrationStats{}
+
+	cursor, err := imagesCollection().Find(ctx, bson.M{"source": bson.M{"$exists": false}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var image Image
+		if err := cursor.Decode(&image); err != nil {
+			return stats, err
+		}
+		stats.Scanned++
+
+		source, title, content, ok := parseLegacyCaption(&image)
+		if !ok || title == "" {
+			stats.Skipped++
+			continue
+		}
+
+		realImage := !IsPlaceholderImageURL(image.ImageURL)
+		article := &BlogArticle{
+			KeywordID:   image.KeywordID,
+			Source:      source,
+			Title:       title,
+			Content:     content,
+			Tags:        image.Tags,
+			PublishDate: image.FetchedAt,
+		}
+		if realImage {
+			article.ImageURL = image.ImageURL
+		}
+
+		if !dryRun {
+			if err := UpsertBlogArticle(ctx, article); err != nil {
+				return stats, err
+			}
+		}
+		stats.ArticlesCreated++
+
+		if realImage {
+			if !dryRun {
+				_, err := imagesCollection().UpdateOne(ctx, bson.M{"_id": image.ID}, bson.M{
+					"$set": bson.M{"caption": title, "source": source},
+				})
+				if err != nil {
+					return stats, err
+				}
+			}
+			stats.ImagesKept++
+			continue
+		}
+
+		if !dryRun {
+			if err := DeleteImage(ctx, image.ID); err != nil {
+				return stats, err
+			}
+		}
+		stats.ImagesRemoved++
+	}
+
+	return stats, cursor.Err()
+}
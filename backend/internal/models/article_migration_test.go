@@ -0,0 +1,52 @@
+package models
+
+import "testing"
+
+func TestParseLegacyCaption(t *testing.T) {
+	tests := []struct {
+		name   string
+		image  Image
+		ok     bool
+		source string
+		title  string
+	}{
+		{
+			name:   "scraper caption",
+			image:  Image{ImageURL: "https://cdn.vogue.com/a.jpg", PageURL: "https://vogue.com/a", Caption: "[vogue.com] Quiet luxury - Muted tones return"},
+			ok:     true,
+			source: "vogue.com",
+			title:  "Quiet luxury",
+		},
+		{
+			name:  "historical caption with placeholder image",
+			image: Image{ImageURL: "https://picsum.photos/400/300", Caption: "Quiet luxury - Muted tones return"},
+			ok:    true,
+			title: "Quiet luxury",
+		},
+		{
+			name:  "historical caption without structured fields",
+			image: Image{ImageURL: "https://cdn.example.org/a.jpg", Caption: "Quiet luxury - Muted tones return"},
+			ok:    true,
+			title: "Quiet luxury",
+		},
+		{
+			name:  "ordinary caption containing a dash",
+			image: Image{ImageURL: "https://cdn.example.org/b.jpg", PageURL: "https://example.org/show", Caption: "Spring/Summer - Paris"},
+		},
+		{
+			name:  "ordinary caption with provenance",
+			image: Image{ImageURL: "https://cdn.example.org/c.jpg", Provenance: "feed", Caption: "Spring/Summer - Paris"},
+		},
+		{
+			name:  "caption without a dash",
+			image: Image{ImageURL: "https://picsum.photos/400/300", Caption: "Quiet luxury"},
+		},
+	}
+
+	for _, tt := range tests {
+		source, title, _, ok := parseLegacyCaption(&tt.image)
+		if ok != tt.ok || source != tt.source || title != tt.title {
+			t.Errorf("%s: got (%q, %q, %v), want (%q, %q, %v)", tt.name, source, title, ok, tt.source, tt.title, tt.ok)
+		}
+	}
+}
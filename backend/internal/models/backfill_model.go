@@ -0,0 +1,78 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Backfill coverage statuses for a single day
+const (
+	BackfillStatusCollected = "collected" // items published on this day were found
+	BackfillStatusEmpty     = "empty"     // sources reached back to this day but had no matching items
+	BackfillStatusUncovered = "uncovered" // no source could reach back to this day
+)
+
+// BackfillCoverage records how well historical collection covered one day
+type BackfillCoverage struct {
+	KeywordID int       `json:"keyword_id" db:"keyword_id"`
+	Date      time.Time `json:"date" db:"coverage_date"`
+	ItemCount int       `json:"item_count" db:"item_count"`
+	Sources   []string  `json:"sources" db:"sources"` // sources whose archives reached this day
+	Status    string    `json:"status" db:"status"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// SaveBackfillCoverage stores the per-day coverage of a backfill run,
+// replacing any earlier result for the same days
+func SaveBackfillCoverage(ctx context.Context, keywordID int, days []BackfillCoverage) error {
+	query := `
+		INSERT INTO backfill_coverage (keyword_id, coverage_date, item_count, sources, status, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (keyword_id, coverage_date) DO UPDATE SET
+			item_count = EXCLUDED.item_count,
+			sources = EXCLUDED.sources,
+			status = EXCLUDED.status,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	now := time.Now()
+	batch := &pgx.Batch{}
+	for _, day := range days {
+		sources := day.Sources
+		if sources == nil {
+			sources = []string{}
+		}
+		batch.Queue(query, keywordID, day.Date, day.ItemCount, sources, day.Status, now)
+	}
+
+	return PgPool.SendBatch(ctx, batch).Close()
+}
+
+// GetBackfillCoverage retrieves the per-day backfill coverage for a keyword
+func GetBackfillCoverage(ctx context.Context, keywordID int) ([]BackfillCoverage, error) {
+	query := `
+		SELECT keyword_id, coverage_date, item_count, sources, status, updated_at
+		FROM backfill_coverage
+		WHERE keyword_id = $1
+		ORDER BY coverage_date ASC
+	`
+
+	rows, err := PgPool.Query(ctx, query, keywordID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []BackfillCoverage
+	for rows.Next() {
+		var day BackfillCoverage
+		if err := rows.Scan(&day.KeywordID, &day.Date, &day.ItemCount, &day.Sources, &day.Status, &day.UpdatedAt); err != nil {
+			return nil, err
+		}
+		days = append(days, day)
+	}
+
+	return days, rows.Err()
+}
@@ -2,6 +2,9 @@ package models
 
 import (
 	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -14,12 +17,18 @@ import (
 type BlogArticle struct {
 	ID          string    `bson:"_id,omitempty" json:"id"`
 	KeywordID   int       `bson:"keyword_id" json:"keyword_id"`
+	Source      string    `bson:"source" json:"source"`
 	Title       string    `bson:"title" json:"title"`
 	URL         string    `bson:"url" json:"url"`
 	Author      string    `bson:"author" json:"author"`
 	Content     string    `bson:"content" json:"content"`
+	ImageURL    string    `bson:"image_url,omitempty" json:"image_url,omitempty"`
+	Tags        []string  `bson:"tags" json:"tags"`
+	Language    string    `bson:"language" json:"language"`
+	Provenance  string    `bson:"provenance" json:"provenance"`
 	PublishDate time.Time `bson:"publish_date" json:"publish_date"`
 	CreatedAt   time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `bson:"updated_at" json:"updated_at"`
 }
 
 // articlesCollection returns the blog articles collection
@@ -37,6 +46,55 @@ func CreateBlogArticle(ctx context.Context, article *BlogArticle) error {
 	return err
 }
 
+// BlogArticleID builds the document ID for an article collected for a keyword.
+// Articles are identified by URL; the title is used when the URL is unknown.
+func BlogArticleID(keywordID int, url, title string) string {
+	ref := url
+	if ref == "" {
+		ref = title
+	}
+	sum := sha1.Sum([]byte(ref))
+	return fmt.Sprintf("%d:%s", keywordID, hex.EncodeToString(sum[:]))
+}
+
+// UpsertBlogArticle stores an article, updating it in place when the same
+// article has already been collected for the keyword
+func UpsertBlogArticle(ctx context.Context, article *BlogArticle) error {
+	if article.ID == "" {
+		article.ID = BlogArticleID(article.KeywordID, article.URL, article.Title)
+	}
+
+	now := time.Now()
+	if article.CreatedAt.IsZero() {
+		article.CreatedAt = now
+	}
+	article.UpdatedAt = now
+
+	update := bson.M{
+		"$set": bson.M{
+			"keyword_id":   article.KeywordID,
+			"source":       article.Source,
+			"title":        article.Title,
+			"url":          article.URL,
+			"author":       article.Author,
+			"content":      article.Content,
+			"image_url":    article.ImageURL,
+			"tags":         article.Tags,
+			"language":     article.Language,
+			"provenance":   article.Provenance,
+			"publish_date": article.PublishDate,
+			"updated_at":   article.UpdatedAt,
+		},
+		"$setOnInsert": bson.M{
+			"created_at": article.CreatedAt,
+		},
+	}
+
+	_, err := articlesCollection().UpdateOne(ctx,
+		bson.M{"_id": article.ID}, update, options.Update().SetUpsert(true))
+	return err
+}
+
 // GetBlogArticlesForKeyword retrieves blog articles for a specific keyword
 func GetBlogArticlesForKeyword(ctx context.Context, keywordID int, limit int) ([]*BlogArticle, error) {
 	if limit <= 0 {
@@ -47,7 +105,7 @@ func GetBlogArticlesForKeyword(ctx context.Context, keywordID int, limit int) ([
 		SetSort(bson.D{primitive.E{Key: "publish_date", Value: -1}}).
 		SetLimit(int64(limit))
 
-	cursor, err := articlesCollection().Find(ctx, 
+	cursor, err := articlesCollection().Find(ctx,
 		bson.M{"keyword_id": keywordID}, opts)
 	if err != nil {
 		return nil, err
@@ -62,10 +120,19 @@ func GetBlogArticlesForKeyword(ctx context.Context, keywordID int, limit int) ([
 	return articles, nil
 }
 
+// DeleteBlogArticlesForKeyword removes all blog articles of a keyword
+func DeleteBlogArticlesForKeyword(ctx context.Context, keywordID int) (int64, error) {
+	result, err := articlesCollection().DeleteMany(ctx, bson.M{"keyword_id": keywordID})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
 // GetBlogArticlesByDate retrieves blog articles published on a specific date
 func GetBlogArticlesByDate(ctx context.Context, date time.Time) ([]*BlogArticle, error) {
 	// 指定された日付の開始と終了を計算
-	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
 	endOfDay := startOfDay.Add(24 * time.Hour)
 
 	// 検索条件を設定
@@ -94,4 +161,31 @@ func GetBlogArticlesByDate(ctx context.Context, date time.Time) ([]*BlogArticle,
 	}
 
 	return articles, nil
-} 
\ No newline at end of file
+}
+
+// GetBlogArticlesByKeywordAndDateRange retrieves articles for a keyword published within a date range
+func GetBlogArticlesByKeywordAndDateRange(ctx context.Context, keywordID int, startDate, endDate time.Time) ([]BlogArticle, error) {
+	filter := bson.M{
+		"keyword_id": keywordID,
+		"publish_date": bson.M{
+			"$gte": startDate,
+			"$lte": endDate,
+		},
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{primitive.E{Key: "publish_date", Value: -1}})
+
+	cursor, err := articlesCollection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var articles []BlogArticle
+	if err := cursor.All(ctx, &articles); err != nil {
+		return nil, err
+	}
+
+	return articles, nil
+}
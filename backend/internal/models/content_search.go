@@ -0,0 +1,305 @@
+package models
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Content types returned by SearchContent
+const (
+	ContentTypeArticle = "article"
+	ContentTypePost    = "post"
+	ContentTypeImage   = "image"
+)
+
+// ErrInvalidCursor is returned when a search cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ContentSearchQuery describes a search over collected content.
+// Empty fields do not filter.
+type ContentSearchQuery struct {
+	Text       string
+	KeywordIDs []int // required: only content for these keywords is searched
+	Types      []string
+	Sources    []string
+	From       time.Time
+	To         time.Time
+	Language   string
+	Provenance string
+	Cursor     *ContentCursor
+	Limit      int
+}
+
+// ContentSearchResult is a single article, post or image matching a search
+type ContentSearchResult struct {
+	Type        string    `json:"type"`
+	ID          string    `json:"id"`
+	KeywordID   int       `json:"keyword_id"`
+	Source      string    `json:"source"`
+	Title       string    `json:"title,omitempty"`
+	Content     string    `json:"content"`
+	URL         string    `json:"url,omitempty"`
+	ImageURL    string    `json:"image_url,omitempty"`
+	Language    string    `json:"language,omitempty"`
+	Provenance  string    `json:"provenance,omitempty"`
+	PublishedAt time.Time `json:"published_at"`
+	Score       float64   `json:"score,omitempty"` // MongoDB text score when searching by text
+}
+
+// ContentCursor marks the position after the last returned result. Results
+// are ordered by publication date (newest first), then content type, then ID.
+type ContentCursor struct {
+	PublishedAt time.Time `json:"t"`
+	TypeRank    int       `json:"r"`
+	ID          string    `json:"id"`
+}
+
+// Encode returns the cursor as an opaque URL-safe string
+func (c *ContentCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeContentCursor parses a cursor produced by ContentCursor.Encode
+func DecodeContentCursor(s string) (*ContentCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c ContentCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// searchableCollection describes how a content collection maps onto search results
+type searchableCollection struct {
+	contentType string
+	rank        int // tie-breaker between collections in the result order
+	collection  func() *mongo.Collection
+	dateField   string
+	sourceField string
+	objectIDs   bool // _id is an ObjectID rather than a string
+	decode      func(cur *mongo.Cursor) (ContentSearchResult, error)
+}
+
+var searchableCollections = []searchableCollection{
+	{
+		contentType: ContentTypeArticle,
+		rank:        0,
+		collection:  articlesCollection,
+		dateField:   "publish_date",
+		sourceField: "source",
+		decode: func(cur *mongo.Cursor) (ContentSearchResult, error) {
+			var doc struct {
+				BlogArticle `bson:",inline"`
+				Score       float64 `bson:"score"`
+			}
+			err := cur.Decode(&doc)
+			return ContentSearchResult{
+				Type: ContentTypeArticle, ID: doc.ID, KeywordID: doc.KeywordID, Source: doc.Source,
+				Title: doc.Title, Content: doc.Content, URL: doc.URL, ImageURL: doc.ImageURL,
+				Language: doc.Language, Provenance: doc.Provenance, PublishedAt: doc.PublishDate, Score: doc.Score,
+			}, err
+		},
+	},
+	{
+		contentType: ContentTypePost,
+		rank:        1,
+		collection:  socialPostsCollection,
+		dateField:   "post_date",
+		sourceField: "platform",
+		decode: func(cur *mongo.Cursor) (ContentSearchResult, error) {
+			var doc struct {
+				SocialMediaPost `bson:",inline"`
+				Provenance      string  `bson:"provenance"`
+				Score           float64 `bson:"score"`
+			}
+			err := cur.Decode(&doc)
+			return ContentSearchResult{
+				Type: ContentTypePost, ID: doc.ID, KeywordID: doc.KeywordID, Source: doc.Platform,
+				Content: doc.Caption, URL: doc.URL, ImageURL: doc.ImageURL,
+				Language: doc.Language, Provenance: doc.Provenance, PublishedAt: doc.PostDate, Score: doc.Score,
+			}, err
+		},
+	},
+	{
+		contentType: ContentTypeImage,
+		rank:        2,
+		collection:  imagesCollection,
+		dateField:   "fetched_at",
+		sourceField: "source",
+		objectIDs:   true,
+		decode: func(cur *mongo.Cursor) (ContentSearchResult, error) {
+			var doc struct {
+				Image `bson:",inline"`
+				Score float64 `bson:"score"`
+			}
+			err := cur.Decode(&doc)
+			return ContentSearchResult{
+				Type: ContentTypeImage, ID: doc.ID.Hex(), KeywordID: doc.KeywordID, Source: doc.Source,
+				Content: doc.Caption, URL: doc.PageURL, ImageURL: doc.ImageURL,
+				Language: doc.Language, Provenance: doc.Provenance, PublishedAt: doc.FetchedAt, Score: doc.Score,
+			}, err
+		},
+	},
+}
+
+// SearchContent searches articles, posts and images with keyset pagination.
+// It returns up to q.Limit results and, when more results exist, the cursor
+// for the next page.
+func SearchContent(ctx context.Context, q ContentSearchQuery) ([]ContentSearchResult, *ContentCursor, error) {
+	if q.Limit <= 0 {
+		q.Limit = 20
+	}
+
+	types := make(map[string]bool)
+	for _, t := range q.Types {
+		types[t] = true
+	}
+
+	var results []ContentSearchResult
+	for _, sc := range searchableCollections {
+		if len(types) > 0 && !types[sc.contentType] {
+			continue
+		}
+
+		filter, ok := sc.filter(q)
+		if !ok {
+			continue // the cursor is past every document in this collection
+		}
+
+		opts := options.Find().
+			SetSort(bson.D{{Key: sc.dateField, Value: -1}, {Key: "_id", Value: -1}}).
+			SetLimit(int64(q.Limit + 1))
+		if q.Text != "" {
+			opts.SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}})
+		}
+
+		cursor, err := sc.collection().Find(ctx, filter, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		for cursor.Next(ctx) {
+			result, err := sc.decode(cursor)
+			if err != nil {
+				cursor.Close(ctx)
+				return nil, nil, err
+			}
+			results = append(results, result)
+		}
+		err = cursor.Err()
+		cursor.Close(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return resultLess(results[i], results[j])
+	})
+
+	if len(results) <= q.Limit {
+		return results, nil, nil
+	}
+
+	results = results[:q.Limit]
+	last := results[len(results)-1]
+	next := &ContentCursor{PublishedAt: last.PublishedAt, TypeRank: typeRank(last.Type), ID: last.ID}
+	return results, next, nil
+}
+
+// filter builds the MongoDB filter for one collection. It returns false when
+// the cursor excludes the whole collection.
+func (sc searchableCollection) filter(q ContentSearchQuery) (bson.M, bool) {
+	filter := bson.M{"keyword_id": bson.M{"$in": q.KeywordIDs}}
+
+	if q.Text != "" {
+		filter["$text"] = bson.M{"$search": q.Text}
+	}
+	if len(q.Sources) > 0 {
+		filter[sc.sourceField] = bson.M{"$in": q.Sources}
+	}
+	if q.Language != "" {
+		filter["language"] = q.Language
+	}
+	if q.Provenance != "" {
+		filter["provenance"] = q.Provenance
+	}
+
+	dateRange := bson.M{}
+	if !q.From.IsZero() {
+		dateRange["$gte"] = q.From
+	}
+	if !q.To.IsZero() {
+		dateRange["$lte"] = q.To
+	}
+
+	if c := q.Cursor; c != nil {
+		switch {
+		case sc.rank > c.TypeRank:
+			// Same-date documents of later types come after the cursor
+			dateRange["$lte"] = minTime(dateRange["$lte"], c.PublishedAt)
+		case sc.rank < c.TypeRank:
+			dateRange["$lt"] = c.PublishedAt
+		default:
+			var lastID interface{} = c.ID
+			if sc.objectIDs {
+				oid, err := primitive.ObjectIDFromHex(c.ID)
+				if err != nil {
+					return nil, false
+				}
+				lastID = oid
+			}
+			filter["$or"] = bson.A{
+				bson.M{sc.dateField: bson.M{"$lt": c.PublishedAt}},
+				bson.M{sc.dateField: c.PublishedAt, "_id": bson.M{"$lt": lastID}},
+			}
+		}
+	}
+
+	if len(dateRange) > 0 {
+		filter[sc.dateField] = dateRange
+	}
+
+	return filter, true
+}
+
+// minTime returns the earlier of an optional bound and t
+func minTime(bound interface{}, t time.Time) time.Time {
+	if b, ok := bound.(time.Time); ok && b.Before(t) {
+		return b
+	}
+	return t
+}
+
+// typeRank returns the ordering rank of a content type
+func typeRank(contentType string) int {
+	for _, sc := range searchableCollections {
+		if sc.contentType == contentType {
+			return sc.rank
+		}
+	}
+	return len(searchableCollections)
+}
+
+// resultLess orders results newest first, then by content type, then by descending ID
+func resultLess(a, b ContentSearchResult) bool {
+	if !a.PublishedAt.Equal(b.PublishedAt) {
+		return a.PublishedAt.After(b.PublishedAt)
+	}
+	if ra, rb := typeRank(a.Type), typeRank(b.Type); ra != rb {
+		return ra < rb
+	}
+	return a.ID > b.ID
+}
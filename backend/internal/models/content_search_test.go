@@ -0,0 +1,30 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestContentCursorRoundTrip(t *testing.T) {
+	cursor := &ContentCursor{
+		PublishedAt: time.Date(2026, 9, 3, 10, 30, 0, 123, time.UTC),
+		TypeRank:    2,
+		ID:          "66d6e3a0c2a4f1b2c3d4e5f6",
+	}
+	encoded := cursor.Encode()
+	decoded, err := DecodeContentCursor(encoded)
+	if err != nil {
+		t.Fatalf("DecodeContentCursor(%q): %v", encoded, err)
+	}
+	if !decoded.PublishedAt.Equal(cursor.PublishedAt) || decoded.TypeRank != cursor.TypeRank || decoded.ID != cursor.ID {
+		t.Errorf("round trip = %+v, want %+v", decoded, cursor)
+	}
+}
+
+func TestDecodeContentCursorRejectsInvalid(t *testing.T) {
+	for _, s := range []string{"", "not base64!", "bm90IGpzb24", (&ContentCursor{TypeRank: 1}).Encode()} {
+		if _, err := DecodeContentCursor(s); err != ErrInvalidCursor {
+			t.Errorf("DecodeContentCursor(%q) error = %v, want ErrInvalidCursor", s, err)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package models
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Forecast is a prediction issued for a keyword, kept to be scored against
+// the actual values as they are collected
+type Forecast struct {
+	ID             int             `json:"id" db:"id"`
+	KeywordID      int             `json:"keyword_id" db:"keyword_id"`
+	Metric         string          `json:"metric" db:"metric"`
+	Sources        []string        `json:"sources" db:"sources"`                 // source filter; empty for all sources
+	RequestedModel string          `json:"requested_model" db:"requested_model"` // model choice of the request, such as auto
+	Model          string          `json:"model" db:"model"`                     // volume model used, or ensemble
+	Spec           string          `json:"spec" db:"spec"`
+	IssueDate      time.Time       `json:"issue_date" db:"issue_date"` // last day of the data the forecast was fitted on
+	Horizon        int             `json:"horizon" db:"horizon"`
+	IssuedAt       time.Time       `json:"issued_at" db:"issued_at"`
+	Points         []ForecastPoint `json:"points"`
+}
+
+// ForecastPoint is the forecast of one day
+type ForecastPoint struct {
+	TargetDate time.Time          `json:"target_date" db:"target_date"`
+	Step       int                `json:"step" db:"step"` // days after the issue date
+	Value      float64            `json:"value" db:"value"`
+	StdErr     float64            `json:"std_err" db:"std_err"`
+	Intervals  []ForecastInterval `json:"intervals" db:"intervals"`
+	Actual     *float64           `json:"actual" db:"actual"` // nil until the day is scored
+}
+
+// ForecastInterval is a prediction interval of a forecast point
+type ForecastInterval struct {
+	Level float64 `json:"level"` // in (0, 1)
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+// SaveForecast stores an issued forecast, replacing the forecast issued
+// earlier for the same keyword, metric, sources, model choice, issue date and
+// horizon
+func SaveForecast(ctx context.Context, f *Forecast) error {
+	tx, err := PgPool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	sources := append([]string{}, f.Sources...)
+	sort.Strings(sources)
+
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO forecasts (keyword_id, metric, sources, requested_model, model, spec, issue_date, horizon)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (keyword_id, metric, sources, requested_model, issue_date, horizon) DO UPDATE SET
+			model = EXCLUDED.model,
+			spec = EXCLUDED.spec,
+			issued_at = NOW()
+		RETURNING id, issued_at
+	`, f.KeywordID, f.Metric, sources, f.RequestedModel, f.Model, f.Spec, f.IssueDate, f.Horizon).
+		Scan(&f.ID, &f.IssuedAt); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM forecast_points WHERE forecast_id = $1`, f.ID); err != nil {
+		return err
+	}
+	batch := &pgx.Batch{}
+	for _, p := range f.Points {
+		intervals := p.Intervals
+		if intervals == nil {
+			intervals = []ForecastInterval{}
+		}
+		batch.Queue(`
+			INSERT INTO forecast_points (forecast_id, target_date, step, value, std_err, intervals)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, f.ID, p.TargetDate, p.Step, p.Value, p.StdErr, intervals)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ForecastQuery selects forecasts of a keyword and metric issued in a range
+type ForecastQuery struct {
+	KeywordID int
+	Metric    string
+	Sources   []string // exact source filter; empty for forecasts over all sources
+	Models    []string // volume models used; all when empty
+	From      time.Time
+	To        time.Time
+}
+
+// GetForecasts retrieves the forecasts matching q with their points, oldest first
+func GetForecasts(ctx context.Context, q ForecastQuery) ([]Forecast, error) {
+	sources := append([]string{}, q.Sources...)
+	sort.Strings(sources)
+	models := append([]string{}, q.Models...)
+
+	rows, err := PgPool.Query(ctx, `
+		SELECT f.id, f.keyword_id, f.metric, f.sources, f.requested_model, f.model, f.spec,
+			f.issue_date, f.horizon, f.issued_at,
+			p.target_date, p.step, p.value, p.std_err, p.intervals, p.actual
+		FROM forecasts f
+		JOIN forecast_points p ON p.forecast_id = f.id
+		WHERE f.keyword_id = $1 AND f.metric = $2 AND f.sources = $3
+			AND f.issue_date BETWEEN $4 AND $5
+			AND (cardinality($6::text[]) = 0 OR f.model = ANY($6))
+		ORDER BY f.issue_date, f.id, p.step
+	`, q.KeywordID, q.Metric, sources, q.From, q.To, models)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanForecasts(rows)
+}
+
+// GetDueForecasts retrieves the forecasts with points not yet scored whose
+// day started in [since, before), with only those points
+func GetDueForecasts(ctx context.Context, since, before time.Time) ([]Forecast, error) {
+	rows, err := PgPool.Query(ctx, `
+		SELECT f.id, f.keyword_id, f.metric, f.sources, f.requested_model, f.model, f.spec,
+			f.issue_date, f.horizon, f.issued_at,
+			p.target_date, p.step, p.value, p.std_err, p.intervals, p.actual
+		FROM forecast_points p
+		JOIN forecasts f ON f.id = p.forecast_id
+		JOIN keywords k ON k.id = f.keyword_id AND k.deleted_at IS NULL
+		WHERE p.actual IS NULL AND p.target_date >= $1 AND p.target_date < $2
+		ORDER BY f.id, p.step
+	`, since, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanForecasts(rows)
+}
+
+// scanForecasts groups forecast point rows, ordered by forecast, into forecasts
+func scanForecasts(rows pgx.Rows) ([]Forecast, error) {
+	var forecasts []Forecast
+	for rows.Next() {
+		var f Forecast
+		var p ForecastPoint
+		if err := rows.Scan(&f.ID, &f.KeywordID, &f.Metric, &f.Sources, &f.RequestedModel, &f.Model, &f.Spec,
+			&f.IssueDate, &f.Horizon, &f.IssuedAt,
+			&p.TargetDate, &p.Step, &p.Value, &p.StdErr, &p.Intervals, &p.Actual); err != nil {
+			return nil, err
+		}
+		if n := len(forecasts); n == 0 || forecasts[n-1].ID != f.ID {
+			forecasts = append(forecasts, f)
+		}
+		last := &forecasts[len(forecasts)-1]
+		last.Points = append(last.Points, p)
+	}
+
+	return forecasts, rows.Err()
+}
+
+// SetForecastActuals records the actual values of forecast days, keyed by
+// the start of the target day
+func SetForecastActuals(ctx context.Context, forecastID int, actuals map[time.Time]float64) error {
+	batch := &pgx.Batch{}
+	for date, actual := range actuals {
+		batch.Queue(`
+			UPDATE forecast_points SET actual = $3, scored_at = NOW()
+			WHERE forecast_id = $1 AND target_date = $2
+		`, forecastID, date, actual)
+	}
+	return PgPool.SendBatch(ctx, batch).Close()
+}
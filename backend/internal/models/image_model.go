@@ -2,6 +2,8 @@ package models
 
 import (
 	"context"
+	"net/url"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -12,27 +14,44 @@ import (
 
 // Image represents an image document in MongoDB
 type Image struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	KeywordID int                `bson:"keyword_id" json:"keyword_id"`
-	ImageURL  string             `bson:"image_url" json:"image_url"`
-	Caption   string             `bson:"caption" json:"caption"`
-	Tags      []string           `bson:"tags" json:"tags"`
-	FetchedAt time.Time          `bson:"fetched_at" json:"fetched_at"`
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	KeywordID  int                `bson:"keyword_id" json:"keyword_id"`
+	ImageURL   string             `bson:"image_url" json:"image_url"`
+	Caption    string             `bson:"caption" json:"caption"`
+	Source     string             `bson:"source,omitempty" json:"source,omitempty"`
+	PageURL    string             `bson:"page_url,omitempty" json:"page_url,omitempty"` // article or post the image appeared in
+	Tags       []string           `bson:"tags" json:"tags"`
+	Language   string             `bson:"language,omitempty" json:"language,omitempty"`
+	Provenance string             `bson:"provenance,omitempty" json:"provenance,omitempty"`
+	FetchedAt  time.Time          `bson:"fetched_at" json:"fetched_at"`
 }
 
-// SocialMediaPost represents a social media post document in MongoDB
-type SocialMediaPost struct {
-	ID          string    `bson:"_id,omitempty"`
-	KeywordID   int       `bson:"keyword_id"`
-	Platform    string    `bson:"platform"`
-	PostID      string    `bson:"post_id"`
-	Username    string    `bson:"username"`
-	Caption     string    `bson:"caption"`
-	ImageURL    string    `bson:"image_url"`
-	LikeCount   int       `bson:"like_count"`
-	CommentCount int      `bson:"comment_count"`
-	PostDate    time.Time `bson:"post_date"`
-	CreatedAt   time.Time `bson:"created_at"`
+// placeholderImageHosts are image services used for generated stand-in
+// images; their URLs never point at real content
+var placeholderImageHosts = []string{
+	"picsum.photos",
+	"via.placeholder.com",
+	"placehold.co",
+	"example.com",
+}
+
+// IsPlaceholderImageURL reports whether an image URL is empty or points at a
+// placeholder service rather than a real image
+func IsPlaceholderImageURL(imageURL string) bool {
+	if imageURL == "" {
+		return true
+	}
+	u, err := url.Parse(imageURL)
+	if err != nil || u.Host == "" {
+		return true
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, placeholder := range placeholderImageHosts {
+		if host == placeholder || strings.HasSuffix(host, "."+placeholder) {
+			return true
+		}
+	}
+	return false
 }
 
 // imagesCollection returns the images collection
@@ -45,7 +64,7 @@ func CreateImage(ctx context.Context, image *Image) error {
 	if image.ID.IsZero() {
 		image.ID = primitive.NewObjectID()
 	}
-	
+
 	if image.FetchedAt.IsZero() {
 		image.FetchedAt = time.Now()
 	}
@@ -54,6 +73,31 @@ func CreateImage(ctx context.Context, image *Image) error {
 	return err
 }
 
+// UpsertImage stores an image once per keyword and image URL
+func UpsertImage(ctx context.Context, image *Image) error {
+	if image.FetchedAt.IsZero() {
+		image.FetchedAt = time.Now()
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"caption":    image.Caption,
+			"source":     image.Source,
+			"page_url":   image.PageURL,
+			"tags":       image.Tags,
+			"language":   image.Language,
+			"provenance": image.Provenance,
+		},
+		"$setOnInsert": bson.M{
+			"fetched_at": image.FetchedAt,
+		},
+	}
+
+	filter := bson.M{"keyword_id": image.KeywordID, "image_url": image.ImageURL}
+	_, err := imagesCollection().UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
 // GetImagesForKeyword retrieves images for a specific keyword
 func GetImagesForKeyword(ctx context.Context, keywordID int, limit int) ([]*Image, error) {
 	if limit <= 0 {
@@ -64,7 +108,7 @@ func GetImagesForKeyword(ctx context.Context, keywordID int, limit int) ([]*Imag
 		SetSort(bson.D{primitive.E{Key: "fetched_at", Value: -1}}).
 		SetLimit(int64(limit))
 
-	cursor, err := imagesCollection().Find(ctx, 
+	cursor, err := imagesCollection().Find(ctx,
 		bson.M{"keyword_id": keywordID}, opts)
 	if err != nil {
 		return nil, err
@@ -82,7 +126,7 @@ func GetImagesForKeyword(ctx context.Context, keywordID int, limit int) ([]*Imag
 // GetImagesByKeywordAndDate retrieves images for a specific keyword on a specific date
 func GetImagesByKeywordAndDate(ctx context.Context, keywordID int, date time.Time) ([]*Image, error) {
 	// 指定された日付の開始と終了を計算
-	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
 	endOfDay := startOfDay.Add(24 * time.Hour)
 
 	// 検索条件を設定
@@ -149,7 +193,7 @@ func GetImagesByTags(ctx context.Context, tags []string, limit int) ([]*Image, e
 		SetLimit(int64(limit))
 
 	filter := bson.M{"tags": bson.M{"$in": tags}}
-	
+
 	cursor, err := imagesCollection().Find(ctx, filter, opts)
 	if err != nil {
 		return nil, err
@@ -178,6 +222,15 @@ func DeleteImage(ctx context.Context, id primitive.ObjectID) error {
 	return nil
 }
 
+// DeleteImagesForKeyword removes all images of a keyword
+func DeleteImagesForKeyword(ctx context.Context, keywordID int) (int64, error) {
+	result, err := imagesCollection().DeleteMany(ctx, bson.M{"keyword_id": keywordID})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
 // GetImagesByKeywordAndDateRange retrieves images for a keyword within a date range
 func GetImagesByKeywordAndDateRange(ctx context.Context, keywordID int, startDate, endDate time.Time) ([]Image, error) {
 	filter := bson.M{
@@ -207,4 +260,4 @@ func GetImagesByKeywordAndDateRange(ctx context.Context, keywordID int, startDat
 	}
 
 	return images, cursor.Err()
-} 
\ No newline at end of file
+}
@@ -6,34 +6,49 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 // Keyword represents a keyword in the database
 type Keyword struct {
-	ID        int       `json:"id"`
-	UserID    int       `json:"user_id"`
-	Keyword   string    `json:"keyword"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        int        `json:"id"`
+	UserID    int        `json:"user_id"`
+	Keyword   string     `json:"keyword"`
+	CreatedAt time.Time  `json:"created_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"` // set while the keyword awaits purging
 }
 
-// CreateKeyword adds a new keyword for a user
-func CreateKeyword(ctx context.Context, userID int, keyword string) (*Keyword, error) {
+// ErrKeywordNotFound is returned when a keyword to change does not exist
+var ErrKeywordNotFound = errors.New("keyword not found")
+
+// ErrKeywordConflict is returned when renaming or restoring a keyword would
+// duplicate the text of another active keyword of the same user
+var ErrKeywordConflict = errors.New("keyword already exists")
+
+// keywordColumns lists the columns read by scanKeyword, in order
+const keywordColumns = `id, user_id, keyword, created_at, deleted_at`
+
+// scanKeyword scans a row selected with keywordColumns
+func scanKeyword(row pgx.Row) (*Keyword, error) {
 	var k Keyword
-	err := PgPool.QueryRow(ctx,
-		`INSERT INTO keywords (user_id, keyword) VALUES ($1, $2) RETURNING id, user_id, keyword, created_at`,
-		userID, keyword).Scan(&k.ID, &k.UserID, &k.Keyword, &k.CreatedAt)
-	if err != nil {
+	if err := row.Scan(&k.ID, &k.UserID, &k.Keyword, &k.CreatedAt, &k.DeletedAt); err != nil {
 		return nil, err
 	}
-
 	return &k, nil
 }
 
-// GetKeywordsForUser retrieves all keywords for a specific user
-func GetKeywordsForUser(ctx context.Context, userID int) ([]*Keyword, error) {
-	rows, err := PgPool.Query(ctx,
-		`SELECT id, user_id, keyword, created_at FROM keywords WHERE user_id = $1 ORDER BY created_at DESC`,
-		userID)
+// getKeyword runs a query selecting a single keyword, returning nil when there is none
+func getKeyword(ctx context.Context, query string, args ...any) (*Keyword, error) {
+	k, err := scanKeyword(PgPool.QueryRow(ctx, query, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return k, err
+}
+
+// queryKeywords runs a query selecting keywordColumns
+func queryKeywords(ctx context.Context, query string, args ...any) ([]*Keyword, error) {
+	rows, err := PgPool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -41,11 +56,11 @@ func GetKeywordsForUser(ctx context.Context, userID int) ([]*Keyword, error) {
 
 	var keywords []*Keyword
 	for rows.Next() {
-		var k Keyword
-		if err := rows.Scan(&k.ID, &k.UserID, &k.Keyword, &k.CreatedAt); err != nil {
+		k, err := scanKeyword(rows)
+		if err != nil {
 			return nil, err
 		}
-		keywords = append(keywords, &k)
+		keywords = append(keywords, k)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -55,91 +70,185 @@ func GetKeywordsForUser(ctx context.Context, userID int) ([]*Keyword, error) {
 	return keywords, nil
 }
 
-// GetKeywordByID retrieves a keyword by its ID
-func GetKeywordByID(ctx context.Context, id int) (*Keyword, error) {
-	var k Keyword
-	err := PgPool.QueryRow(ctx,
-		`SELECT id, user_id, keyword, created_at FROM keywords WHERE id = $1`,
-		id).Scan(&k.ID, &k.UserID, &k.Keyword, &k.CreatedAt)
+// CreateKeyword adds a new keyword for a user together with its first version
+func CreateKeyword(ctx context.Context, userID int, keyword string) (*Keyword, error) {
+	tx, err := PgPool.Begin(ctx)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, nil // No keyword found with this ID
-		}
 		return nil, err
 	}
+	defer tx.Rollback(ctx)
 
-	return &k, nil
+	k, err := scanKeyword(tx.QueryRow(ctx,
+		`INSERT INTO keywords (user_id, keyword) VALUES ($1, $2) RETURNING `+keywordColumns,
+		userID, keyword))
+	if err != nil {
+		return nil, err
+	}
+	if err := addKeywordVersion(ctx, tx, k.ID, keyword); err != nil {
+		return nil, err
+	}
+
+	return k, tx.Commit(ctx)
+}
+
+// GetKeywordsForUser retrieves all keywords for a specific user
+func GetKeywordsForUser(ctx context.Context, userID int) ([]*Keyword, error) {
+	return queryKeywords(ctx,
+		`SELECT `+keywordColumns+` FROM keywords WHERE user_id = $1 AND deleted_at IS NULL ORDER BY created_at DESC`,
+		userID)
+}
+
+// GetKeywordByID retrieves a keyword by its ID
+func GetKeywordByID(ctx context.Context, id int) (*Keyword, error) {
+	return getKeyword(ctx,
+		`SELECT `+keywordColumns+` FROM keywords WHERE id = $1 AND deleted_at IS NULL`,
+		id)
 }
 
 // GetKeywordByName retrieves a keyword by its name (keyword text)
 func GetKeywordByName(ctx context.Context, keyword string) (*Keyword, error) {
-	var k Keyword
-	err := PgPool.QueryRow(ctx,
-		`SELECT id, user_id, keyword, created_at FROM keywords WHERE keyword = $1`,
-		keyword).Scan(&k.ID, &k.UserID, &k.Keyword, &k.CreatedAt)
+	return getKeyword(ctx,
+		`SELECT `+keywordColumns+` FROM keywords WHERE keyword = $1 AND deleted_at IS NULL`,
+		keyword)
+}
+
+// UpdateKeyword changes the text of a keyword. A changed text is recorded as
+// a new keyword version effective now; it reports whether the text changed.
+func UpdateKeyword(ctx context.Context, id int, newKeyword string) (bool, error) {
+	tx, err := PgPool.Begin(ctx)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, nil // No keyword found with this name
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	var current string
+	err = tx.QueryRow(ctx, `SELECT keyword FROM keywords WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id).
+		Scan(&current)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, ErrKeywordNotFound
+	}
+	if err != nil {
+		return false, err
+	}
+	if current == newKeyword {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE keywords SET keyword = $1 WHERE id = $2`, newKeyword, id); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+			return false, ErrKeywordConflict
 		}
-		return nil, err
+		return false, err
+	}
+	if err := addKeywordVersion(ctx, tx, id, newKeyword); err != nil {
+		return false, err
 	}
 
-	return &k, nil
+	return true, tx.Commit(ctx)
+}
+
+// SoftDeleteKeyword marks a keyword as deleted and returns the deletion time.
+// Its data is kept until PurgeKeyword removes it.
+func SoftDeleteKeyword(ctx context.Context, id int) (time.Time, error) {
+	var deletedAt time.Time
+	err := PgPool.QueryRow(ctx,
+		`UPDATE keywords SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL RETURNING deleted_at`,
+		id).Scan(&deletedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return time.Time{}, ErrKeywordNotFound
+	}
+	return deletedAt, err
 }
 
-// UpdateKeyword updates a keyword
-func UpdateKeyword(ctx context.Context, id int, newKeyword string) error {
+// RestoreKeyword undoes the deletion of a keyword deleted at or after since
+func RestoreKeyword(ctx context.Context, id int, since time.Time) error {
 	result, err := PgPool.Exec(ctx,
-		`UPDATE keywords SET keyword = $1 WHERE id = $2`,
-		newKeyword, id)
+		`UPDATE keywords SET deleted_at = NULL WHERE id = $1 AND deleted_at >= $2`,
+		id, since)
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+			return ErrKeywordConflict
+		}
 		return err
 	}
 
 	if result.RowsAffected() == 0 {
-		return errors.New("keyword not found")
+		return ErrKeywordNotFound
 	}
 
 	return nil
 }
 
-// DeleteKeyword deletes a keyword
-func DeleteKeyword(ctx context.Context, id int) error {
-	result, err := PgPool.Exec(ctx,
-		`DELETE FROM keywords WHERE id = $1`,
+// GetDeletedKeywordByID retrieves a soft-deleted keyword by its ID
+func GetDeletedKeywordByID(ctx context.Context, id int) (*Keyword, error) {
+	return getKeyword(ctx,
+		`SELECT `+keywordColumns+` FROM keywords WHERE id = $1 AND deleted_at IS NOT NULL`,
 		id)
-	if err != nil {
-		return err
-	}
+}
 
-	if result.RowsAffected() == 0 {
-		return errors.New("keyword not found")
-	}
+// GetDeletedKeywordsForUser retrieves a user's keywords deleted at or after since
+func GetDeletedKeywordsForUser(ctx context.Context, userID int, since time.Time) ([]*Keyword, error) {
+	return queryKeywords(ctx,
+		`SELECT `+keywordColumns+` FROM keywords WHERE user_id = $1 AND deleted_at >= $2 ORDER BY deleted_at DESC`,
+		userID, since)
+}
 
-	return nil
+// GetKeywordsDeletedBefore retrieves the keywords deleted before cutoff
+func GetKeywordsDeletedBefore(ctx context.Context, cutoff time.Time) ([]*Keyword, error) {
+	return queryKeywords(ctx,
+		`SELECT `+keywordColumns+` FROM keywords WHERE deleted_at < $1 ORDER BY deleted_at`,
+		cutoff)
 }
 
-// GetAllKeywords retrieves all keywords from the database
-func GetAllKeywords(ctx context.Context) ([]*Keyword, error) {
-	rows, err := PgPool.Query(ctx,
-		`SELECT id, user_id, keyword, created_at FROM keywords ORDER BY created_at DESC`)
+// PurgeKeyword permanently deletes a soft-deleted keyword and all of its
+// PostgreSQL data in one transaction
+func PurgeKeyword(ctx context.Context, id int) error {
+	tx, err := PgPool.Begin(ctx)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer rows.Close()
+	defer tx.Rollback(ctx)
 
-	var keywords []*Keyword
-	for rows.Next() {
-		var k Keyword
-		if err := rows.Scan(&k.ID, &k.UserID, &k.Keyword, &k.CreatedAt); err != nil {
-			return nil, err
+	var deleted bool
+	if err := tx.QueryRow(ctx, `SELECT deleted_at IS NOT NULL FROM keywords WHERE id = $1 FOR UPDATE`, id).
+		Scan(&deleted); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil // Already purged
 		}
-		keywords = append(keywords, &k)
+		return err
+	}
+	if !deleted {
+		return errors.New("keyword is not deleted")
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, err
+	// Dependent rows cascade too; deleting them explicitly keeps the purge
+	// independent of the foreign key definitions
+	queries := []string{
+		`DELETE FROM trend_record_items WHERE keyword_id = $1`,
+		`DELETE FROM trend_items WHERE keyword_id = $1`,
+		`DELETE FROM trend_record_sources WHERE keyword_id = $1`,
+		`DELETE FROM metric_series WHERE keyword_id = $1`,
+		`DELETE FROM backfill_coverage WHERE keyword_id = $1`,
+		`DELETE FROM keyword_versions WHERE keyword_id = $1`,
+		`DELETE FROM forecast_points WHERE forecast_id IN (SELECT id FROM forecasts WHERE keyword_id = $1)`,
+		`DELETE FROM forecasts WHERE keyword_id = $1`,
+		`DELETE FROM anomalies WHERE keyword_id = $1`,
+		`DELETE FROM trend_records WHERE keyword_id = $1`,
+		`DELETE FROM keywords WHERE id = $1`,
+	}
+	for _, query := range queries {
+		if _, err := tx.Exec(ctx, query, id); err != nil {
+			return err
+		}
 	}
 
-	return keywords, nil
-} 
\ No newline at end of file
+	return tx.Commit(ctx)
+}
+
+// GetAllKeywords retrieves all keywords that are not deleted
+func GetAllKeywords(ctx context.Context) ([]*Keyword, error) {
+	return queryKeywords(ctx,
+		`SELECT `+keywordColumns+` FROM keywords WHERE deleted_at IS NULL ORDER BY created_at DESC`)
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// KeywordVersion is a definition of a keyword: the text it was collected
+// with from EffectiveFrom until the next version took effect
+type KeywordVersion struct {
+	ID            int       `json:"id"`
+	KeywordID     int       `json:"keyword_id"`
+	Version       int       `json:"version"`
+	Keyword       string    `json:"keyword"`
+	EffectiveFrom time.Time `json:"effective_from"`
+}
+
+// addKeywordVersion records a new definition of a keyword, effective now
+func addKeywordVersion(ctx context.Context, tx pgx.Tx, keywordID int, keyword string) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO keyword_versions (keyword_id, version, keyword)
+		SELECT $1, COALESCE(MAX(version), 0) + 1, $2 FROM keyword_versions WHERE keyword_id = $1
+	`, keywordID, keyword)
+	return err
+}
+
+// GetKeywordVersions retrieves the definitions of a keyword, oldest first
+func GetKeywordVersions(ctx context.Context, keywordID int) ([]KeywordVersion, error) {
+	rows, err := PgPool.Query(ctx, `
+		SELECT id, keyword_id, version, keyword, effective_from
+		FROM keyword_versions
+		WHERE keyword_id = $1
+		ORDER BY version
+	`, keywordID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []KeywordVersion
+	for rows.Next() {
+		var v KeywordVersion
+		if err := rows.Scan(&v.ID, &v.KeywordID, &v.Version, &v.Keyword, &v.EffectiveFrom); err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+
+	return versions, rows.Err()
+}
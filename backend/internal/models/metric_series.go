@@ -0,0 +1,286 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/trendscout/backend/internal/metrics"
+	"github.com/trendscout/backend/internal/timezone"
+)
+
+// MetricPoint is one bucket of a named metric series. Dimensions is empty for
+// the keyword total and holds e.g. {"source": "wwd.com"} for breakdowns.
+type MetricPoint struct {
+	KeywordID   int               `json:"keyword_id" db:"keyword_id"`
+	Metric      string            `json:"metric" db:"metric"`
+	Granularity string            `json:"granularity" db:"granularity"`
+	BucketStart time.Time         `json:"bucket_start" db:"bucket_start"`
+	Value       float64           `json:"value" db:"value"`
+	Dimensions  map[string]string `json:"dimensions,omitempty" db:"dimensions"`
+	UpdatedAt   time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// MetricQuery selects a metric series. Without a dimension the keyword total
+// is returned; with one, the matching breakdown rows are aggregated per bucket.
+type MetricQuery struct {
+	KeywordID       int
+	Metric          string
+	Granularity     string // defaults to metrics.GranularityDay
+	Start           time.Time
+	End             time.Time
+	Dimension       string   // e.g. "source"
+	DimensionValues []string // values of Dimension to include
+}
+
+// UpsertMetricPoints stores metric points, replacing existing values for the
+// same keyword, metric, granularity, bucket and dimensions
+func UpsertMetricPoints(ctx context.Context, points []MetricPoint) error {
+	if len(points) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO metric_series (keyword_id, metric, granularity, bucket_start, value, dimensions, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (keyword_id, metric, granularity, bucket_start, dimensions) DO UPDATE SET
+			value = EXCLUDED.value,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	now := time.Now()
+	batch := &pgx.Batch{}
+	for _, p := range points {
+		granularity := p.Granularity
+		if granularity == "" {
+			granularity = metrics.GranularityDay
+		}
+		dimensions := p.Dimensions
+		if dimensions == nil {
+			dimensions = map[string]string{}
+		}
+		batch.Queue(query, p.KeywordID, p.Metric, granularity, p.BucketStart, p.Value, dimensions, now)
+	}
+
+	return PgPool.SendBatch(ctx, batch).Close()
+}
+
+// GetMetricSeries retrieves a metric series ordered by bucket. A daily series
+// includes days that only have hourly buckets, rolled up on demand into days of
+// the collection time zone; stored daily values take precedence over the rollup.
+func GetMetricSeries(ctx context.Context, q MetricQuery) ([]MetricPoint, error) {
+	granularity := q.Granularity
+	if granularity == "" {
+		granularity = metrics.GranularityDay
+	}
+
+	aggregate := "SUM(value)"
+	if metrics.Aggregation(q.Metric) == metrics.AggregateAvg {
+		aggregate = "AVG(value)"
+	}
+
+	args := []any{q.KeywordID, q.Metric, granularity, q.Start, q.End}
+
+	// buckets selects (bucket, dimensions, value) rows at the requested granularity
+	buckets := `
+		SELECT bucket_start AS bucket, dimensions, value
+		FROM metric_series
+		WHERE keyword_id = $1 AND metric = $2 AND granularity = $3
+			AND bucket_start >= $4 AND bucket_start <= $5`
+	if granularity == metrics.GranularityDay {
+		args = append(args, timezone.Collection().String())
+		buckets += `
+		UNION ALL
+		SELECT date_trunc('day', h.bucket_start, $6), h.dimensions, ` + aggregate + `
+		FROM metric_series h
+		WHERE h.keyword_id = $1 AND h.metric = $2 AND h.granularity = 'hour'
+			AND h.bucket_start >= $4 AND h.bucket_start < $5::timestamptz + interval '1 day'
+			AND NOT EXISTS (
+				SELECT 1 FROM metric_series d
+				WHERE d.keyword_id = h.keyword_id AND d.metric = h.metric AND d.granularity = 'day'
+					AND d.dimensions = h.dimensions AND d.bucket_start = date_trunc('day', h.bucket_start, $6)
+			)
+		GROUP BY 1, 2`
+	}
+
+	var rows pgx.Rows
+	var err error
+	if q.Dimension == "" {
+		rows, err = PgPool.Query(ctx, `
+			SELECT bucket, value
+			FROM (`+buckets+`) b
+			WHERE dimensions = '{}'::jsonb
+			ORDER BY bucket ASC
+		`, args...)
+	} else {
+		n := len(args)
+		args = append(args, q.Dimension, q.DimensionValues)
+		rows, err = PgPool.Query(ctx, fmt.Sprintf(`
+			SELECT bucket, %s
+			FROM (%s) b
+			WHERE dimensions->>$%d = ANY($%d)
+			GROUP BY bucket
+			ORDER BY bucket ASC
+		`, aggregate, buckets, n+1, n+2), args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []MetricPoint
+	for rows.Next() {
+		p := MetricPoint{KeywordID: q.KeywordID, Metric: q.Metric, Granularity: granularity}
+		if err := rows.Scan(&p.BucketStart, &p.Value); err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+
+	return points, rows.Err()
+}
+
+// GetHourlyTrendRecords builds one trend record per hour from the hourly
+// metric series between start and end (bucket starts, inclusive). The
+// selected metric is merged into Values unless it is a trend record column.
+// A non-empty sources list restricts the data to those sources.
+func GetHourlyTrendRecords(ctx context.Context, keywordID int, metric string, start, end time.Time, sources []string) ([]TrendRecord, error) {
+	names := []string{metrics.Volume, metrics.Engagement, metrics.Sentiment}
+	if !isTrendRecordColumn(metric) {
+		names = append(names, metric)
+	}
+
+	byBucket := make(map[time.Time]*TrendRecord)
+	for _, name := range names {
+		q := MetricQuery{KeywordID: keywordID, Metric: name, Granularity: metrics.GranularityHour, Start: start, End: end}
+		if len(sources) > 0 {
+			q.Dimension = "source"
+			q.DimensionValues = sources
+		}
+		points, err := GetMetricSeries(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range points {
+			bucket := p.BucketStart.UTC()
+			r, ok := byBucket[bucket]
+			if !ok {
+				r = &TrendRecord{KeywordID: keywordID, Date: bucket}
+				byBucket[bucket] = r
+			}
+			switch name {
+			case metrics.Volume:
+				r.Volume = int(p.Value)
+			case metrics.Engagement:
+				r.Engagement = p.Value
+			case metrics.Sentiment:
+				r.Sentiment = p.Value
+			default:
+				r.Values = map[string]float64{name: p.Value}
+			}
+		}
+	}
+
+	// Hours without a point of the selected metric are missing, not zero
+	records := make([]TrendRecord, 0, len(byBucket))
+	for _, r := range byBucket {
+		if _, ok := r.Values[metric]; ok || isTrendRecordColumn(metric) {
+			records = append(records, *r)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Date.Before(records[j].Date)
+	})
+	return records, nil
+}
+
+// GetMetricTrendRecords retrieves daily trend records whose MetricValue
+// returns the named metric. Volume, engagement and sentiment come straight
+// from trend_records; other metrics are read from the metric series store and
+// merged in by date, and days without a stored point of them are left out.
+// A non-empty sources list restricts the data to those sources.
+func GetMetricTrendRecords(ctx context.Context, keywordID int, metric string, startDate, endDate time.Time, sources []string) ([]TrendRecord, error) {
+	var records []TrendRecord
+	var err error
+	if len(sources) > 0 {
+		records, err = GetTrendRecordsForSources(ctx, keywordID, startDate, endDate, sources)
+	} else {
+		records, err = GetTrendRecords(ctx, keywordID, startDate, endDate)
+	}
+	if err != nil || isTrendRecordColumn(metric) {
+		return records, err
+	}
+
+	q := MetricQuery{KeywordID: keywordID, Metric: metric, Start: startDate, End: endDate}
+	if len(sources) > 0 {
+		q.Dimension = "source"
+		q.DimensionValues = sources
+	}
+	series, err := GetMetricSeries(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeMetricSeries(records, keywordID, metric, series), nil
+}
+
+// mergeMetricSeries merges the daily points of a metric into trend records by
+// collection day. Days with a point but no trend record get a record of their
+// own; records without a point are dropped, as the metric's value on that day
+// is unknown rather than zero.
+func mergeMetricSeries(records []TrendRecord, keywordID int, metric string, series []MetricPoint) []TrendRecord {
+	// Records and buckets both start at midnight of a collection day
+	byDay := make(map[int64]TrendRecord, len(records))
+	for _, r := range records {
+		byDay[timezone.CollectionDay(r.Date).Unix()] = r
+	}
+
+	merged := make([]TrendRecord, 0, len(series))
+	for _, p := range series {
+		day := timezone.CollectionDay(p.BucketStart)
+		r, ok := byDay[day.Unix()]
+		if !ok {
+			r = TrendRecord{KeywordID: keywordID, Date: day}
+		}
+		values := make(map[string]float64, len(r.Values)+1)
+		for name, v := range r.Values {
+			values[name] = v
+		}
+		values[metric] = p.Value
+		r.Values = values
+		merged = append(merged, r)
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Date.Before(merged[j].Date)
+	})
+	return merged
+}
+
+// MetricExists reports whether any series of the named metric is stored
+func MetricExists(ctx context.Context, metric string) (bool, error) {
+	var exists bool
+	err := PgPool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM metric_series WHERE metric = $1)`, metric).Scan(&exists)
+	return exists, err
+}
+
+// isTrendRecordColumn reports whether the metric is stored as a trend_records column
+func isTrendRecordColumn(metric string) bool {
+	switch metric {
+	case metrics.Volume, metrics.Engagement, metrics.Sentiment:
+		return true
+	}
+	return false
+}
+
+// trendRecordMetricPoints returns the metric series points mirrored from a trend record
+func trendRecordMetricPoints(r *TrendRecord) []MetricPoint {
+	return []MetricPoint{
+		{KeywordID: r.KeywordID, Metric: metrics.Volume, BucketStart: r.Date, Value: float64(r.Volume)},
+		{KeywordID: r.KeywordID, Metric: metrics.Engagement, BucketStart: r.Date, Value: r.Engagement},
+		{KeywordID: r.KeywordID, Metric: metrics.Sentiment, BucketStart: r.Date, Value: r.Sentiment},
+	}
+}
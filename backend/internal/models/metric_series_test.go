@@ -0,0 +1,67 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMergeMetricSeries(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	records := []TrendRecord{
+		{KeywordID: 7, Date: day(1), Volume: 10, Values: map[string]float64{"post_count": 2}},
+		{KeywordID: 7, Date: day(2), Volume: 20},
+		{KeywordID: 7, Date: day(4), Volume: 40},
+	}
+	series := []MetricPoint{
+		{Metric: "article_count", BucketStart: day(4), Value: 4},
+		// Points are merged by collection day, whatever their time of day
+		{Metric: "article_count", BucketStart: day(1).Add(9 * time.Hour), Value: 1},
+		{Metric: "article_count", BucketStart: day(3), Value: 0},
+	}
+
+	merged := mergeMetricSeries(records, 7, "article_count", series)
+
+	want := []struct {
+		date   time.Time
+		volume int
+		value  float64
+	}{
+		{day(1), 10, 1},
+		// Day 2 has no point: its value is unknown, so it is left out
+		{day(3), 0, 0},
+		{day(4), 40, 4},
+	}
+	if len(merged) != len(want) {
+		t.Fatalf("got %d records, want %d", len(merged), len(want))
+	}
+	for i, w := range want {
+		r := merged[i]
+		if !r.Date.Equal(w.date) {
+			t.Errorf("record %d: date = %s, want %s", i, r.Date, w.date)
+		}
+		if r.KeywordID != 7 {
+			t.Errorf("record %d: keyword = %d, want 7", i, r.KeywordID)
+		}
+		if r.Volume != w.volume {
+			t.Errorf("record %d: volume = %d, want %d", i, r.Volume, w.volume)
+		}
+		if v, ok := r.Values["article_count"]; !ok || v != w.value {
+			t.Errorf("record %d: article_count = %v (%v), want %v", i, v, ok, w.value)
+		}
+	}
+
+	// Values already loaded are kept, without changing the input record
+	if merged[0].Values["post_count"] != 2 {
+		t.Errorf("post_count = %v, want 2", merged[0].Values["post_count"])
+	}
+	if _, ok := records[0].Values["article_count"]; ok {
+		t.Error("input record was modified")
+	}
+
+	if got := mergeMetricSeries(records, 7, "article_count", nil); len(got) != 0 {
+		t.Errorf("without points got %d records, want 0", len(got))
+	}
+}
@@ -72,4 +72,24 @@ func KeyExists(ctx context.Context, key string) (bool, error) {
 		return false, err
 	}
 	return result > 0, nil
-} 
\ No newline at end of file
+}
+
+// KeywordCachePrefix is the key prefix of everything cached for a keyword.
+// Caches scoped to a keyword must use it so that purging the keyword removes them.
+func KeywordCachePrefix(keywordID int) string {
+	return fmt.Sprintf("keyword:%d:", keywordID)
+}
+
+// DelPrefix deletes all keys starting with prefix and returns how many were deleted
+func DelPrefix(ctx context.Context, prefix string) (int64, error) {
+	var deleted int64
+	iter := RedisClient.Scan(ctx, 0, prefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		n, err := RedisClient.Del(ctx, iter.Val()).Result()
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+	}
+	return deleted, iter.Err()
+}
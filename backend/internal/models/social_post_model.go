@@ -0,0 +1,145 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SocialMediaPost represents a social media post document in MongoDB
+type SocialMediaPost struct {
+	ID           string    `bson:"_id,omitempty" json:"id"`
+	KeywordID    int       `bson:"keyword_id" json:"keyword_id"`
+	Platform     string    `bson:"platform" json:"platform"`
+	PostID       string    `bson:"post_id" json:"post_id"`
+	Username     string    `bson:"username" json:"username"`
+	Caption      string    `bson:"caption" json:"caption"`
+	URL          string    `bson:"url" json:"url"`
+	ImageURL     string    `bson:"image_url" json:"image_url"`
+	Tags         []string  `bson:"tags" json:"tags"`
+	LikeCount    int       `bson:"like_count" json:"like_count"`
+	CommentCount int       `bson:"comment_count" json:"comment_count"`
+	RepostCount  int       `bson:"repost_count" json:"repost_count"`
+	Language     string    `bson:"language" json:"language"`
+	PostDate     time.Time `bson:"post_date" json:"post_date"`
+	CreatedAt    time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// socialPostsCollection returns the social posts collection
+func socialPostsCollection() *mongo.Collection {
+	return MongoDB.Collection("social_posts")
+}
+
+// SocialMediaPostID builds the document ID for a post collected for a keyword.
+// The same post can match several keywords, so the keyword is part of the ID.
+func SocialMediaPostID(keywordID int, platform, postID string) string {
+	return fmt.Sprintf("%s:%d:%s", platform, keywordID, postID)
+}
+
+// UpsertSocialMediaPost stores a post, refreshing its engagement counts when it
+// has already been collected
+func UpsertSocialMediaPost(ctx context.Context, post *SocialMediaPost) error {
+	if post.ID == "" {
+		post.ID = SocialMediaPostID(post.KeywordID, post.Platform, post.PostID)
+	}
+
+	now := time.Now()
+	if post.CreatedAt.IsZero() {
+		post.CreatedAt = now
+	}
+	post.UpdatedAt = now
+
+	update := bson.M{
+		"$set": bson.M{
+			"keyword_id":    post.KeywordID,
+			"platform":      post.Platform,
+			"post_id":       post.PostID,
+			"username":      post.Username,
+			"caption":       post.Caption,
+			"url":           post.URL,
+			"image_url":     post.ImageURL,
+			"tags":          post.Tags,
+			"like_count":    post.LikeCount,
+			"comment_count": post.CommentCount,
+			"repost_count":  post.RepostCount,
+			"language":      post.Language,
+			"provenance":    "api",
+			"post_date":     post.PostDate,
+			"updated_at":    post.UpdatedAt,
+		},
+		"$setOnInsert": bson.M{
+			"created_at": post.CreatedAt,
+		},
+	}
+
+	_, err := socialPostsCollection().UpdateOne(ctx,
+		bson.M{"_id": post.ID}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// GetSocialMediaPostsForKeyword retrieves the most recent posts for a keyword
+func GetSocialMediaPostsForKeyword(ctx context.Context, keywordID int, limit int) ([]*SocialMediaPost, error) {
+	if limit <= 0 {
+		limit = 20 // Default limit
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{primitive.E{Key: "post_date", Value: -1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := socialPostsCollection().Find(ctx,
+		bson.M{"keyword_id": keywordID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var posts []*SocialMediaPost
+	if err := cursor.All(ctx, &posts); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+// DeleteSocialMediaPostsForKeyword removes all social media posts of a keyword
+func DeleteSocialMediaPostsForKeyword(ctx context.Context, keywordID int) (int64, error) {
+	result, err := socialPostsCollection().DeleteMany(ctx, bson.M{"keyword_id": keywordID})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// GetSocialMediaPostsByKeywordAndDateRange retrieves posts for a keyword within a date range
+func GetSocialMediaPostsByKeywordAndDateRange(ctx context.Context, keywordID int, startDate, endDate time.Time) ([]SocialMediaPost, error) {
+	filter := bson.M{
+		"keyword_id": keywordID,
+		"post_date": bson.M{
+			"$gte": startDate,
+			"$lte": endDate,
+		},
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{primitive.E{Key: "post_date", Value: -1}})
+
+	cursor, err := socialPostsCollection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var posts []SocialMediaPost
+	if err := cursor.All(ctx, &posts); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
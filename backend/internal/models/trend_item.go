@@ -0,0 +1,300 @@
+package models
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// TrendItem is a single collected item as seen by the aggregation pipeline.
+// Trend records are rebuilt from these rows, so the item text and raw counts
+// are kept alongside the scores derived from them.
+type TrendItem struct {
+	ID              int64     `json:"id" db:"id"`
+	KeywordID       int       `json:"keyword_id" db:"keyword_id"`
+	ItemKey         string    `json:"item_key" db:"item_key"` // see TrendItemKey
+	Kind            string    `json:"kind" db:"kind"`
+	Source          string    `json:"source" db:"source"`
+	URL             string    `json:"url" db:"url"`
+	ExternalID      string    `json:"external_id,omitempty" db:"external_id"`
+	Title           string    `json:"title" db:"title"`
+	Content         string    `json:"content" db:"content"`
+	ImageURL        string    `json:"image_url,omitempty" db:"image_url"`
+	Author          string    `json:"author,omitempty" db:"author"`
+	Language        string    `json:"language,omitempty" db:"language"`
+	PublishedAt     time.Time `json:"published_at" db:"published_at"`
+	BucketStart     time.Time `json:"bucket_start" db:"bucket_start"` // day the item was counted in
+	LikeCount       int       `json:"like_count" db:"like_count"`
+	CommentCount    int       `json:"comment_count" db:"comment_count"`
+	RepostCount     int       `json:"repost_count" db:"repost_count"`
+	Relevance       float64   `json:"relevance" db:"relevance"` // 0 means excluded from aggregation
+	Sentiment       float64   `json:"sentiment" db:"sentiment"`
+	Engagement      float64   `json:"engagement" db:"engagement"`
+	Provenance      string    `json:"provenance" db:"provenance"`
+	PipelineVersion int       `json:"pipeline_version" db:"pipeline_version"`
+	CollectedAt     time.Time `json:"collected_at" db:"collected_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// trendItemColumns lists the columns read by scanTrendItem, in order
+const trendItemColumns = `id, keyword_id, item_key, kind, source, url, external_id, title, content, image_url, author,
+	language, published_at, bucket_start, like_count, comment_count, repost_count, relevance, sentiment, engagement,
+	provenance, pipeline_version, collected_at, updated_at`
+
+// scanTrendItem scans a row selected with trendItemColumns
+func scanTrendItem(row pgx.Row) (TrendItem, error) {
+	var i TrendItem
+	err := row.Scan(&i.ID, &i.KeywordID, &i.ItemKey, &i.Kind, &i.Source, &i.URL, &i.ExternalID, &i.Title, &i.Content,
+		&i.ImageURL, &i.Author, &i.Language, &i.PublishedAt, &i.BucketStart, &i.LikeCount, &i.CommentCount,
+		&i.RepostCount, &i.Relevance, &i.Sentiment, &i.Engagement, &i.Provenance, &i.PipelineVersion,
+		&i.CollectedAt, &i.UpdatedAt)
+	return i, err
+}
+
+// TrendItemKey identifies an item within a keyword across collection runs
+func TrendItemKey(source, url, externalID string) string {
+	sum := sha1.Sum([]byte(source + "|" + url + "|" + externalID))
+	return hex.EncodeToString(sum[:])
+}
+
+// UpsertTrendItems stores items, replacing the counts, scores and bucket of
+// items already stored for the same keyword. The first collection time is kept.
+func UpsertTrendItems(ctx context.Context, items []TrendItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO trend_items (keyword_id, item_key, kind, source, url, external_id, title, content, image_url, author,
+			language, published_at, bucket_start, like_count, comment_count, repost_count, relevance, sentiment, engagement,
+			provenance, pipeline_version, collected_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $22)
+		ON CONFLICT (keyword_id, item_key) DO UPDATE SET
+			title = EXCLUDED.title,
+			content = EXCLUDED.content,
+			image_url = EXCLUDED.image_url,
+			published_at = EXCLUDED.published_at,
+			bucket_start = EXCLUDED.bucket_start,
+			like_count = EXCLUDED.like_count,
+			comment_count = EXCLUDED.comment_count,
+			repost_count = EXCLUDED.repost_count,
+			relevance = EXCLUDED.relevance,
+			sentiment = EXCLUDED.sentiment,
+			engagement = EXCLUDED.engagement,
+			pipeline_version = EXCLUDED.pipeline_version,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	now := time.Now()
+	batch := &pgx.Batch{}
+	for _, i := range items {
+		batch.Queue(query, i.KeywordID, i.ItemKey, i.Kind, i.Source, i.URL, i.ExternalID, i.Title, i.Content,
+			i.ImageURL, i.Author, i.Language, i.PublishedAt, i.BucketStart, i.LikeCount, i.CommentCount,
+			i.RepostCount, i.Relevance, i.Sentiment, i.Engagement, i.Provenance, i.PipelineVersion, now)
+	}
+
+	return PgPool.SendBatch(ctx, batch).Close()
+}
+
+// UpdateTrendItemScores stores rescored items: bucket, relevance, sentiment,
+// engagement and pipeline version
+func UpdateTrendItemScores(ctx context.Context, items []TrendItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	query := `
+		UPDATE trend_items
+		SET bucket_start = $2, relevance = $3, sentiment = $4, engagement = $5, pipeline_version = $6, updated_at = $7
+		WHERE id = $1
+	`
+
+	now := time.Now()
+	batch := &pgx.Batch{}
+	for _, i := range items {
+		batch.Queue(query, i.ID, i.BucketStart, i.Relevance, i.Sentiment, i.Engagement, i.PipelineVersion, now)
+	}
+
+	return PgPool.SendBatch(ctx, batch).Close()
+}
+
+// GetTrendItems retrieves the items of a keyword published within a time range
+func GetTrendItems(ctx context.Context, keywordID int, start, end time.Time) ([]TrendItem, error) {
+	return queryTrendItems(ctx, `
+		SELECT `+trendItemColumns+`
+		FROM trend_items
+		WHERE keyword_id = $1 AND published_at >= $2 AND published_at <= $3
+		ORDER BY published_at ASC, id ASC
+	`, keywordID, start, end)
+}
+
+// GetTrendItemsForBucket retrieves the items counted in a keyword's bucket
+func GetTrendItemsForBucket(ctx context.Context, keywordID int, bucketStart time.Time) ([]TrendItem, error) {
+	return queryTrendItems(ctx, `
+		SELECT `+trendItemColumns+`
+		FROM trend_items
+		WHERE keyword_id = $1 AND bucket_start = $2
+		ORDER BY published_at ASC, id ASC
+	`, keywordID, bucketStart)
+}
+
+// queryTrendItems runs a query selecting trendItemColumns
+func queryTrendItems(ctx context.Context, query string, args ...any) ([]TrendItem, error) {
+	rows, err := PgPool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []TrendItem
+	for rows.Next() {
+		item, err := scanTrendItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// Orderings of the items behind a trend point
+const (
+	TrendItemOrderRelevance  = "relevance"
+	TrendItemOrderEngagement = "engagement"
+	TrendItemOrderPositive   = "positive" // most positive sentiment first
+	TrendItemOrderNegative   = "negative" // most negative sentiment first
+)
+
+// trendItemOrders maps each ordering to its ORDER BY clause
+var trendItemOrders = map[string]string{
+	TrendItemOrderRelevance:  "relevance DESC, engagement DESC, published_at DESC, id",
+	TrendItemOrderEngagement: "engagement DESC, relevance DESC, published_at DESC, id",
+	TrendItemOrderPositive:   "sentiment DESC, engagement DESC, published_at DESC, id",
+	TrendItemOrderNegative:   "sentiment ASC, engagement DESC, published_at DESC, id",
+}
+
+// IsTrendItemOrder reports whether order is a supported item ordering
+func IsTrendItemOrder(order string) bool {
+	_, ok := trendItemOrders[order]
+	return ok
+}
+
+// TrendPointQuery selects the items counted in a keyword's trend buckets
+// between Start (inclusive) and End (exclusive)
+type TrendPointQuery struct {
+	KeywordID int
+	Start     time.Time
+	End       time.Time
+	Hourly    bool     // match publication times instead of day buckets
+	Kinds     []string // empty means all kinds
+	OrderBy   string   // TrendItemOrderRelevance or TrendItemOrderEngagement
+	Limit     int
+	Offset    int
+}
+
+// TrendPointKindSummary aggregates the items of one kind behind a trend point
+type TrendPointKindSummary struct {
+	Kind       string
+	Volume     int
+	Engagement float64
+	Sentiment  float64
+}
+
+// ReplaceTrendRecordItems records the items counted in a keyword's trend
+// record for the bucket starting at recordDate, replacing the previous lineage
+func ReplaceTrendRecordItems(ctx context.Context, keywordID int, recordDate time.Time, itemIDs []int64) error {
+	tx, err := PgPool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM trend_record_items WHERE keyword_id = $1 AND record_date = $2`,
+		keywordID, recordDate); err != nil {
+		return err
+	}
+	if len(itemIDs) > 0 {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO trend_record_items (keyword_id, record_date, item_id)
+			SELECT $1, $2, unnest($3::bigint[])
+			ON CONFLICT DO NOTHING
+		`, keywordID, recordDate, itemIDs); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetTrendPointItems retrieves the items counted in the selected trend
+// buckets, ranked by the query's ordering
+func GetTrendPointItems(ctx context.Context, q TrendPointQuery) ([]TrendItem, error) {
+	order, ok := trendItemOrders[q.OrderBy]
+	if !ok {
+		order = trendItemOrders[TrendItemOrderRelevance]
+	}
+
+	where, args := trendPointFilter(q)
+	args = append(args, q.Limit, q.Offset)
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM trend_items
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, trendItemColumns, where, order, len(args)-1, len(args))
+
+	return queryTrendItems(ctx, query, args...)
+}
+
+// GetTrendPointSummary aggregates the items counted in the selected trend
+// buckets per kind, ignoring the query's ordering and paging
+func GetTrendPointSummary(ctx context.Context, q TrendPointQuery) ([]TrendPointKindSummary, error) {
+	where, args := trendPointFilter(q)
+	rows, err := PgPool.Query(ctx, `
+		SELECT kind, COUNT(*), COALESCE(SUM(engagement), 0), COALESCE(AVG(sentiment), 0.5)
+		FROM trend_items
+		WHERE `+where+`
+		GROUP BY kind
+		ORDER BY kind
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summaries []TrendPointKindSummary
+	for rows.Next() {
+		var s TrendPointKindSummary
+		if err := rows.Scan(&s.Kind, &s.Volume, &s.Engagement, &s.Sentiment); err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, s)
+	}
+
+	return summaries, rows.Err()
+}
+
+// trendPointFilter builds the WHERE clause and arguments of a trend point query
+func trendPointFilter(q TrendPointQuery) (string, []any) {
+	where := `keyword_id = $1 AND id IN (
+		SELECT item_id FROM trend_record_items
+		WHERE keyword_id = $1 AND record_date >= $2 AND record_date < $3)`
+	if q.Hourly {
+		where = `keyword_id = $1 AND published_at >= $2 AND published_at < $3
+			AND id IN (SELECT item_id FROM trend_record_items WHERE keyword_id = $1)`
+	}
+
+	args := []any{q.KeywordID, q.Start, q.End}
+	if len(q.Kinds) > 0 {
+		args = append(args, q.Kinds)
+		where += fmt.Sprintf(" AND kind = ANY($%d)", len(args))
+	}
+
+	return where, args
+}
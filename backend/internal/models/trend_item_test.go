@@ -0,0 +1,73 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrendPointFilter(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1)
+
+	tests := []struct {
+		name     string
+		q        TrendPointQuery
+		contains []string
+		excludes []string
+		args     []any
+	}{
+		{
+			name: "day bucket",
+			q:    TrendPointQuery{KeywordID: 4, Start: start, End: end},
+			contains: []string{
+				"keyword_id = $1",
+				"record_date >= $2 AND record_date < $3",
+			},
+			excludes: []string{"published_at", "kind"},
+			args:     []any{4, start, end},
+		},
+		{
+			name: "hourly",
+			q:    TrendPointQuery{KeywordID: 4, Start: start, End: start.Add(time.Hour), Hourly: true},
+			contains: []string{
+				"published_at >= $2 AND published_at < $3",
+				// Only items counted in some trend record are returned
+				"id IN (SELECT item_id FROM trend_record_items WHERE keyword_id = $1)",
+			},
+			excludes: []string{"record_date", "kind"},
+			args:     []any{4, start, start.Add(time.Hour)},
+		},
+		{
+			name:     "day bucket of one kind",
+			q:        TrendPointQuery{KeywordID: 4, Start: start, End: end, Kinds: []string{"post"}},
+			contains: []string{"record_date >= $2", "AND kind = ANY($4)"},
+			args:     []any{4, start, end, []string{"post"}},
+		},
+		{
+			name:     "hourly of several kinds",
+			q:        TrendPointQuery{KeywordID: 4, Start: start, End: end, Hourly: true, Kinds: []string{"article", "post"}},
+			contains: []string{"published_at >= $2", "AND kind = ANY($4)"},
+			args:     []any{4, start, end, []string{"article", "post"}},
+		},
+	}
+
+	for _, tt := range tests {
+		where, args := trendPointFilter(tt.q)
+		where = strings.Join(strings.Fields(where), " ")
+		for _, s := range tt.contains {
+			if !strings.Contains(where, s) {
+				t.Errorf("%s: WHERE %q does not contain %q", tt.name, where, s)
+			}
+		}
+		for _, s := range tt.excludes {
+			if strings.Contains(where, s) {
+				t.Errorf("%s: WHERE %q contains %q", tt.name, where, s)
+			}
+		}
+		if !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("%s: args = %v, want %v", tt.name, args, tt.args)
+		}
+	}
+}
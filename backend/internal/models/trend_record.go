@@ -2,49 +2,93 @@ package models
 
 import (
 	"context"
+	"fmt"
 	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/trendscout/backend/internal/metrics"
+	"github.com/trendscout/backend/internal/timezone"
 )
 
 // TrendRecord represents a trend data point
 type TrendRecord struct {
-	ID        int       `json:"id" db:"id"`
-	KeywordID int       `json:"keyword_id" db:"keyword_id"`
-	Date      time.Time `json:"date" db:"date"`
-	Volume    int       `json:"volume" db:"volume"`
-	Sentiment float64   `json:"sentiment" db:"sentiment"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	ID         int       `json:"id" db:"id"`
+	KeywordID  int       `json:"keyword_id" db:"keyword_id"`
+	Date       time.Time `json:"date" db:"date"`
+	Volume     int       `json:"volume" db:"volume"`
+	Engagement float64   `json:"engagement" db:"engagement"`
+	Sentiment  float64   `json:"sentiment" db:"sentiment"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+
+	// PipelineVersion is the aggregation pipeline that produced the record;
+	// 0 for records computed before item-level storage
+	PipelineVersion int `json:"pipeline_version" db:"pipeline_version"`
+
+	// Values holds other metrics loaded from the metric series store
+	Values map[string]float64 `json:"values,omitempty" db:"-"`
+}
+
+// trendRecordColumns lists the columns read by scanTrendRecord, in order
+const trendRecordColumns = `id, keyword_id, record_date, volume, engagement, sentiment, created_at, updated_at, pipeline_version`
+
+// scanTrendRecord scans a row selected with trendRecordColumns
+func scanTrendRecord(row pgx.Row) (TrendRecord, error) {
+	var record TrendRecord
+	err := row.Scan(&record.ID, &record.KeywordID, &record.Date, &record.Volume, &record.Engagement,
+		&record.Sentiment, &record.CreatedAt, &record.UpdatedAt, &record.PipelineVersion)
+	return record, err
 }
 
-// CreateTrendRecord creates a new trend record
-func CreateTrendRecord(ctx context.Context, keywordID int, date time.Time, volume int, sentiment float64) (*TrendRecord, error) {
+// MetricValue returns the value of the named metric (see package metrics).
+// A metric that is not a trend record column and was not loaded into Values
+// returns 0; GetMetricTrendRecords leaves such days out.
+func (r TrendRecord) MetricValue(metric string) float64 {
+	switch metric {
+	case metrics.Volume, "":
+		return float64(r.Volume)
+	case metrics.Engagement:
+		return r.Engagement
+	case metrics.Sentiment:
+		return r.Sentiment
+	default:
+		return r.Values[metric]
+	}
+}
+
+// CreateTrendRecord creates or replaces the trend record of a keyword's day,
+// noting the aggregation pipeline version that computed it
+func CreateTrendRecord(ctx context.Context, keywordID int, date time.Time, volume int, engagement, sentiment float64, pipelineVersion int) (*TrendRecord, error) {
 	query := `
-		INSERT INTO trend_records (keyword_id, record_date, volume, sentiment, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO trend_records (keyword_id, record_date, volume, engagement, sentiment, created_at, updated_at, pipeline_version)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		ON CONFLICT (keyword_id, record_date) DO UPDATE SET
 			volume = EXCLUDED.volume,
+			engagement = EXCLUDED.engagement,
 			sentiment = EXCLUDED.sentiment,
-			updated_at = EXCLUDED.updated_at
-		RETURNING id, keyword_id, record_date, volume, sentiment, created_at, updated_at
-	`
+			updated_at = EXCLUDED.updated_at,
+			pipeline_version = EXCLUDED.pipeline_version
+		RETURNING ` + trendRecordColumns
 
 	now := time.Now()
-	var record TrendRecord
-
-	err := PgPool.QueryRow(ctx, query, keywordID, date, volume, sentiment, now, now).
-		Scan(&record.ID, &record.KeywordID, &record.Date, &record.Volume, &record.Sentiment, &record.CreatedAt, &record.UpdatedAt)
 
+	record, err := scanTrendRecord(PgPool.QueryRow(ctx, query, keywordID, date, volume, engagement, sentiment, now, now, pipelineVersion))
 	if err != nil {
 		return nil, err
 	}
 
+	// Mirror the built-in metrics into the metric series store
+	if err := UpsertMetricPoints(ctx, trendRecordMetricPoints(&record)); err != nil {
+		return nil, fmt.Errorf("failed to store metric series: %w", err)
+	}
+
 	return &record, nil
 }
 
 // GetTrendRecords retrieves trend records for a keyword within a date range
 func GetTrendRecords(ctx context.Context, keywordID int, startDate, endDate time.Time) ([]TrendRecord, error) {
 	query := `
-		SELECT id, keyword_id, record_date, volume, sentiment, created_at, updated_at
+		SELECT ` + trendRecordColumns + `
 		FROM trend_records
 		WHERE keyword_id = $1 AND record_date >= $2 AND record_date <= $3
 		ORDER BY record_date ASC
@@ -58,8 +102,7 @@ func GetTrendRecords(ctx context.Context, keywordID int, startDate, endDate time
 
 	var records []TrendRecord
 	for rows.Next() {
-		var record TrendRecord
-		err := rows.Scan(&record.ID, &record.KeywordID, &record.Date, &record.Volume, &record.Sentiment, &record.CreatedAt, &record.UpdatedAt)
+		record, err := scanTrendRecord(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -77,17 +120,14 @@ func GetTrendRecordsForKeyword(ctx context.Context, keywordID int, startDate, en
 // GetLatestTrendRecord gets the most recent trend record for a keyword
 func GetLatestTrendRecord(ctx context.Context, keywordID int) (*TrendRecord, error) {
 	query := `
-		SELECT id, keyword_id, record_date, volume, sentiment, created_at, updated_at
+		SELECT ` + trendRecordColumns + `
 		FROM trend_records
 		WHERE keyword_id = $1
 		ORDER BY record_date DESC
 		LIMIT 1
 	`
 
-	var record TrendRecord
-	err := PgPool.QueryRow(ctx, query, keywordID).
-		Scan(&record.ID, &record.KeywordID, &record.Date, &record.Volume, &record.Sentiment, &record.CreatedAt, &record.UpdatedAt)
-
+	record, err := scanTrendRecord(PgPool.QueryRow(ctx, query, keywordID))
 	if err != nil {
 		return nil, err
 	}
@@ -98,7 +138,7 @@ func GetLatestTrendRecord(ctx context.Context, keywordID int) (*TrendRecord, err
 // GetTrendRecordsByDateRange gets all trend records within a date range
 func GetTrendRecordsByDateRange(ctx context.Context, startDate, endDate time.Time) ([]TrendRecord, error) {
 	query := `
-		SELECT id, keyword_id, record_date, volume, sentiment, created_at, updated_at
+		SELECT ` + trendRecordColumns + `
 		FROM trend_records
 		WHERE record_date >= $1 AND record_date <= $2
 		ORDER BY record_date ASC, keyword_id ASC
@@ -112,8 +152,7 @@ func GetTrendRecordsByDateRange(ctx context.Context, startDate, endDate time.Tim
 
 	var records []TrendRecord
 	for rows.Next() {
-		var record TrendRecord
-		err := rows.Scan(&record.ID, &record.KeywordID, &record.Date, &record.Volume, &record.Sentiment, &record.CreatedAt, &record.UpdatedAt)
+		record, err := scanTrendRecord(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -130,6 +169,30 @@ func DeleteTrendRecordsForKeyword(ctx context.Context, keywordID int) error {
 	return err
 }
 
+// DeleteTrendBucket deletes a keyword's trend record, its lineage, source
+// breakdown and daily metric points for the day starting at bucketStart
+func DeleteTrendBucket(ctx context.Context, keywordID int, bucketStart time.Time) error {
+	tx, err := PgPool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	queries := []string{
+		`DELETE FROM trend_records WHERE keyword_id = $1 AND record_date = $2`,
+		`DELETE FROM trend_record_items WHERE keyword_id = $1 AND record_date = $2`,
+		`DELETE FROM trend_record_sources WHERE keyword_id = $1 AND record_date = $2`,
+		`DELETE FROM metric_series WHERE keyword_id = $1 AND granularity = 'day' AND bucket_start = $2`,
+	}
+	for _, query := range queries {
+		if _, err := tx.Exec(ctx, query, keywordID, bucketStart); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
 // GetTrendStatistics calculates statistics for a keyword's trend data
 func GetTrendStatistics(ctx context.Context, keywordID int, days int) (map[string]interface{}, error) {
 	endDate := time.Now()
@@ -167,13 +230,13 @@ func GetTrendStatistics(ctx context.Context, keywordID int, days int) (map[strin
 	}
 
 	result := map[string]interface{}{
-		"data_points":    stats.DataPoints,
-		"avg_volume":     stats.AvgVolume,
-		"max_volume":     stats.MaxVolume,
-		"min_volume":     stats.MinVolume,
-		"avg_sentiment":  stats.AvgSentiment,
-		"max_sentiment":  stats.MaxSentiment,
-		"min_sentiment":  stats.MinSentiment,
+		"data_points":   stats.DataPoints,
+		"avg_volume":    stats.AvgVolume,
+		"max_volume":    stats.MaxVolume,
+		"min_volume":    stats.MinVolume,
+		"avg_sentiment": stats.AvgSentiment,
+		"max_sentiment": stats.MaxSentiment,
+		"min_sentiment": stats.MinSentiment,
 	}
 
 	return result, nil
@@ -183,22 +246,37 @@ func GetTrendStatistics(ctx context.Context, keywordID int, days int) (map[strin
 func SaveTrendData(ctx context.Context, keywordID int, posts []SocialMediaPost, articles []BlogArticle) error {
 	// Calculate volume based on number of posts and articles
 	volume := len(posts) + len(articles)
-	
+
+	engagement := trendDataEngagement(posts, articles)
+
 	// Calculate basic sentiment (placeholder implementation)
 	sentiment := 0.5 // neutral sentiment as default
-	
-	// Use current date
-	date := time.Now().Truncate(24 * time.Hour) // Remove time component
-	
-	// Create trend record
-	_, err := CreateTrendRecord(ctx, keywordID, date, volume, sentiment)
+
+	// Use the current day in the collection time zone
+	date := timezone.CollectionDay(time.Now())
+
+	// Create trend record; this path is not rebuilt from items, so it has no pipeline version
+	_, err := CreateTrendRecord(ctx, keywordID, date, volume, engagement, sentiment, 0)
 	return err
 }
 
+// trendDataEngagement weights posts by their likes and comments; articles
+// count with the weight of their source, as in the collection pipeline
+func trendDataEngagement(posts []SocialMediaPost, articles []BlogArticle) float64 {
+	var engagement float64
+	for _, post := range posts {
+		engagement += metrics.EngagementScore(post.Platform, post.LikeCount, post.CommentCount, post.RepostCount)
+	}
+	for _, article := range articles {
+		engagement += metrics.EngagementScore(article.Source, 0, 0, 0)
+	}
+	return engagement
+}
+
 // GetLatestTrendRecords gets the most recent trend records for a keyword
 func GetLatestTrendRecords(ctx context.Context, keywordID int, limit int) ([]TrendRecord, error) {
 	query := `
-		SELECT id, keyword_id, record_date, volume, sentiment, created_at, updated_at
+		SELECT ` + trendRecordColumns + `
 		FROM trend_records
 		WHERE keyword_id = $1
 		ORDER BY record_date ASC
@@ -213,8 +291,7 @@ func GetLatestTrendRecords(ctx context.Context, keywordID int, limit int) ([]Tre
 
 	var records []TrendRecord
 	for rows.Next() {
-		var record TrendRecord
-		err := rows.Scan(&record.ID, &record.KeywordID, &record.Date, &record.Volume, &record.Sentiment, &record.CreatedAt, &record.UpdatedAt)
+		record, err := scanTrendRecord(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -222,4 +299,4 @@ func GetLatestTrendRecords(ctx context.Context, keywordID int, limit int) ([]Tre
 	}
 
 	return records, rows.Err()
-} 
\ No newline at end of file
+}
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/trendscout/backend/internal/metrics"
+)
+
+// TrendRecordSource is the per-source breakdown of a trend record. A row with
+// FetchOK false means the source could not be fetched on that day, so a low
+// volume reflects a broken feed rather than a drop in interest.
+type TrendRecordSource struct {
+	KeywordID  int       `json:"keyword_id" db:"keyword_id"`
+	Date       time.Time `json:"date" db:"record_date"`
+	Source     string    `json:"source" db:"source"`
+	Volume     int       `json:"volume" db:"volume"`
+	Engagement float64   `json:"engagement" db:"engagement"`
+	Sentiment  float64   `json:"sentiment" db:"sentiment"`
+	FetchOK    bool      `json:"fetch_ok" db:"fetch_ok"`
+	FetchError string    `json:"fetch_error,omitempty" db:"fetch_error"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// MetricValue returns the value of the named metric (see package metrics)
+func (r TrendRecordSource) MetricValue(metric string) float64 {
+	switch metric {
+	case metrics.Volume, "":
+		return float64(r.Volume)
+	case metrics.Engagement:
+		return r.Engagement
+	case metrics.Sentiment:
+		return r.Sentiment
+	default:
+		return 0 // other metrics have no per-source breakdown here
+	}
+}
+
+// UpsertTrendRecordSource stores the volume collected from a source on a day
+func UpsertTrendRecordSource(ctx context.Context, r *TrendRecordSource) error {
+	query := `
+		INSERT INTO trend_record_sources (keyword_id, record_date, source, volume, engagement, sentiment, fetch_ok, fetch_error, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, TRUE, '', $7)
+		ON CONFLICT (keyword_id, record_date, source) DO UPDATE SET
+			volume = EXCLUDED.volume,
+			engagement = EXCLUDED.engagement,
+			sentiment = EXCLUDED.sentiment,
+			fetch_ok = TRUE,
+			fetch_error = '',
+			updated_at = EXCLUDED.updated_at
+	`
+
+	r.FetchOK = true
+	r.FetchError = ""
+	r.UpdatedAt = time.Now()
+
+	if _, err := PgPool.Exec(ctx, query, r.KeywordID, r.Date, r.Source, r.Volume, r.Engagement, r.Sentiment, r.UpdatedAt); err != nil {
+		return err
+	}
+
+	// Mirror the breakdown into the metric series store
+	dims := map[string]string{"source": r.Source}
+	return UpsertMetricPoints(ctx, []MetricPoint{
+		{KeywordID: r.KeywordID, Metric: metrics.Volume, BucketStart: r.Date, Value: float64(r.Volume), Dimensions: dims},
+		{KeywordID: r.KeywordID, Metric: metrics.Engagement, BucketStart: r.Date, Value: r.Engagement, Dimensions: dims},
+		{KeywordID: r.KeywordID, Metric: metrics.Sentiment, BucketStart: r.Date, Value: r.Sentiment, Dimensions: dims},
+	})
+}
+
+// RecordSourceFetchFailure marks a source as failed on a day. Volume already
+// collected earlier that day is kept.
+func RecordSourceFetchFailure(ctx context.Context, keywordID int, date time.Time, source, fetchError string) error {
+	query := `
+		INSERT INTO trend_record_sources (keyword_id, record_date, source, volume, engagement, sentiment, fetch_ok, fetch_error, updated_at)
+		VALUES ($1, $2, $3, 0, 0, 0.5, FALSE, $4, $5)
+		ON CONFLICT (keyword_id, record_date, source) DO UPDATE SET
+			fetch_ok = FALSE,
+			fetch_error = EXCLUDED.fetch_error,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := PgPool.Exec(ctx, query, keywordID, date, source, fetchError, time.Now())
+	return err
+}
+
+// GetTrendRecordSources retrieves the per-source breakdown for a keyword
+// within a date range. An empty sources list returns every source.
+func GetTrendRecordSources(ctx context.Context, keywordID int, startDate, endDate time.Time, sources []string) ([]TrendRecordSource, error) {
+	query := `
+		SELECT keyword_id, record_date, source, volume, engagement, sentiment, fetch_ok, fetch_error, updated_at
+		FROM trend_record_sources
+		WHERE keyword_id = $1 AND record_date >= $2 AND record_date <= $3
+			AND (cardinality($4::text[]) = 0 OR source = ANY($4))
+		ORDER BY record_date ASC, source ASC
+	`
+
+	if sources == nil {
+		sources = []string{}
+	}
+
+	rows, err := PgPool.Query(ctx, query, keywordID, startDate, endDate, sources)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []TrendRecordSource
+	for rows.Next() {
+		var r TrendRecordSource
+		if err := rows.Scan(&r.KeywordID, &r.Date, &r.Source, &r.Volume, &r.Engagement, &r.Sentiment,
+			&r.FetchOK, &r.FetchError, &r.UpdatedAt); err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+
+	return records, rows.Err()
+}
+
+// GetTrendRecordsForSources builds daily trend records from the selected
+// sources only. Sentiment is the volume-weighted average across sources.
+func GetTrendRecordsForSources(ctx context.Context, keywordID int, startDate, endDate time.Time, sources []string) ([]TrendRecord, error) {
+	query := `
+		SELECT keyword_id, record_date,
+			SUM(volume)::int,
+			SUM(engagement),
+			COALESCE(SUM(sentiment * volume) / NULLIF(SUM(volume), 0), AVG(sentiment)),
+			MIN(updated_at),
+			MAX(updated_at)
+		FROM trend_record_sources
+		WHERE keyword_id = $1 AND record_date >= $2 AND record_date <= $3 AND source = ANY($4)
+		GROUP BY keyword_id, record_date
+		ORDER BY record_date ASC
+	`
+
+	rows, err := PgPool.Query(ctx, query, keywordID, startDate, endDate, sources)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []TrendRecord
+	for rows.Next() {
+		var r TrendRecord
+		if err := rows.Scan(&r.KeywordID, &r.Date, &r.Volume, &r.Engagement, &r.Sentiment,
+			&r.CreatedAt, &r.UpdatedAt); err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+
+	return records, rows.Err()
+}
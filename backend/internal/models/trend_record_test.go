@@ -0,0 +1,44 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTrendDataEngagement(t *testing.T) {
+	posts := []SocialMediaPost{{Platform: "reddit", LikeCount: 10, CommentCount: 2, RepostCount: 1}}
+	articles := []BlogArticle{{Source: "vogue.com"}, {Source: "popsugar.com"}}
+
+	// 0.8 × (1 + ln 18) for the post, and the source weights of the articles
+	want := 0.8*(1+math.Log(18)) + 1.5 + 0.9
+	if got := trendDataEngagement(posts, articles); math.Abs(got-want) > 1e-9 {
+		t.Errorf("engagement = %v, want %v", got, want)
+	}
+}
+
+func TestTrendRecordMetricValue(t *testing.T) {
+	record := TrendRecord{Volume: 12, Engagement: 3.5, Sentiment: 0.7, Values: map[string]float64{"article_count": 4}}
+
+	tests := []struct {
+		metric string
+		want   float64
+	}{
+		{"", 12},
+		{"volume", 12},
+		{"engagement", 3.5},
+		{"sentiment", 0.7},
+		{"article_count", 4},
+		// A metric that was not loaded falls back to 0
+		{"post_count", 0},
+	}
+
+	for _, tt := range tests {
+		if got := record.MetricValue(tt.metric); got != tt.want {
+			t.Errorf("MetricValue(%q) = %v, want %v", tt.metric, got, tt.want)
+		}
+	}
+
+	if got := (TrendRecord{}).MetricValue("article_count"); got != 0 {
+		t.Errorf("MetricValue without Values = %v, want 0", got)
+	}
+}
@@ -13,7 +13,8 @@ import (
 type User struct {
 	ID           int       `json:"id"`
 	Email        string    `json:"email"`
-	PasswordHash string    `json:"-"` // Do not include in JSON responses
+	PasswordHash string    `json:"-"`        // Do not include in JSON responses
+	Timezone     string    `json:"timezone"` // IANA time zone dates are shown in
 	CreatedAt    time.Time `json:"created_at"`
 }
 
@@ -28,8 +29,8 @@ func CreateUser(ctx context.Context, email, password string) (*User, error) {
 	// Create the user in the database
 	var user User
 	err = PgPool.QueryRow(ctx,
-		`INSERT INTO users (email, password_hash) VALUES ($1, $2) RETURNING id, email, password_hash, created_at`,
-		email, hashedPassword).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.CreatedAt)
+		`INSERT INTO users (email, password_hash) VALUES ($1, $2) RETURNING id, email, password_hash, timezone, created_at`,
+		email, hashedPassword).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.Timezone, &user.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -41,8 +42,8 @@ func CreateUser(ctx context.Context, email, password string) (*User, error) {
 func GetUserByEmail(ctx context.Context, email string) (*User, error) {
 	var user User
 	err := PgPool.QueryRow(ctx,
-		`SELECT id, email, password_hash, created_at FROM users WHERE email = $1`,
-		email).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.CreatedAt)
+		`SELECT id, email, password_hash, timezone, created_at FROM users WHERE email = $1`,
+		email).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.Timezone, &user.CreatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil // No user found with this email
@@ -57,8 +58,8 @@ func GetUserByEmail(ctx context.Context, email string) (*User, error) {
 func GetUserByID(ctx context.Context, id int) (*User, error) {
 	var user User
 	err := PgPool.QueryRow(ctx,
-		`SELECT id, email, password_hash, created_at FROM users WHERE id = $1`,
-		id).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.CreatedAt)
+		`SELECT id, email, password_hash, timezone, created_at FROM users WHERE id = $1`,
+		id).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.Timezone, &user.CreatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil // No user found with this ID
@@ -94,4 +95,17 @@ func (u *User) UpdatePassword(ctx context.Context, newPassword string) error {
 	// Update the local object
 	u.PasswordHash = string(hashedPassword)
 	return nil
-} 
\ No newline at end of file
+}
+
+// UpdateTimezone updates the user's time zone preference
+func (u *User) UpdateTimezone(ctx context.Context, timezone string) error {
+	_, err := PgPool.Exec(ctx,
+		`UPDATE users SET timezone = $1 WHERE id = $2`,
+		timezone, u.ID)
+	if err != nil {
+		return err
+	}
+
+	u.Timezone = timezone
+	return nil
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"context"
 	"strings"
+	"unicode"
 )
 
 // CreateTestUser はテスト用のユーザーを作成または検索する補助関数です
@@ -29,4 +30,15 @@ func SanitizeString(s string) string {
 	// 基本的なサニタイズ処理（必要に応じて拡張）
 	// 先頭と末尾の空白を削除
 	return strings.TrimSpace(s)
-} 
\ No newline at end of file
+}
+
+// DetectLanguage は本文の文字種から言語コードを推定します
+// 仮名・漢字を含む場合は "ja"、それ以外は "en" を返します
+func DetectLanguage(text string) string {
+	for _, r := range text {
+		if unicode.In(r, unicode.Hiragana, unicode.Katakana, unicode.Han) {
+			return "ja"
+		}
+	}
+	return "en"
+}
@@ -1,11 +1,396 @@
 // backend/internal/prediction/arima.go
 package prediction
 
-// PredictARIMA は時系列データに対するARIMAモデルによる予測を行います
-// 注: 完全なARIMA実装は複雑なため、簡易版として線形予測を返します
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// WeeklyPeriod は日次系列の週次季節性の周期です
+const WeeklyPeriod = 7
+
+// 自動次数選択の探索範囲
+const (
+	maxAutoP     = 3
+	maxAutoQ     = 3
+	maxAutoOrder = 5 // p+q+P+Q の上限
+)
+
+// minARIMAPoints は ARIMA を当てはめるのに必要な最小のデータ数です
+const minARIMAPoints = 10
+
+// ErrInsufficientData はモデルを当てはめるにはデータが少なすぎることを示します
+var ErrInsufficientData = errors.New("insufficient data for model")
+
+// ARIMAOrder は SARIMA(p,d,q)(P,D,Q)[Period] の次数です。
+// Period が 1 以下のとき季節成分は使いません
+type ARIMAOrder struct {
+	P, D, Q    int
+	SP, SD, SQ int
+	Period     int
+}
+
+// String はモデルの表記を返します
+func (o ARIMAOrder) String() string {
+	if !o.seasonal() {
+		return fmt.Sprintf("ARIMA(%d,%d,%d)", o.P, o.D, o.Q)
+	}
+	return fmt.Sprintf("SARIMA(%d,%d,%d)(%d,%d,%d)[%d]", o.P, o.D, o.Q, o.SP, o.SD, o.SQ, o.Period)
+}
+
+// seasonal は季節成分を持つかを返します
+func (o ARIMAOrder) seasonal() bool {
+	return o.Period > 1 && o.SP+o.SD+o.SQ > 0
+}
+
+// differencing は差分で失われる先頭のデータ数を返します
+func (o ARIMAOrder) differencing() int {
+	if !o.seasonal() {
+		return o.D
+	}
+	return o.D + o.SD*o.Period
+}
+
+// ARIMAModel は当てはめ済みの SARIMA モデルです。係数は条件付き残差平方和
+// (CSS) を最小化して推定し、尤度は残差の正規性を仮定して求めます
+type ARIMAModel struct {
+	Order  ARIMAOrder
+	AR     []float64 // 非季節 AR 係数 φ1..φp
+	MA     []float64 // 非季節 MA 係数 θ1..θq
+	SAR    []float64 // 季節 AR 係数 Φ1..ΦP
+	SMA    []float64 // 季節 MA 係数 Θ1..ΘQ
+	Mean   float64   // 差分系列の平均 (差分ありのモデルではドリフト)
+	Sigma2 float64   // 残差分散
+	LogLik float64
+	AIC    float64
+
+	data      []float64
+	residuals []float64 // 元系列の時点に揃えた残差
+}
+
+// FitARIMA は指定した次数の SARIMA モデルを当てはめます。係数は定常性と
+// 反転可能性を満たす範囲で推定します
+func FitARIMA(data []float64, order ARIMAOrder) (*ARIMAModel, error) {
+	if order.P < 0 || order.D < 0 || order.Q < 0 || order.SP < 0 || order.SD < 0 || order.SQ < 0 {
+		return nil, fmt.Errorf("invalid order %s", order)
+	}
+	if order.Period <= 1 {
+		order.SP, order.SD, order.SQ, order.Period = 0, 0, 0, 0
+	}
+
+	w := data
+	for i := 0; i < order.D; i++ {
+		w = difference(w, 1)
+	}
+	for i := 0; i < order.SD; i++ {
+		w = difference(w, order.Period)
+	}
+
+	// 差分が 1 回までなら差分系列の平均 (差分ありではドリフト) を推定する
+	includeMean := order.D+order.SD <= 1
+	nAR := order.P + order.SP*order.Period
+	nParams := order.P + order.Q + order.SP + order.SQ
+	if includeMean {
+		nParams++
+	}
+	if len(w)-nAR < nParams+3 {
+		return nil, ErrInsufficientData
+	}
+
+	// 尺度をそろえた系列で推定し、初期単体の幅を系列の大きさから切り離す
+	center, scale := meanStd(w)
+	if !includeMean {
+		center = 0
+	}
+	if scale == 0 {
+		scale = 1
+	}
+	z := make([]float64, len(w))
+	for i, v := range w {
+		z[i] = (v - center) / scale
+	}
+
+	objective := func(x []float64) float64 {
+		m := order.unpack(x, includeMean)
+		ar, ma := m.expanded()
+		_, sse := cssResiduals(z, m.Mean, ar, ma)
+		return sse
+	}
+	x, _ := nelderMead(objective, make([]float64, nParams), 0.1, 200*nParams+200)
+
+	model := order.unpack(x, includeMean)
+	model.Order = order
+	model.data = data
+	if includeMean {
+		model.Mean = center + scale*model.Mean
+	}
+
+	ar, ma := model.expanded()
+	e, sse := cssResiduals(w, model.Mean, ar, ma)
+
+	nEff := float64(len(w) - nAR)
+	model.Sigma2 = math.Max(sse/nEff, 1e-12)
+	model.LogLik = -0.5 * nEff * (math.Log(2*math.Pi*model.Sigma2) + 1)
+	model.AIC = -2*model.LogLik + 2*float64(nParams+1)
+
+	model.residuals = make([]float64, len(data))
+	copy(model.residuals[order.differencing():], e)
+
+	return model, nil
+}
+
+// unpack は最適化の変数をモデルの係数に変換します。各係数ブロックは偏自己相関
+// として表し、定常性 (AR) と反転可能性 (MA) を保証します
+func (o ARIMAOrder) unpack(x []float64, includeMean bool) *ARIMAModel {
+	m := &ARIMAModel{}
+	i := 0
+	take := func(n int) []float64 {
+		block := x[i : i+n]
+		i += n
+		return block
+	}
+	m.AR = pacfToCoefficients(take(o.P))
+	m.MA = negate(pacfToCoefficients(take(o.Q)))
+	m.SAR = pacfToCoefficients(take(o.SP))
+	m.SMA = negate(pacfToCoefficients(take(o.SQ)))
+	if includeMean {
+		m.Mean = x[i]
+	}
+	return m
+}
+
+// pacfToCoefficients は (-∞,∞) の変数を tanh で偏自己相関に写し、
+// Durbin-Levinson の漸化式で定常な AR 係数に変換します
+func pacfToCoefficients(u []float64) []float64 {
+	phi := make([]float64, len(u))
+	prev := make([]float64, len(u))
+	for k := range u {
+		r := math.Tanh(u[k])
+		copy(prev, phi)
+		phi[k] = r
+		for j := 0; j < k; j++ {
+			phi[j] = prev[j] - r*prev[k-1-j]
+		}
+	}
+	return phi
+}
+
+// negate は符号を反転した係数を返します
+func negate(x []float64) []float64 {
+	for i := range x {
+		x[i] = -x[i]
+	}
+	return x
+}
+
+// expanded は季節成分を掛け合わせた AR 係数と MA 係数を返します。
+// (1-φ(B))(1-Φ(B^s)) と (1+θ(B))(1+Θ(B^s)) を展開したものです
+func (m *ARIMAModel) expanded() ([]float64, []float64) {
+	s := m.Order.Period
+	arPoly := polyMultiply(lagPolynomial(m.AR, 1, -1), lagPolynomial(m.SAR, s, -1))
+	maPoly := polyMultiply(lagPolynomial(m.MA, 1, 1), lagPolynomial(m.SMA, s, 1))
+	return negate(arPoly[1:]), maPoly[1:]
+}
+
+// lagPolynomial は 1 + sign·(c1·B^lag + c2·B^2lag + ...) の係数を返します
+func lagPolynomial(coefficients []float64, lag int, sign float64) []float64 {
+	poly := make([]float64, len(coefficients)*lag+1)
+	poly[0] = 1
+	for i, c := range coefficients {
+		poly[(i+1)*lag] = sign * c
+	}
+	return poly
+}
+
+// polyMultiply は多項式の積を返します
+func polyMultiply(a, b []float64) []float64 {
+	out := make([]float64, len(a)+len(b)-1)
+	for i, x := range a {
+		for j, y := range b {
+			out[i+j] += x * y
+		}
+	}
+	return out
+}
+
+// cssResiduals は ARMA の条件付き残差と、AR の次数以降の残差平方和を返します。
+// 観測前の残差はゼロとします
+func cssResiduals(w []float64, mean float64, ar, ma []float64) ([]float64, float64) {
+	e := make([]float64, len(w))
+	sse := 0.0
+	for t := len(ar); t < len(w); t++ {
+		pred := mean
+		for i, a := range ar {
+			pred += a * (w[t-1-i] - mean)
+		}
+		for j, b := range ma {
+			if t-1-j >= 0 {
+				pred += b * e[t-1-j]
+			}
+		}
+		e[t] = w[t] - pred
+		sse += e[t] * e[t]
+	}
+	return e, sse
+}
+
+// Forecast は horizon 期先までの予測値と予測標準誤差を返します。
+// 標準誤差は差分を含むモデルの ψ 重みから求めます
+func (m *ARIMAModel) Forecast(horizon int) ([]float64, []float64) {
+	ar, ma := m.expanded()
+
+	// 差分を含めた AR 多項式 φ(B)Φ(B^s)(1-B)^d(1-B^s)^D
+	full := append([]float64{1}, negate(append([]float64(nil), ar...))...)
+	for i := 0; i < m.Order.D; i++ {
+		full = polyMultiply(full, []float64{1, -1})
+	}
+	for i := 0; i < m.Order.SD; i++ {
+		full = polyMultiply(full, lagPolynomial([]float64{1}, m.Order.Period, -1))
+	}
+	phi := negate(full[1:])
+
+	// 定数項は差分系列の平均から生じる
+	constant := m.Mean
+	for _, a := range ar {
+		constant -= a * m.Mean
+	}
+
+	n := len(m.data)
+	x := append(append([]float64(nil), m.data...), make([]float64, horizon)...)
+	forecasts := make([]float64, horizon)
+	for h := 0; h < horizon; h++ {
+		t := n + h
+		value := constant
+		for i, a := range phi {
+			if t-1-i >= 0 {
+				value += a * x[t-1-i]
+			}
+		}
+		for j, b := range ma {
+			if k := t - 1 - j; k >= 0 && k < n {
+				value += b * m.residuals[k]
+			}
+		}
+		x[t] = value
+		forecasts[h] = value
+	}
+
+	// ψ 重み: ψ0 = 1, ψj = θj + Σ φi ψ(j-i)
+	psi := make([]float64, horizon)
+	stdErrs := make([]float64, horizon)
+	variance := 0.0
+	for j := 0; j < horizon; j++ {
+		if j == 0 {
+			psi[j] = 1
+		} else {
+			if j-1 < len(ma) {
+				psi[j] = ma[j-1]
+			}
+			for i := 1; i <= j && i <= len(phi); i++ {
+				psi[j] += phi[i-1] * psi[j-i]
+			}
+		}
+		variance += psi[j] * psi[j]
+		stdErrs[j] = math.Sqrt(m.Sigma2 * variance)
+	}
+
+	return forecasts, stdErrs
+}
+
+// Intervals は horizon 期先までの予測区間を、正規誤差を仮定して予測標準誤差
+// から求めます
+func (m *ARIMAModel) Intervals(horizon int, levels []float64) [][]Interval {
+	forecasts, stdErrs := m.Forecast(horizon)
+	return NormalIntervals(forecasts, stdErrs, levels)
+}
+
+// String はモデルの表記を返します
+func (m *ARIMAModel) String() string {
+	return m.Order.String()
+}
+
+// Parameters は当てはめた係数を返します。平均は差分系列の平均を推定した
+// モデルのみ含みます
+func (m *ARIMAModel) Parameters() map[string]float64 {
+	params := map[string]float64{"sigma2": m.Sigma2}
+	for name, coefficients := range map[string][]float64{"ar": m.AR, "ma": m.MA, "sar": m.SAR, "sma": m.SMA} {
+		for i, c := range coefficients {
+			params[fmt.Sprintf("%s%d", name, i+1)] = c
+		}
+	}
+	if m.Order.D+m.Order.SD <= 1 {
+		params["mean"] = m.Mean
+	}
+	return params
+}
+
+// AutoARIMA は差分の次数を検定で決め、AIC が最小となる SARIMA モデルを選びます。
+// 季節差分は季節性が強いとき、差分は KPSS 検定で定常になるまで (最大 2 回) 取ります。
+// period が 1 以下なら季節成分は使いません
+func AutoARIMA(data []float64, period int) (*ARIMAModel, error) {
+	if len(data) < minARIMAPoints {
+		return nil, ErrInsufficientData
+	}
+
+	order := ARIMAOrder{Period: period}
+	x := data
+	maxSeasonal := 0
+	if period > 1 && len(data) >= 3*period {
+		maxSeasonal = 1
+		if seasonalStrength(data, period) > seasonalStrengthThreshold {
+			order.SD = 1
+			x = difference(x, period)
+		}
+	}
+	for order.D < 2 && !isStationary(x) {
+		order.D++
+		x = difference(x, 1)
+	}
+
+	var best *ARIMAModel
+	for p := 0; p <= maxAutoP; p++ {
+		for q := 0; q <= maxAutoQ; q++ {
+			for sp := 0; sp <= maxSeasonal; sp++ {
+				for sq := 0; sq <= maxSeasonal; sq++ {
+					if p+q+sp+sq > maxAutoOrder {
+						continue
+					}
+					candidate := order
+					candidate.P, candidate.Q, candidate.SP, candidate.SQ = p, q, sp, sq
+					model, err := FitARIMA(data, candidate)
+					if err != nil {
+						continue
+					}
+					if best == nil || model.AIC < best.AIC {
+						best = model
+					}
+				}
+			}
+		}
+	}
+
+	if best == nil {
+		return nil, ErrInsufficientData
+	}
+	return best, nil
+}
+
+// PredictARIMA は自動選択した週次季節性付きの SARIMA モデルで予測します。
+// 予測値は負にならないよう 0 で打ち切ります
 func PredictARIMA(data []float64, horizon int) ([]float64, error) {
-	// 簡易実装として線形回帰を使用
-	return PredictLinearRegression(data, horizon)
+	model, err := AutoARIMA(data, WeeklyPeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	predictions, _ := model.Forecast(horizon)
+	for i := range predictions {
+		if predictions[i] < 0 {
+			predictions[i] = 0
+		}
+	}
+	return predictions, nil
 }
 
 // PredictLinearRegression は線形回帰による予測を行います
@@ -46,4 +431,4 @@ func PredictLinearRegression(data []float64, horizon int) ([]float64, error) {
 	}
 	
 	return predictions, nil
-}
\ No newline at end of file
+}
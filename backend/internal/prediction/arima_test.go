@@ -1,6 +1,10 @@
 package prediction
 
-import "testing"
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
 
 func TestPredictLinearRegressionLength(t *testing.T) {
 	data := []float64{1, 2, 3, 4, 5}
@@ -29,3 +33,129 @@ func TestPredictLinearRegressionNonNegative(t *testing.T) {
 		}
 	}
 }
+
+// simulateARMA generates an ARMA(1,1) series with Gaussian noise
+func simulateARMA(n int, mean, phi, theta, sigma float64, seed int64) []float64 {
+	rng := rand.New(rand.NewSource(seed))
+	data := make([]float64, n)
+	prevE, prevX := 0.0, 0.0
+	for i := range data {
+		e := rng.NormFloat64() * sigma
+		x := phi*prevX + e + theta*prevE
+		data[i] = mean + x
+		prevE, prevX = e, x
+	}
+	return data
+}
+
+func TestFitARIMARecoversAR1(t *testing.T) {
+	data := simulateARMA(400, 50, 0.6, 0, 1, 1)
+
+	model, err := FitARIMA(data, ARIMAOrder{P: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(model.AR[0]-0.6) > 0.1 {
+		t.Errorf("AR coefficient = %f, want about 0.6", model.AR[0])
+	}
+	if math.Abs(model.Mean-50) > 0.5 {
+		t.Errorf("mean = %f, want about 50", model.Mean)
+	}
+	if math.Abs(model.Sigma2-1) > 0.2 {
+		t.Errorf("sigma2 = %f, want about 1", model.Sigma2)
+	}
+}
+
+func TestFitARIMARecoversMA1(t *testing.T) {
+	data := simulateARMA(400, 0, 0, 0.5, 1, 2)
+
+	model, err := FitARIMA(data, ARIMAOrder{Q: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(model.MA[0]-0.5) > 0.12 {
+		t.Errorf("MA coefficient = %f, want about 0.5", model.MA[0])
+	}
+}
+
+func TestForecastContinuesTrend(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	data := make([]float64, 60)
+	for i := range data {
+		data[i] = 10 + 2*float64(i) + rng.NormFloat64()*0.5
+	}
+
+	model, err := AutoARIMA(data, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if model.Order.D == 0 {
+		t.Errorf("order %s: expected differencing for a trending series", model.Order)
+	}
+
+	forecasts, stdErrs := model.Forecast(5)
+	for h, f := range forecasts {
+		want := 10 + 2*float64(len(data)+h)
+		if math.Abs(f-want) > 3 {
+			t.Errorf("forecast %d = %f, want about %f", h, f, want)
+		}
+	}
+	for h := 1; h < len(stdErrs); h++ {
+		if stdErrs[h] < stdErrs[h-1] || stdErrs[h] <= 0 {
+			t.Errorf("standard errors should grow with the horizon: %v", stdErrs)
+			break
+		}
+	}
+}
+
+func TestAutoARIMAWeeklySeasonality(t *testing.T) {
+	pattern := []float64{10, 12, 14, 13, 18, 25, 22}
+	rng := rand.New(rand.NewSource(4))
+	data := make([]float64, 84)
+	for i := range data {
+		data[i] = 100 + pattern[i%WeeklyPeriod] + rng.NormFloat64()*0.5
+	}
+
+	model, err := AutoARIMA(data, WeeklyPeriod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !model.Order.seasonal() {
+		t.Fatalf("order %s: expected a seasonal model", model.Order)
+	}
+
+	forecasts, _ := model.Forecast(WeeklyPeriod)
+	for h, f := range forecasts {
+		want := 100 + pattern[(len(data)+h)%WeeklyPeriod]
+		if math.Abs(f-want) > 2 {
+			t.Errorf("forecast %d = %f, want about %f", h, f, want)
+		}
+	}
+}
+
+func TestAutoARIMAInsufficientData(t *testing.T) {
+	if _, err := AutoARIMA([]float64{1, 2, 3}, WeeklyPeriod); err != ErrInsufficientData {
+		t.Errorf("err = %v, want ErrInsufficientData", err)
+	}
+}
+
+func TestPacfToCoefficientsIsStationary(t *testing.T) {
+	for _, u := range [][]float64{{5, 5}, {-5, 5}, {5, -5}, {-3, -3}} {
+		phi := pacfToCoefficients(u)
+		// AR(2) stationarity triangle
+		if math.Abs(phi[1]) >= 1 || phi[0]+phi[1] >= 1 || phi[1]-phi[0] >= 1 {
+			t.Errorf("pacfToCoefficients(%v) = %v is not stationary", u, phi)
+		}
+	}
+}
+
+func TestNelderMeadRosenbrock(t *testing.T) {
+	rosenbrock := func(x []float64) float64 {
+		return (1-x[0])*(1-x[0]) + 100*(x[1]-x[0]*x[0])*(x[1]-x[0]*x[0])
+	}
+
+	x, value := nelderMead(rosenbrock, []float64{-1.2, 1}, 0.5, 5000)
+	if value > 1e-6 || math.Abs(x[0]-1) > 1e-2 || math.Abs(x[1]-1) > 1e-2 {
+		t.Errorf("minimum at %v (%g), want (1, 1)", x, value)
+	}
+}
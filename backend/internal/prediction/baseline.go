@@ -0,0 +1,158 @@
+package prediction
+
+import (
+	"fmt"
+	"math"
+)
+
+// NaiveModel は直近の値 (季節ナイーブでは 1 周期前の値) をそのまま予測値とする
+// ベースラインモデルです
+type NaiveModel struct {
+	Period int // 季節周期。0 なら直近の値を使う
+	Sigma2 float64
+	AIC    float64
+
+	last []float64 // 最後の 1 周期 (季節なしでは最後の 1 点)
+}
+
+// FitNaive はナイーブモデルを当てはめます。period が 2 以上なら季節ナイーブです
+func FitNaive(data []float64, period int) (*NaiveModel, error) {
+	lag := 1
+	if period > 1 {
+		lag = period
+	} else {
+		period = 0
+	}
+	// 誤差分散の推定に 2 点以上の誤差が必要
+	if len(data) < lag+2 {
+		return nil, ErrInsufficientData
+	}
+
+	errs := make([]float64, 0, len(data)-lag)
+	for t := lag; t < len(data); t++ {
+		errs = append(errs, data[t]-data[t-lag])
+	}
+
+	n := float64(len(errs))
+	model := &NaiveModel{Period: period}
+	model.Sigma2 = math.Max(sumSquares(errs)/n, 1e-12)
+	model.AIC = n*math.Log(model.Sigma2) + 2
+	model.last = append([]float64(nil), data[len(data)-lag:]...)
+
+	return model, nil
+}
+
+// Forecast は horizon 期先までの予測値と予測標準誤差を返します。誤差は周期を
+// 1 つ進むごとに足し合わされます
+func (m *NaiveModel) Forecast(horizon int) ([]float64, []float64) {
+	forecasts := make([]float64, horizon)
+	stdErrs := make([]float64, horizon)
+	lag := len(m.last)
+	for h := 0; h < horizon; h++ {
+		forecasts[h] = m.last[h%lag]
+		stdErrs[h] = math.Sqrt(m.Sigma2 * float64(h/lag+1))
+	}
+	return forecasts, stdErrs
+}
+
+// Intervals は horizon 期先までの予測区間を返します
+func (m *NaiveModel) Intervals(horizon int, levels []float64) [][]Interval {
+	forecasts, stdErrs := m.Forecast(horizon)
+	return NormalIntervals(forecasts, stdErrs, levels)
+}
+
+// String はモデルの表記を返します
+func (m *NaiveModel) String() string {
+	if m.Period > 1 {
+		return fmt.Sprintf("SNaive[%d]", m.Period)
+	}
+	return "Naive"
+}
+
+// Parameters は当てはめたパラメータを返します
+func (m *NaiveModel) Parameters() map[string]float64 {
+	return map[string]float64{"sigma2": m.Sigma2}
+}
+
+// LinearTrendModel は時間に対する最小二乗直線を延長するモデルです
+type LinearTrendModel struct {
+	Intercept float64
+	Slope     float64
+	Sigma2    float64
+	AIC       float64
+
+	n   int
+	sxx float64 // 時点の偏差平方和
+}
+
+// FitLinearTrend は線形トレンドモデルを当てはめます
+func FitLinearTrend(data []float64) (*LinearTrendModel, error) {
+	if len(data) < 3 {
+		return nil, ErrInsufficientData
+	}
+
+	n := float64(len(data))
+	meanX := (n - 1) / 2
+	meanY := 0.0
+	for _, v := range data {
+		meanY += v
+	}
+	meanY /= n
+
+	sxx, sxy := 0.0, 0.0
+	for t, v := range data {
+		dx := float64(t) - meanX
+		sxx += dx * dx
+		sxy += dx * (v - meanY)
+	}
+
+	model := &LinearTrendModel{n: len(data), sxx: sxx}
+	model.Slope = sxy / sxx
+	model.Intercept = meanY - model.Slope*meanX
+
+	sse := 0.0
+	for t, v := range data {
+		e := v - (model.Intercept + model.Slope*float64(t))
+		sse += e * e
+	}
+	// 予測区間には不偏分散、AIC には最尤推定の分散を使う
+	model.Sigma2 = math.Max(sse/(n-2), 1e-12)
+	model.AIC = n*math.Log(math.Max(sse/n, 1e-12)) + 2*3
+
+	return model, nil
+}
+
+// Forecast は horizon 期先までの予測値と予測標準誤差を返します。標準誤差には
+// 直線の推定誤差を含めます
+func (m *LinearTrendModel) Forecast(horizon int) ([]float64, []float64) {
+	forecasts := make([]float64, horizon)
+	stdErrs := make([]float64, horizon)
+	n := float64(m.n)
+	meanX := (n - 1) / 2
+	for h := 0; h < horizon; h++ {
+		x := n + float64(h)
+		forecasts[h] = m.Intercept + m.Slope*x
+		stdErrs[h] = math.Sqrt(m.Sigma2 * (1 + 1/n + (x-meanX)*(x-meanX)/m.sxx))
+	}
+	return forecasts, stdErrs
+}
+
+// Intervals は horizon 期先までの予測区間を返します
+func (m *LinearTrendModel) Intervals(horizon int, levels []float64) [][]Interval {
+	forecasts, stdErrs := m.Forecast(horizon)
+	return NormalIntervals(forecasts, stdErrs, levels)
+}
+
+// String はモデルの表記を返します
+func (m *LinearTrendModel) String() string {
+	return "Linear"
+}
+
+// Parameters は当てはめたパラメータを返します
+func (m *LinearTrendModel) Parameters() map[string]float64 {
+	return map[string]float64{
+		"intercept": m.Intercept,
+		"slope":     m.Slope,
+		"sigma2":    m.Sigma2,
+	}
+}
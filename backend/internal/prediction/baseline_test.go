@@ -0,0 +1,49 @@
+package prediction
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSeasonalNaiveRepeatsLastWeek(t *testing.T) {
+	data := make([]float64, 21)
+	for i := range data {
+		data[i] = float64(i%WeeklyPeriod) + float64(i/WeeklyPeriod)
+	}
+
+	model, err := FitNaive(data, WeeklyPeriod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forecasts, stdErrs := model.Forecast(2 * WeeklyPeriod)
+	for h, f := range forecasts {
+		if want := data[14+h%WeeklyPeriod]; f != want {
+			t.Errorf("horizon %d: forecast %f, want %f", h, f, want)
+		}
+	}
+	// The error of the second week adds to that of the first
+	if !(stdErrs[WeeklyPeriod] > stdErrs[WeeklyPeriod-1]) {
+		t.Errorf("standard errors should grow by week: %v", stdErrs)
+	}
+}
+
+func TestLinearTrendExtendsLine(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	data := make([]float64, 60)
+	for i := range data {
+		data[i] = 10 + 2*float64(i) + rng.NormFloat64()
+	}
+
+	model, err := FitLinearTrend(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(model.Slope-2) > 0.05 {
+		t.Errorf("slope = %f, want about 2", model.Slope)
+	}
+	forecasts, _ := model.Forecast(5)
+	if want := 10 + 2*64.0; math.Abs(forecasts[4]-want) > 2 {
+		t.Errorf("forecast = %f, want about %f", forecasts[4], want)
+	}
+}
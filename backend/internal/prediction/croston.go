@@ -0,0 +1,118 @@
+package prediction
+
+import (
+	"fmt"
+	"math"
+)
+
+// Croston 法の平滑化係数の探索範囲と刻み
+const (
+	crostonMinAlpha  = 0.05
+	crostonMaxAlpha  = 0.5
+	crostonAlphaStep = 0.05
+)
+
+// CrostonModel は値が 0 の日が多い間欠的な系列向けの Croston 法です。
+// 0 でない値の大きさと発生間隔を別々に指数平滑化し、その比を 1 日あたりの
+// 予測値とします
+type CrostonModel struct {
+	Alpha    float64
+	Demand   float64 // 平滑化した 0 でない値の大きさ
+	Interval float64 // 平滑化した発生間隔 (日)
+	Sigma2   float64
+	AIC      float64
+}
+
+// FitCroston は Croston 法を当てはめます。平滑化係数は 1 期先予測誤差の
+// 二乗和が最小になる値を格子探索で選びます
+func FitCroston(data []float64) (*CrostonModel, error) {
+	nonZero := 0
+	for _, v := range data {
+		if v != 0 {
+			nonZero++
+		}
+	}
+	if nonZero < 2 {
+		return nil, fmt.Errorf("croston requires at least two non-zero observations")
+	}
+
+	var best *CrostonModel
+	bestSSE := math.Inf(1)
+	for alpha := crostonMinAlpha; alpha <= crostonMaxAlpha+1e-9; alpha += crostonAlphaStep {
+		demand, interval, errs := runCroston(data, alpha)
+		sse := sumSquares(errs)
+		if sse >= bestSSE {
+			continue
+		}
+		bestSSE = sse
+
+		// 平滑化係数と初期の大きさ・間隔を推定量として数える
+		n := float64(len(errs))
+		sigma2 := math.Max(sse/n, 1e-12)
+		best = &CrostonModel{
+			Alpha:    alpha,
+			Demand:   demand,
+			Interval: interval,
+			Sigma2:   sigma2,
+			AIC:      n*math.Log(sigma2) + 2*(3+1),
+		}
+	}
+
+	return best, nil
+}
+
+// runCroston は最初の 0 でない値から平滑化を始め、最終的な大きさと間隔、
+// およびそれ以降の 1 期先予測誤差を返します
+func runCroston(data []float64, alpha float64) (demand, interval float64, errs []float64) {
+	first := 0
+	for data[first] == 0 {
+		first++
+	}
+	demand = data[first]
+	interval = float64(first + 1)
+
+	since := 0
+	for t := first + 1; t < len(data); t++ {
+		errs = append(errs, data[t]-demand/interval)
+		since++
+		if data[t] != 0 {
+			demand += alpha * (data[t] - demand)
+			interval += alpha * (float64(since) - interval)
+			since = 0
+		}
+	}
+	return demand, interval, errs
+}
+
+// Forecast は horizon 期先までの予測値と予測標準誤差を返します。予測値は
+// 一定で、標準誤差は単純指数平滑化の誤差分散の式による近似です
+func (m *CrostonModel) Forecast(horizon int) ([]float64, []float64) {
+	forecasts := make([]float64, horizon)
+	stdErrs := make([]float64, horizon)
+	for h := 0; h < horizon; h++ {
+		forecasts[h] = m.Demand / m.Interval
+		stdErrs[h] = math.Sqrt(m.Sigma2 * (1 + float64(h)*m.Alpha*m.Alpha))
+	}
+	return forecasts, stdErrs
+}
+
+// Intervals は horizon 期先までの予測区間を返します
+func (m *CrostonModel) Intervals(horizon int, levels []float64) [][]Interval {
+	forecasts, stdErrs := m.Forecast(horizon)
+	return NormalIntervals(forecasts, stdErrs, levels)
+}
+
+// String はモデルの表記を返します
+func (m *CrostonModel) String() string {
+	return "Croston"
+}
+
+// Parameters は当てはめたパラメータを返します
+func (m *CrostonModel) Parameters() map[string]float64 {
+	return map[string]float64{
+		"alpha":    m.Alpha,
+		"demand":   m.Demand,
+		"interval": m.Interval,
+		"sigma2":   m.Sigma2,
+	}
+}
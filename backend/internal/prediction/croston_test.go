@@ -0,0 +1,27 @@
+package prediction
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCrostonIntermittentDemand(t *testing.T) {
+	// A demand of 6 every third day averages 2 a day
+	data := make([]float64, 60)
+	for i := 2; i < len(data); i += 3 {
+		data[i] = 6
+	}
+
+	model, err := FitCroston(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forecasts, _ := model.Forecast(3)
+	if math.Abs(forecasts[0]-2) > 0.05 {
+		t.Errorf("forecast = %f, want about 2", forecasts[0])
+	}
+
+	if _, err := FitCroston([]float64{0, 0, 5, 0, 0}); err == nil {
+		t.Error("expected an error for a single non-zero observation")
+	}
+}
@@ -0,0 +1,285 @@
+package prediction
+
+import (
+	"fmt"
+	"math"
+)
+
+// 普及曲線の種類
+const (
+	DiffusionBass     = "bass"
+	DiffusionLogistic = "logistic"
+	DiffusionGompertz = "gompertz"
+)
+
+// DiffusionKinds は当てはめられる普及曲線の一覧です
+var DiffusionKinds = []string{DiffusionBass, DiffusionLogistic, DiffusionGompertz}
+
+// minDiffusionData は普及曲線を当てはめる最小の日数です
+const minDiffusionData = 14
+
+// maxDiffusionSaturation は探索する飽和水準の上限で、観測した累積の倍数です。
+// ピーク前のデータでは飽和水準が定まらず、上限がないと際限なく大きくなります
+const maxDiffusionSaturation = 100
+
+// 探索する速度のパラメータ (k, p, q) の範囲 (1 日あたり) です。1 日だけの急増
+// には階段関数が最もよく当てはまり、上限がないと速度が発散してピークの高さが
+// 無限大になります。Bass の p は 0 に近づくと q/p が溢れるため下限も設けます
+const (
+	minDiffusionRate = 1e-6
+	maxDiffusionRate = 5
+)
+
+// DiffusionModel は日次の値の累積に当てはめた普及曲線です。時刻は最初の日の
+// 始まりを 0 とする日数で、累積は最初の日からの値の合計です。データより前に
+// 始まった普及も表せるよう、曲線は F(t) - F(0) として累積に当てはめます
+type DiffusionModel struct {
+	Kind       string
+	Params     []float64 // 曲線のパラメータ (Parameters の順)
+	Saturation float64   // 累積が飽和する水準 (最初の日から数えた値)
+	PeakDay    float64   // 日次の値が最大になる日 (最初の日を 0 とする。データの範囲外もありうる)
+	PeakRate   float64   // 日次の値の最大値
+	SSE        float64   // 日次の値の誤差の二乗和
+	RMSE       float64   // 日次の値の平均二乗誤差の平方根
+	R2         float64   // 累積への当てはまりの決定係数
+	R2Daily    float64   // 日次の値への当てはまりの決定係数。累積の決定係数は常に高くなるため品質の判定にはこちらを使う
+	AIC        float64   // 日次の値の誤差に基づく AIC
+
+	curve diffusionCurve
+	theta []float64 // 変換後のパラメータ
+	n     int
+}
+
+// diffusionCurve は普及曲線の累積関数 F(θ, t) です。パラメータは最適化しやすい
+// よう対数などに変換した値で持ちます
+type diffusionCurve struct {
+	names  []string                                  // 変換前のパラメータ名
+	rates  []int                                     // 速度の対数を持つ θ の添字
+	cum    func(theta []float64, t float64) float64  // 累積 F(t)
+	params func(theta []float64) []float64           // 変換前のパラメータ
+	peak   func(theta []float64) (day, rate float64) // 日次の値のピークの時刻と高さ
+	starts func(n int, peakDay float64) [][]float64  // 探索の初期値 (累積を 1 に正規化した尺度)
+}
+
+var diffusionCurves = map[string]diffusionCurve{
+	// ロジスティック曲線 F(t) = m / (1 + exp(-k(t - t0)))。ピークは t0 で高さ mk/4
+	DiffusionLogistic: {
+		names: []string{"m", "k", "t0"},
+		rates: []int{1},
+		cum: func(theta []float64, t float64) float64 {
+			return math.Exp(theta[0]) / (1 + math.Exp(-math.Exp(theta[1])*(t-theta[2])))
+		},
+		params: func(theta []float64) []float64 {
+			return []float64{math.Exp(theta[0]), math.Exp(theta[1]), theta[2]}
+		},
+		peak: func(theta []float64) (float64, float64) {
+			return theta[2], math.Exp(theta[0]) * math.Exp(theta[1]) / 4
+		},
+		starts: sigmoidStarts,
+	},
+	// ゴンペルツ曲線 F(t) = m exp(-exp(-k(t - tp)))。ピークは tp で高さ mk/e
+	DiffusionGompertz: {
+		names: []string{"m", "k", "tp"},
+		rates: []int{1},
+		cum: func(theta []float64, t float64) float64 {
+			return math.Exp(theta[0]) * math.Exp(-math.Exp(-math.Exp(theta[1])*(t-theta[2])))
+		},
+		params: func(theta []float64) []float64 {
+			return []float64{math.Exp(theta[0]), math.Exp(theta[1]), theta[2]}
+		},
+		peak: func(theta []float64) (float64, float64) {
+			return theta[2], math.Exp(theta[0]) * math.Exp(theta[1]) / math.E
+		},
+		starts: sigmoidStarts,
+	},
+	// Bass モデル F(t) = m (1 - e^{-(p+q)u}) / (1 + (q/p) e^{-(p+q)u})、u = t + s。
+	// p は革新係数、q は模倣係数、s はデータより前に経過した日数です
+	DiffusionBass: {
+		names: []string{"m", "p", "q", "s"},
+		rates: []int{1, 2},
+		cum: func(theta []float64, t float64) float64 {
+			m, p, q, s := math.Exp(theta[0]), math.Exp(theta[1]), math.Exp(theta[2]), theta[3]*theta[3]
+			e := math.Exp(-(p + q) * (t + s))
+			return m * (1 - e) / (1 + q/p*e)
+		},
+		params: func(theta []float64) []float64 {
+			return []float64{math.Exp(theta[0]), math.Exp(theta[1]), math.Exp(theta[2]), theta[3] * theta[3]}
+		},
+		peak: func(theta []float64) (float64, float64) {
+			m, p, q, s := math.Exp(theta[0]), math.Exp(theta[1]), math.Exp(theta[2]), theta[3]*theta[3]
+			if q <= p {
+				// 模倣が弱いと普及の開始時が最大になる
+				return -s, m * p
+			}
+			return math.Log(q/p)/(p+q) - s, m * (p + q) * (p + q) / (4 * q)
+		},
+		starts: func(n int, peakDay float64) [][]float64 {
+			var starts [][]float64
+			for _, m := range []float64{1.2, 2, 4} {
+				for _, p := range []float64{0.001, 0.01} {
+					for _, q := range []float64{0.05, 0.2} {
+						for _, s := range []float64{0, float64(n) / 2} {
+							starts = append(starts, []float64{math.Log(m), math.Log(p), math.Log(q), math.Sqrt(s)})
+						}
+					}
+				}
+			}
+			return starts
+		},
+	},
+}
+
+// sigmoidStarts はロジスティック曲線とゴンペルツ曲線の初期値です。ピークの
+// 時刻は日次の値が最大の日から探索を始めます
+func sigmoidStarts(n int, peakDay float64) [][]float64 {
+	var starts [][]float64
+	for _, m := range []float64{1.2, 2, 4} {
+		for _, k := range []float64{0.03, 0.1, 0.3} {
+			starts = append(starts, []float64{math.Log(m), math.Log(k), peakDay})
+		}
+	}
+	return starts
+}
+
+// IsDiffusionKind は kind が当てはめられる普及曲線かどうかを返します
+func IsDiffusionKind(kind string) bool {
+	_, ok := diffusionCurves[kind]
+	return ok
+}
+
+// FitDiffusion は日次の値の累積に普及曲線を非線形最小二乗法で当てはめます。
+// 累積を合計で正規化し、初期値を変えて Nelder-Mead 法で探索した最良の解を使います
+func FitDiffusion(data []float64, kind string) (*DiffusionModel, error) {
+	curve, ok := diffusionCurves[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown diffusion curve %q", kind)
+	}
+	n := len(data)
+	if n < minDiffusionData {
+		return nil, ErrInsufficientData
+	}
+
+	cumulative := make([]float64, n)
+	total := 0.0
+	peakDay := 0
+	for t, v := range data {
+		total += v
+		cumulative[t] = total
+		if v > data[peakDay] {
+			peakDay = t
+		}
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("no volume to fit a diffusion curve to")
+	}
+	y := make([]float64, n)
+	for t := range cumulative {
+		y[t] = cumulative[t] / total
+	}
+
+	// 累積は t 日目の終わり、すなわち時刻 t+1 の値です
+	sse := func(theta []float64) float64 {
+		if theta[0] > math.Log(maxDiffusionSaturation) {
+			return math.Inf(1)
+		}
+		for _, i := range curve.rates {
+			if theta[i] < math.Log(minDiffusionRate) || theta[i] > math.Log(maxDiffusionRate) {
+				return math.Inf(1)
+			}
+		}
+		origin := curve.cum(theta, 0)
+		sum := 0.0
+		for t := range y {
+			e := y[t] - (curve.cum(theta, float64(t+1)) - origin)
+			sum += e * e
+		}
+		return sum
+	}
+
+	var best []float64
+	bestValue := math.Inf(1)
+	for _, start := range curve.starts(n, float64(peakDay)+0.5) {
+		theta, value := nelderMead(sse, start, 0.3, 3000)
+		if value < bestValue {
+			best, bestValue = theta, value
+		}
+	}
+	if math.IsInf(bestValue, 1) {
+		return nil, fmt.Errorf("%s curve did not converge", kind)
+	}
+
+	// 正規化した尺度の m を元の尺度に戻す
+	best[0] += math.Log(total)
+	model := &DiffusionModel{Kind: kind, Params: curve.params(best), curve: curve, theta: best, n: n}
+	model.Saturation = math.Exp(best[0]) - curve.cum(best, 0)
+	day, rate := curve.peak(best)
+	model.PeakDay, model.PeakRate = day-0.5, rate
+
+	// 当てはまりは累積と日次の値の両方で測る
+	meanDaily := total / float64(n)
+	meanCum := 0.0
+	for _, c := range cumulative {
+		meanCum += c
+	}
+	meanCum /= float64(n)
+	var ssCum, ssTotCum, ssTotDaily float64
+	for t := range data {
+		e := cumulative[t] - model.Cumulative(t)
+		ssCum += e * e
+		ssTotCum += (cumulative[t] - meanCum) * (cumulative[t] - meanCum)
+		d := data[t] - model.Daily(t)
+		model.SSE += d * d
+		ssTotDaily += (data[t] - meanDaily) * (data[t] - meanDaily)
+	}
+	model.RMSE = math.Sqrt(model.SSE / float64(n))
+	model.R2 = 1 - ssCum/math.Max(ssTotCum, 1e-12)
+	model.R2Daily = 1 - model.SSE/math.Max(ssTotDaily, 1e-12)
+	model.AIC = float64(n)*math.Log(math.Max(model.SSE/float64(n), 1e-12)) + 2*float64(len(best))
+
+	return model, nil
+}
+
+// Cumulative は最初の日から day 日目 (0 始まり) までの累積の当てはめ値を返します
+func (m *DiffusionModel) Cumulative(day int) float64 {
+	return m.curve.cum(m.theta, float64(day+1)) - m.curve.cum(m.theta, 0)
+}
+
+// Daily は day 日目 (0 始まり) の日次の値の当てはめ値を返します
+func (m *DiffusionModel) Daily(day int) float64 {
+	return m.curve.cum(m.theta, float64(day+1)) - m.curve.cum(m.theta, float64(day))
+}
+
+// Forecast はデータの翌日から horizon 日分の日次の値を返します
+func (m *DiffusionModel) Forecast(horizon int) []float64 {
+	forecasts := make([]float64, horizon)
+	for h := range forecasts {
+		forecasts[h] = m.Daily(m.n + h)
+	}
+	return forecasts
+}
+
+// String はモデルの表記を返します
+func (m *DiffusionModel) String() string {
+	return fmt.Sprintf("%s(%s)", m.Kind, formatParams(m.curve.names, m.Params))
+}
+
+// Parameters は当てはめたパラメータを返します
+func (m *DiffusionModel) Parameters() map[string]float64 {
+	params := make(map[string]float64, len(m.Params))
+	for i, name := range m.curve.names {
+		params[name] = m.Params[i]
+	}
+	return params
+}
+
+// formatParams は name=value の並びを返します
+func formatParams(names []string, values []float64) string {
+	s := ""
+	for i, name := range names {
+		if i > 0 {
+			s += ", "
+		}
+		s += fmt.Sprintf("%s=%.4g", name, values[i])
+	}
+	return s
+}
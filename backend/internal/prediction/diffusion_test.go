@@ -0,0 +1,67 @@
+package prediction
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFitDiffusionRecoversPeak(t *testing.T) {
+	// Daily volume of a logistic curve with m=2000, k=0.15 and the peak on day 50
+	rng := rand.New(rand.NewSource(4))
+	data := make([]float64, 80)
+	for i := range data {
+		e := math.Exp(-0.15 * (float64(i) + 0.5 - 50))
+		data[i] = 2000*0.15*e/((1+e)*(1+e)) + rng.NormFloat64()*2
+	}
+
+	for _, kind := range DiffusionKinds {
+		model, err := FitDiffusion(data, kind)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The skewed Gompertz curve places the peak of symmetric data a few days early
+		if math.Abs(model.PeakDay-50) > 6 {
+			t.Errorf("%s: peak day = %f, want about 50", kind, model.PeakDay)
+		}
+		if model.R2Daily < 0.8 {
+			t.Errorf("%s: daily R2 = %f, want a close fit", kind, model.R2Daily)
+		}
+	}
+
+	model, _ := FitDiffusion(data, DiffusionLogistic)
+	if math.Abs(model.Saturation-2000) > 150 {
+		t.Errorf("saturation = %f, want about 2000", model.Saturation)
+	}
+	if _, err := FitDiffusion(data[:10], DiffusionBass); err != ErrInsufficientData {
+		t.Errorf("err = %v, want ErrInsufficientData", err)
+	}
+}
+
+func TestFitDiffusionOneDaySpikeStaysFinite(t *testing.T) {
+	// A single day of volume is best fitted by a step, which drives the rates
+	// of the curves to their bounds
+	for _, day := range []int{0, 15, 29} {
+		data := make([]float64, 30)
+		data[day] = 500
+
+		for _, kind := range DiffusionKinds {
+			model, err := FitDiffusion(data, kind)
+			if err != nil {
+				t.Fatalf("%s: %v", kind, err)
+			}
+			values := []float64{model.Saturation, model.PeakDay, model.PeakRate, model.SSE, model.RMSE, model.R2, model.R2Daily, model.AIC}
+			for _, v := range append(values, model.Params...) {
+				if math.IsNaN(v) || math.IsInf(v, 0) {
+					t.Errorf("spike on day %d: non-finite value in %s, peak %v on day %v", day, model, model.PeakRate, model.PeakDay)
+					break
+				}
+			}
+			for name, v := range model.Parameters() {
+				if (name == "k" || name == "p" || name == "q") && (v < minDiffusionRate*0.999 || v > maxDiffusionRate*1.001) {
+					t.Errorf("spike on day %d: %s %s = %v outside the bounds", day, kind, name, v)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,363 @@
+package prediction
+
+import (
+	"fmt"
+	"math"
+)
+
+// Holt-Winters の季節成分の種類
+const (
+	SeasonalNone           = "none"
+	SeasonalAdditive       = "additive"
+	SeasonalMultiplicative = "multiplicative"
+)
+
+// 減衰係数 φ の範囲。1 に近いほど減衰が弱くなります
+const (
+	minDamping = 0.8
+	maxDamping = 0.98
+)
+
+// HoltWintersConfig は Holt-Winters モデルの構成です
+type HoltWintersConfig struct {
+	Seasonal string // SeasonalNone, SeasonalAdditive または SeasonalMultiplicative
+	Period   int    // 季節の周期 (Seasonal が SeasonalNone 以外のとき)
+	Damped   bool   // トレンドを減衰させるか
+}
+
+// String はモデルの表記を返します
+func (c HoltWintersConfig) String() string {
+	trend := "A"
+	if c.Damped {
+		trend = "Ad"
+	}
+	switch c.Seasonal {
+	case SeasonalAdditive:
+		return fmt.Sprintf("HW(%s,A)[%d]", trend, c.Period)
+	case SeasonalMultiplicative:
+		return fmt.Sprintf("HW(%s,M)[%d]", trend, c.Period)
+	}
+	return fmt.Sprintf("Holt(%s)", trend)
+}
+
+// HoltWintersModel は平滑化パラメータを当てはめた Holt-Winters モデルです。
+// パラメータは 1 期先予測誤差の二乗和を最小化して推定します
+type HoltWintersModel struct {
+	Config HoltWintersConfig
+	Alpha  float64 // 水準の平滑化パラメータ
+	Beta   float64 // トレンドの平滑化パラメータ
+	Gamma  float64 // 季節成分の平滑化パラメータ
+	Phi    float64 // トレンドの減衰係数 (減衰なしでは 1)
+	SSE    float64 // 1 期先予測誤差の二乗和
+	Sigma2 float64 // 1 期先予測誤差の分散
+	AIC    float64
+
+	state     holtWintersState // 最終状態。季節成分は 1 期先から 1 周期分
+	residuals []float64        // 1 期先予測誤差
+}
+
+// holtWintersState は平滑化の状態です
+type holtWintersState struct {
+	level, trend float64
+	season       []float64
+}
+
+// FitHoltWinters は指定した構成の Holt-Winters モデルを当てはめます。
+// 乗法的季節成分は正の値の系列にのみ使えます
+func FitHoltWinters(data []float64, config HoltWintersConfig) (*HoltWintersModel, error) {
+	period := 0
+	switch config.Seasonal {
+	case SeasonalNone:
+	case SeasonalAdditive, SeasonalMultiplicative:
+		if config.Period < 2 {
+			return nil, fmt.Errorf("invalid seasonal period %d", config.Period)
+		}
+		period = config.Period
+	default:
+		return nil, fmt.Errorf("unknown seasonal component %q", config.Seasonal)
+	}
+	if config.Seasonal == SeasonalNone {
+		config.Period = 0
+	}
+
+	// 季節成分の初期化に 2 周期、季節なしでも数点が必要
+	if len(data) < 2*period || len(data) < 4 {
+		return nil, ErrInsufficientData
+	}
+	if config.Seasonal == SeasonalMultiplicative {
+		for _, v := range data {
+			if v <= 0 {
+				return nil, fmt.Errorf("multiplicative seasonality requires positive data")
+			}
+		}
+	}
+
+	initial := initialHoltWintersState(data, config)
+
+	// 変数は制約のない値で、ロジスティック関数で範囲内に写す
+	nParams := 2
+	if config.Seasonal != SeasonalNone {
+		nParams++
+	}
+	if config.Damped {
+		nParams++
+	}
+	unpack := func(x []float64) (alpha, beta, gamma, phi float64) {
+		alpha = logistic(x[0])
+		beta = logistic(x[1])
+		phi = 1
+		i := 2
+		if config.Seasonal != SeasonalNone {
+			gamma = logistic(x[i])
+			i++
+		}
+		if config.Damped {
+			phi = minDamping + (maxDamping-minDamping)*logistic(x[i])
+		}
+		return alpha, beta, gamma, phi
+	}
+
+	objective := func(x []float64) float64 {
+		alpha, beta, gamma, phi := unpack(x)
+		_, errs := runHoltWinters(data, config, initial, alpha, beta, gamma, phi)
+		return sumSquares(errs)
+	}
+	x0 := make([]float64, nParams)
+	x0[0] = -1 // α ≈ 0.27 から探索を始める
+	x, _ := nelderMead(objective, x0, 0.5, 300*nParams)
+
+	model := &HoltWintersModel{Config: config}
+	model.Alpha, model.Beta, model.Gamma, model.Phi = unpack(x)
+	state, errs := runHoltWinters(data, config, initial, model.Alpha, model.Beta, model.Gamma, model.Phi)
+	model.state, model.residuals = state, errs
+	sse := sumSquares(errs)
+
+	// 誤差は初期化に使わなかった時点から数え、初期状態 (水準・トレンド・
+	// 季節成分) も推定量として数える
+	n := float64(len(errs))
+	k := float64(nParams + 2 + period)
+	model.SSE = sse
+	model.Sigma2 = math.Max(sse/n, 1e-12)
+	model.AIC = n*math.Log(model.Sigma2) + 2*(k+1)
+
+	return model, nil
+}
+
+// initialHoltWintersState は最初の 2 周期 (季節なしでは最初の 2 点) から
+// 初期状態を求めます
+func initialHoltWintersState(data []float64, config HoltWintersConfig) holtWintersState {
+	if config.Seasonal == SeasonalNone {
+		return holtWintersState{level: data[0], trend: data[1] - data[0]}
+	}
+
+	m := config.Period
+	first, second := 0.0, 0.0
+	for i := 0; i < m; i++ {
+		first += data[i]
+		second += data[m+i]
+	}
+	first /= float64(m)
+	second /= float64(m)
+
+	state := holtWintersState{
+		level:  first,
+		trend:  (second - first) / float64(m),
+		season: make([]float64, m),
+	}
+	for i := 0; i < m; i++ {
+		if config.Seasonal == SeasonalMultiplicative {
+			state.season[i] = data[i] / first
+		} else {
+			state.season[i] = data[i] - first
+		}
+	}
+	return state
+}
+
+// runHoltWinters は系列全体に平滑化を適用し、最終状態と 1 期先予測誤差を
+// 返します。季節ありでは最初の 1 周期を初期化に使います
+func runHoltWinters(data []float64, config HoltWintersConfig, initial holtWintersState, alpha, beta, gamma, phi float64) (holtWintersState, []float64) {
+	state := holtWintersState{
+		level:  initial.level,
+		trend:  initial.trend,
+		season: append([]float64(nil), initial.season...),
+	}
+	m := len(state.season)
+
+	start := 1
+	if m > 0 {
+		start = m
+	}
+
+	errs := make([]float64, 0, len(data)-start)
+	for t := start; t < len(data); t++ {
+		slot := 0
+		if m > 0 {
+			slot = t % m
+		}
+		errs = append(errs, state.update(data[t], slot, config.Seasonal, alpha, beta, gamma, phi))
+	}
+
+	// 季節成分を系列の最後の時点の次から順に並べ替える
+	ordered := make([]float64, m)
+	for i := range ordered {
+		ordered[i] = state.season[(len(data)+i)%m]
+	}
+	state.season = ordered
+	return state, errs
+}
+
+// forecast は季節成分の位置 slot での 1 期先予測値を返します
+func (s *holtWintersState) forecast(slot int, seasonal string, phi float64) float64 {
+	base := s.level + phi*s.trend
+	switch seasonal {
+	case SeasonalAdditive:
+		return base + s.season[slot]
+	case SeasonalMultiplicative:
+		return base * s.season[slot]
+	}
+	return base
+}
+
+// update は観測値 y で状態を更新し、1 期先予測誤差を返します
+func (s *holtWintersState) update(y float64, slot int, seasonal string, alpha, beta, gamma, phi float64) float64 {
+	base := s.level + phi*s.trend
+	prevLevel := s.level
+	e := y - s.forecast(slot, seasonal, phi)
+
+	switch seasonal {
+	case SeasonalAdditive:
+		season := s.season[slot]
+		s.level = alpha*(y-season) + (1-alpha)*base
+		s.season[slot] = gamma*(y-base) + (1-gamma)*season
+	case SeasonalMultiplicative:
+		season := s.season[slot]
+		s.level = alpha*(y/season) + (1-alpha)*base
+		if base > 0 {
+			s.season[slot] = gamma*(y/base) + (1-gamma)*season
+		}
+	default:
+		s.level = alpha*y + (1-alpha)*base
+	}
+	s.trend = beta*(s.level-prevLevel) + (1-beta)*phi*s.trend
+	return e
+}
+
+// Forecast は horizon 期先までの予測値と予測標準誤差を返します。標準誤差は
+// 加法モデルの誤差分散の式による近似です
+func (m *HoltWintersModel) Forecast(horizon int) ([]float64, []float64) {
+	forecasts := make([]float64, horizon)
+	stdErrs := make([]float64, horizon)
+
+	damping := 0.0 // φ + φ² + ... + φ^h
+	variance := 0.0
+	for h := 0; h < horizon; h++ {
+		damping += math.Pow(m.Phi, float64(h+1))
+		base := m.state.level + damping*m.state.trend
+
+		switch m.Config.Seasonal {
+		case SeasonalAdditive:
+			forecasts[h] = base + m.state.season[h%len(m.state.season)]
+		case SeasonalMultiplicative:
+			forecasts[h] = base * m.state.season[h%len(m.state.season)]
+		default:
+			forecasts[h] = base
+		}
+
+		// Var(h) = σ²(1 + Σ_{j<h} c_j²), c_j = α(1 + β(φ+...+φ^j)) + γ(1-α)[j が周期の倍数]
+		if h > 0 {
+			c := m.Alpha * (1 + m.Beta*(damping-math.Pow(m.Phi, float64(h+1))))
+			if m.Config.Seasonal != SeasonalNone && h%m.Config.Period == 0 {
+				c += m.Gamma * (1 - m.Alpha)
+			}
+			variance += c * c
+		}
+		stdErrs[h] = math.Sqrt(m.Sigma2 * (1 + variance))
+	}
+
+	return forecasts, stdErrs
+}
+
+// Intervals は horizon 期先までの予測区間を返します。乗法的季節成分には誤差
+// 分散の閉じた式がないため、1 期先予測誤差をリサンプリングした将来の経路から
+// 求めます
+func (m *HoltWintersModel) Intervals(horizon int, levels []float64) [][]Interval {
+	if m.Config.Seasonal != SeasonalMultiplicative {
+		forecasts, stdErrs := m.Forecast(horizon)
+		return NormalIntervals(forecasts, stdErrs, levels)
+	}
+
+	return bootstrapIntervals(m.residuals, horizon, levels, func(draw func() float64) []float64 {
+		state := m.state
+		state.season = append([]float64(nil), m.state.season...)
+		path := make([]float64, horizon)
+		for h := range path {
+			slot := h % len(state.season)
+			path[h] = state.forecast(slot, m.Config.Seasonal, m.Phi) + draw()
+			state.update(path[h], slot, m.Config.Seasonal, m.Alpha, m.Beta, m.Gamma, m.Phi)
+		}
+		return path
+	})
+}
+
+// String はモデルの表記を返します
+func (m *HoltWintersModel) String() string {
+	return m.Config.String()
+}
+
+// Parameters は当てはめたパラメータを返します
+func (m *HoltWintersModel) Parameters() map[string]float64 {
+	params := map[string]float64{
+		"alpha":  m.Alpha,
+		"beta":   m.Beta,
+		"sigma2": m.Sigma2,
+	}
+	if m.Config.Seasonal != SeasonalNone {
+		params["gamma"] = m.Gamma
+	}
+	if m.Config.Damped {
+		params["phi"] = m.Phi
+	}
+	return params
+}
+
+// AutoHoltWinters は季節成分 (なし・加法・乗法) と減衰の有無の組み合わせから
+// AIC が最小のモデルを選びます。period が 1 以下なら季節成分は使いません
+func AutoHoltWinters(data []float64, period int) (*HoltWintersModel, error) {
+	seasonals := []string{SeasonalNone}
+	if period > 1 && len(data) >= 2*period {
+		seasonals = append(seasonals, SeasonalAdditive, SeasonalMultiplicative)
+	}
+
+	var best *HoltWintersModel
+	for _, seasonal := range seasonals {
+		for _, damped := range []bool{false, true} {
+			model, err := FitHoltWinters(data, HoltWintersConfig{Seasonal: seasonal, Period: period, Damped: damped})
+			if err != nil {
+				continue
+			}
+			if best == nil || model.AIC < best.AIC {
+				best = model
+			}
+		}
+	}
+
+	if best == nil {
+		return nil, ErrInsufficientData
+	}
+	return best, nil
+}
+
+// sumSquares は二乗和を返します
+func sumSquares(x []float64) float64 {
+	sum := 0.0
+	for _, v := range x {
+		sum += v * v
+	}
+	return sum
+}
+
+// logistic は実数を (0, 1) に写します
+func logistic(x float64) float64 {
+	return 1 / (1 + math.Exp(-x))
+}
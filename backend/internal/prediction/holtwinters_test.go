@@ -0,0 +1,85 @@
+package prediction
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestHoltWintersAdditiveSeasonality(t *testing.T) {
+	pattern := []float64{-5, -3, 0, 1, 3, 6, -2}
+	rng := rand.New(rand.NewSource(5))
+	data := make([]float64, 70)
+	for i := range data {
+		data[i] = 50 + 0.5*float64(i) + pattern[i%WeeklyPeriod] + rng.NormFloat64()*0.3
+	}
+
+	model, err := FitHoltWinters(data, HoltWintersConfig{Seasonal: SeasonalAdditive, Period: WeeklyPeriod})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []float64{model.Alpha, model.Beta, model.Gamma} {
+		if p <= 0 || p >= 1 {
+			t.Errorf("smoothing parameter %f outside (0, 1)", p)
+		}
+	}
+
+	forecasts, stdErrs := model.Forecast(WeeklyPeriod)
+	for h, f := range forecasts {
+		i := len(data) + h
+		want := 50 + 0.5*float64(i) + pattern[i%WeeklyPeriod]
+		if math.Abs(f-want) > 1.5 {
+			t.Errorf("forecast %d = %f, want about %f", h, f, want)
+		}
+	}
+	if stdErrs[0] <= 0 || stdErrs[len(stdErrs)-1] < stdErrs[0] {
+		t.Errorf("standard errors = %v", stdErrs)
+	}
+}
+
+func TestHoltWintersDampedTrendFlattens(t *testing.T) {
+	data := make([]float64, 30)
+	for i := range data {
+		data[i] = 10 + 3*float64(i)
+	}
+
+	model, err := FitHoltWinters(data, HoltWintersConfig{Seasonal: SeasonalNone, Damped: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if model.Phi < minDamping || model.Phi > maxDamping {
+		t.Errorf("phi = %f outside [%f, %f]", model.Phi, minDamping, maxDamping)
+	}
+
+	forecasts, _ := model.Forecast(60)
+	first, last := forecasts[1]-forecasts[0], forecasts[59]-forecasts[58]
+	if last >= first {
+		t.Errorf("damped trend should flatten: first step %f, last step %f", first, last)
+	}
+}
+
+func TestHoltWintersMultiplicativeRequiresPositiveData(t *testing.T) {
+	data := make([]float64, 28)
+	data[3] = -1
+	if _, err := FitHoltWinters(data, HoltWintersConfig{Seasonal: SeasonalMultiplicative, Period: WeeklyPeriod}); err == nil {
+		t.Error("expected an error for non-positive data")
+	}
+}
+
+func TestAutoHoltWintersPicksMultiplicative(t *testing.T) {
+	pattern := []float64{0.6, 0.8, 1.0, 1.1, 1.2, 1.5, 0.8}
+	rng := rand.New(rand.NewSource(6))
+	data := make([]float64, 84)
+	for i := range data {
+		level := 20 + 2*float64(i)
+		data[i] = level * pattern[i%WeeklyPeriod] * (1 + rng.NormFloat64()*0.01)
+	}
+
+	model, err := AutoHoltWinters(data, WeeklyPeriod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if model.Config.Seasonal != SeasonalMultiplicative {
+		t.Errorf("selected %s, want multiplicative seasonality", model.Config)
+	}
+}
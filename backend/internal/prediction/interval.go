@@ -0,0 +1,97 @@
+package prediction
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// DefaultIntervalLevels は予測区間の既定の信頼水準です
+var DefaultIntervalLevels = []float64{0.8, 0.95}
+
+// bootstrapPaths はブートストラップで生成する将来の経路の数です
+const bootstrapPaths = 1000
+
+// bootstrapSeed は区間が要求ごとに揺れないよう固定した乱数の種です
+const bootstrapSeed = 1
+
+// Interval は予測区間です
+type Interval struct {
+	Level float64 // 信頼水準 (0.8 なら 80% 区間)
+	Lower float64
+	Upper float64
+}
+
+// ValidateLevels は信頼水準がすべて 0 と 1 の間にあるかを確認します
+func ValidateLevels(levels []float64) error {
+	for _, level := range levels {
+		if level <= 0 || level >= 1 {
+			return fmt.Errorf("interval level %g must be between 0 and 1", level)
+		}
+	}
+	return nil
+}
+
+// NormalQuantile は標準正規分布の p 分位点を返します
+func NormalQuantile(p float64) float64 {
+	return math.Sqrt2 * math.Erfinv(2*p-1)
+}
+
+// NormalIntervals は予測誤差を正規分布とみなし、予測値と標準誤差から
+// 各時点の予測区間を求めます
+func NormalIntervals(forecasts, stdErrs []float64, levels []float64) [][]Interval {
+	intervals := make([][]Interval, len(forecasts))
+	for h := range forecasts {
+		intervals[h] = make([]Interval, len(levels))
+		for i, level := range levels {
+			half := NormalQuantile(0.5+level/2) * stdErrs[h]
+			intervals[h][i] = Interval{Level: level, Lower: forecasts[h] - half, Upper: forecasts[h] + half}
+		}
+	}
+	return intervals
+}
+
+// bootstrapIntervals は simulate が生成する将来の経路の分位点から予測区間を
+// 求めます。simulate は 1 期先誤差を draw で引いて horizon 期分の経路を返します
+func bootstrapIntervals(residuals []float64, horizon int, levels []float64, simulate func(draw func() float64) []float64) [][]Interval {
+	rng := rand.New(rand.NewSource(bootstrapSeed))
+	draw := func() float64 {
+		return residuals[rng.Intn(len(residuals))]
+	}
+
+	samples := make([][]float64, horizon)
+	for p := 0; p < bootstrapPaths; p++ {
+		for h, v := range simulate(draw) {
+			samples[h] = append(samples[h], v)
+		}
+	}
+
+	intervals := make([][]Interval, horizon)
+	for h := range samples {
+		sort.Float64s(samples[h])
+		intervals[h] = make([]Interval, len(levels))
+		for i, level := range levels {
+			intervals[h][i] = Interval{
+				Level: level,
+				Lower: quantile(samples[h], (1-level)/2),
+				Upper: quantile(samples[h], (1+level)/2),
+			}
+		}
+	}
+	return intervals
+}
+
+// quantile は昇順に並んだ標本の p 分位点を線形補間で返します
+func quantile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return math.NaN()
+	}
+	pos := p * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	if lower+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	frac := pos - float64(lower)
+	return sorted[lower]*(1-frac) + sorted[lower+1]*frac
+}
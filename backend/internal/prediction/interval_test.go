@@ -0,0 +1,65 @@
+package prediction
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestNormalIntervals(t *testing.T) {
+	if q := NormalQuantile(0.975); math.Abs(q-1.959964) > 1e-5 {
+		t.Errorf("NormalQuantile(0.975) = %f", q)
+	}
+
+	intervals := NormalIntervals([]float64{100}, []float64{10}, []float64{0.8, 0.95})
+	if len(intervals) != 1 || len(intervals[0]) != 2 {
+		t.Fatalf("intervals = %v", intervals)
+	}
+	i80, i95 := intervals[0][0], intervals[0][1]
+	if math.Abs(i80.Upper-112.8155) > 1e-3 || math.Abs((i80.Lower+i80.Upper)/2-100) > 1e-9 {
+		t.Errorf("80%% interval = %+v", i80)
+	}
+	if i95.Lower >= i80.Lower || i95.Upper <= i80.Upper {
+		t.Errorf("95%% interval %+v should contain 80%% interval %+v", i95, i80)
+	}
+}
+
+func TestValidateLevels(t *testing.T) {
+	if err := ValidateLevels([]float64{0.5, 0.99}); err != nil {
+		t.Error(err)
+	}
+	for _, level := range []float64{0, 1, 80} {
+		if err := ValidateLevels([]float64{level}); err == nil {
+			t.Errorf("level %g: expected an error", level)
+		}
+	}
+}
+
+func TestHoltWintersMultiplicativeBootstrapIntervals(t *testing.T) {
+	pattern := []float64{0.6, 0.8, 1.0, 1.1, 1.2, 1.5, 0.8}
+	rng := rand.New(rand.NewSource(7))
+	data := make([]float64, 84)
+	for i := range data {
+		data[i] = 100 * pattern[i%WeeklyPeriod] * (1 + rng.NormFloat64()*0.05)
+	}
+
+	model, err := FitHoltWinters(data, HoltWintersConfig{Seasonal: SeasonalMultiplicative, Period: WeeklyPeriod})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	forecasts, _ := model.Forecast(WeeklyPeriod)
+	intervals := model.Intervals(WeeklyPeriod, []float64{0.8, 0.95})
+	for h, f := range forecasts {
+		i80, i95 := intervals[h][0], intervals[h][1]
+		if !(i95.Lower < i80.Lower && i80.Lower < f && f < i80.Upper && i80.Upper < i95.Upper) {
+			t.Errorf("horizon %d: forecast %f, intervals %+v %+v", h, f, i80, i95)
+		}
+	}
+
+	// Bootstrap intervals are reproducible
+	again := model.Intervals(WeeklyPeriod, []float64{0.8, 0.95})
+	if again[3][0] != intervals[3][0] {
+		t.Errorf("intervals differ between calls: %+v, %+v", again[3][0], intervals[3][0])
+	}
+}
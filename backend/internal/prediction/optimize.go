@@ -0,0 +1,112 @@
+package prediction
+
+import (
+	"math"
+	"sort"
+)
+
+// Nelder-Mead 法の係数 (反射・拡大・収縮・縮小)
+const (
+	nmReflect  = 1.0
+	nmExpand   = 2.0
+	nmContract = 0.5
+	nmShrink   = 0.5
+	nmTol      = 1e-10
+)
+
+// nelderMead は f を最小化する点を Nelder-Mead 法で探索し、その点と値を返します。
+// step は初期単体の各軸方向の幅です。f が NaN を返す点は +Inf として扱います
+func nelderMead(f func([]float64) float64, x0 []float64, step float64, maxIter int) ([]float64, float64) {
+	n := len(x0)
+	eval := func(x []float64) float64 {
+		v := f(x)
+		if math.IsNaN(v) {
+			return math.Inf(1)
+		}
+		return v
+	}
+
+	if n == 0 {
+		return nil, eval(x0)
+	}
+
+	// 初期単体: x0 と各軸方向に step だけずらした n 点
+	simplex := make([][]float64, n+1)
+	values := make([]float64, n+1)
+	for i := range simplex {
+		simplex[i] = append([]float64(nil), x0...)
+		if i > 0 {
+			simplex[i][i-1] += step
+		}
+		values[i] = eval(simplex[i])
+	}
+
+	order := make([]int, n+1)
+	centroid := make([]float64, n)
+	point := func(from []float64, towards []float64, coef float64) []float64 {
+		p := make([]float64, n)
+		for j := range p {
+			p[j] = from[j] + coef*(towards[j]-from[j])
+		}
+		return p
+	}
+
+	for iter := 0; iter < maxIter; iter++ {
+		for i := range order {
+			order[i] = i
+		}
+		sort.Slice(order, func(a, b int) bool { return values[order[a]] < values[order[b]] })
+		best, worst, second := order[0], order[n], order[n-1]
+
+		if math.Abs(values[worst]-values[best]) <= nmTol*(math.Abs(values[best])+nmTol) {
+			break
+		}
+
+		// 最悪点を除いた重心
+		for j := range centroid {
+			centroid[j] = 0
+		}
+		for _, i := range order[:n] {
+			for j := range centroid {
+				centroid[j] += simplex[i][j] / float64(n)
+			}
+		}
+
+		reflected := point(centroid, simplex[worst], -nmReflect)
+		fr := eval(reflected)
+		switch {
+		case fr < values[best]:
+			expanded := point(centroid, simplex[worst], -nmExpand)
+			if fe := eval(expanded); fe < fr {
+				simplex[worst], values[worst] = expanded, fe
+			} else {
+				simplex[worst], values[worst] = reflected, fr
+			}
+		case fr < values[second]:
+			simplex[worst], values[worst] = reflected, fr
+		default:
+			// 反射点と最悪点の良い方に向かって収縮し、改善しなければ最良点へ縮小
+			from, fromValue := simplex[worst], values[worst]
+			if fr < fromValue {
+				from, fromValue = reflected, fr
+			}
+			contracted := point(centroid, from, nmContract)
+			if fc := eval(contracted); fc < fromValue {
+				simplex[worst], values[worst] = contracted, fc
+				continue
+			}
+			for _, i := range order[1:] {
+				simplex[i] = point(simplex[best], simplex[i], nmShrink)
+				values[i] = eval(simplex[i])
+			}
+		}
+	}
+
+	best := 0
+	for i := range values {
+		if values[i] < values[best] {
+			best = i
+		}
+	}
+	return simplex[best], values[best]
+}
@@ -0,0 +1,156 @@
+package prediction
+
+import "math"
+
+// kpssCritical は KPSS 検定 (水準定常) の 5% 棄却限界値です
+const kpssCritical = 0.463
+
+// seasonalStrengthThreshold を超える季節性の強さで季節差分を取ります
+const seasonalStrengthThreshold = 0.64
+
+// difference は lag 時点前との差分系列を返します
+func difference(x []float64, lag int) []float64 {
+	if len(x) <= lag {
+		return nil
+	}
+	out := make([]float64, len(x)-lag)
+	for i := range out {
+		out[i] = x[i+lag] - x[i]
+	}
+	return out
+}
+
+// meanStd は平均と標本標準偏差を返します
+func meanStd(x []float64) (float64, float64) {
+	if len(x) == 0 {
+		return 0, 0
+	}
+	mean := 0.0
+	for _, v := range x {
+		mean += v
+	}
+	mean /= float64(len(x))
+	if len(x) < 2 {
+		return mean, 0
+	}
+	ss := 0.0
+	for _, v := range x {
+		ss += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(ss / float64(len(x)-1))
+}
+
+// kpssStatistic は水準定常性の KPSS 統計量を返します。長期分散は Bartlett
+// カーネルの Newey-West 推定量で求めます
+func kpssStatistic(x []float64) float64 {
+	n := len(x)
+	mean, _ := meanStd(x)
+	e := make([]float64, n)
+	for i, v := range x {
+		e[i] = v - mean
+	}
+
+	partial, sum := 0.0, 0.0
+	for _, v := range e {
+		partial += v
+		sum += partial * partial
+	}
+
+	lags := int(4 * math.Pow(float64(n)/100, 0.25))
+	variance := 0.0
+	for _, v := range e {
+		variance += v * v
+	}
+	for k := 1; k <= lags; k++ {
+		cov := 0.0
+		for t := k; t < n; t++ {
+			cov += e[t] * e[t-k]
+		}
+		variance += 2 * (1 - float64(k)/float64(lags+1)) * cov
+	}
+	variance /= float64(n)
+	if variance <= 0 {
+		return 0 // 定数系列は定常とみなす
+	}
+
+	return sum / (float64(n) * float64(n) * variance)
+}
+
+// isStationary は KPSS 検定で定常性が棄却されないかを返します
+func isStationary(x []float64) bool {
+	return len(x) < 3 || kpssStatistic(x) < kpssCritical
+}
+
+// seasonalStrength は古典的分解による季節性の強さ (0〜1) を返します。
+// 1 - Var(残差) / Var(季節成分 + 残差) で、強い季節性ほど 1 に近づきます
+func seasonalStrength(x []float64, period int) float64 {
+	if period < 2 || len(x) < 2*period {
+		return 0
+	}
+
+	trend := centeredMovingAverage(x, period)
+
+	// 周期内の位置ごとにトレンド除去後の平均を取り、季節成分とする
+	sums := make([]float64, period)
+	counts := make([]int, period)
+	for i, t := range trend {
+		if !math.IsNaN(t) {
+			sums[i%period] += x[i] - t
+			counts[i%period]++
+		}
+	}
+	seasonal := make([]float64, period)
+	total := 0.0
+	for i := range seasonal {
+		if counts[i] > 0 {
+			seasonal[i] = sums[i] / float64(counts[i])
+		}
+		total += seasonal[i]
+	}
+	for i := range seasonal {
+		seasonal[i] -= total / float64(period)
+	}
+
+	var remainder, detrended []float64
+	for i, t := range trend {
+		if math.IsNaN(t) {
+			continue
+		}
+		detrended = append(detrended, x[i]-t)
+		remainder = append(remainder, x[i]-t-seasonal[i%period])
+	}
+
+	_, sdRemainder := meanStd(remainder)
+	_, sdDetrended := meanStd(detrended)
+	if sdDetrended == 0 {
+		return 0
+	}
+	return math.Max(0, 1-(sdRemainder*sdRemainder)/(sdDetrended*sdDetrended))
+}
+
+// centeredMovingAverage は周期 period の中心化移動平均を返します。偶数周期では
+// 2×period 移動平均を使います。端の定義できない点は NaN です
+func centeredMovingAverage(x []float64, period int) []float64 {
+	out := make([]float64, len(x))
+	half := period / 2
+	for i := range x {
+		if i < half || i+half >= len(x) {
+			out[i] = math.NaN()
+			continue
+		}
+		sum := 0.0
+		if period%2 == 1 {
+			for j := i - half; j <= i+half; j++ {
+				sum += x[j]
+			}
+			out[i] = sum / float64(period)
+			continue
+		}
+		for j := i - half + 1; j < i+half; j++ {
+			sum += x[j]
+		}
+		sum += (x[i-half] + x[i+half]) / 2
+		out[i] = sum / float64(period)
+	}
+	return out
+}
@@ -4,24 +4,41 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 	"time"
 
+	"github.com/trendscout/backend/internal/deletion"
 	"github.com/trendscout/backend/internal/models"
 	"github.com/trendscout/backend/internal/scraper"
+	"github.com/trendscout/backend/internal/trend"
+)
+
+const (
+	// defaultCollectionInterval is used when COLLECTION_INTERVAL is not set
+	defaultCollectionInterval = 24 * time.Hour
+	// minCollectionInterval keeps sources from being polled too aggressively
+	minCollectionInterval = 15 * time.Minute
+	// purgeInterval is how often keywords past their deletion grace period are purged
+	purgeInterval = time.Hour
 )
 
 // Service handles scheduled operations
 type Service struct {
-	scraperService *scraper.Service
-	ticker         *time.Ticker
-	quit           chan struct{}
+	scraperService  *scraper.Service
+	deletionService *deletion.Service
+	trendService    *trend.Service
+	ticker          *time.Ticker
+	purgeTicker     *time.Ticker
+	quit            chan struct{}
 }
 
 // NewService creates a new scheduler service
 func NewService() *Service {
 	return &Service{
-		scraperService: scraper.NewService(),
-		quit:           make(chan struct{}),
+		scraperService:  scraper.NewService(),
+		deletionService: deletion.NewService(),
+		trendService:    trend.NewService(),
+		quit:            make(chan struct{}),
 	}
 }
 
@@ -32,8 +49,11 @@ func (s *Service) Start() {
 	// Run immediately on startup
 	go s.collectAllKeywordsData()
 	
-	// Schedule to run every 24 hours
-	s.ticker = time.NewTicker(24 * time.Hour)
+	// Schedule to run every collection interval
+	interval := collectionInterval()
+	log.Printf("Data collection interval: %s", interval)
+	s.ticker = time.NewTicker(interval)
+	s.purgeTicker = time.NewTicker(purgeInterval)
 	
 	go func() {
 		for {
@@ -41,6 +61,8 @@ func (s *Service) Start() {
 			case <-s.ticker.C:
 				log.Println("Running scheduled data collection...")
 				s.collectAllKeywordsData()
+			case <-s.purgeTicker.C:
+				s.purgeDeletedKeywords()
 			case <-s.quit:
 				log.Println("Stopping data collection scheduler...")
 				return
@@ -49,14 +71,52 @@ func (s *Service) Start() {
 	}()
 }
 
+// collectionInterval reads COLLECTION_INTERVAL (a Go duration such as "1h"
+// or "30m"). Sub-daily intervals fill the hourly metric series.
+func collectionInterval() time.Duration {
+	value := os.Getenv("COLLECTION_INTERVAL")
+	if value == "" {
+		return defaultCollectionInterval
+	}
+
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Invalid COLLECTION_INTERVAL %q, using %s: %v", value, defaultCollectionInterval, err)
+		return defaultCollectionInterval
+	}
+	if interval < minCollectionInterval {
+		log.Printf("COLLECTION_INTERVAL %s is below the minimum, using %s", interval, minCollectionInterval)
+		return minCollectionInterval
+	}
+	return interval
+}
+
 // Stop terminates the scheduler
 func (s *Service) Stop() {
 	if s.ticker != nil {
 		s.ticker.Stop()
 	}
+	if s.purgeTicker != nil {
+		s.purgeTicker.Stop()
+	}
 	close(s.quit)
 }
 
+// purgeDeletedKeywords purges keywords whose deletion grace period has ended
+func (s *Service) purgeDeletedKeywords() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	purged, err := s.deletionService.PurgeExpired(ctx)
+	if err != nil {
+		log.Printf("Failed to purge deleted keywords: %v", err)
+		return
+	}
+	if purged > 0 {
+		log.Printf("Purged %d deleted keywords", purged)
+	}
+}
+
 // collectAllKeywordsData collects data for all keywords in the system
 func (s *Service) collectAllKeywordsData() {
 	ctx := context.Background()
@@ -92,6 +152,49 @@ func (s *Service) collectAllKeywordsData() {
 	}
 	
 	log.Printf("Completed scheduled data collection")
+
+	s.scoreForecasts()
+	s.detectAnomalies()
+}
+
+// scoreForecasts scores issued forecasts against the days just collected
+func (s *Service) scoreForecasts() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	scored, err := s.trendService.ScoreForecasts(ctx)
+	if err != nil {
+		log.Printf("Failed to score forecasts: %v", err)
+	}
+	if scored > 0 {
+		log.Printf("Scored %d forecast days", scored)
+	}
+}
+
+// detectAnomalies detects anomalies in the metrics of all keywords, now that
+// the latest day has been collected
+func (s *Service) detectAnomalies() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	keywords, err := models.GetAllKeywords(ctx)
+	if err != nil {
+		log.Printf("Failed to get keywords for anomaly detection: %v", err)
+		return
+	}
+
+	detected := 0
+	for _, keyword := range keywords {
+		for _, metric := range trend.AnomalyMetrics {
+			anomalies, err := s.trendService.DetectAnomalies(ctx, keyword.ID, metric, trend.AnomalyLookbackDays)
+			if err != nil {
+				log.Printf("Failed to detect %s anomalies for keyword %s: %v", metric, keyword.Keyword, err)
+				continue
+			}
+			detected += len(anomalies)
+		}
+	}
+	log.Printf("Detected %d anomalies over %d keywords", detected, len(keywords))
 }
 
 // ForceCollectionForKeyword manually triggers data collection for a specific keyword
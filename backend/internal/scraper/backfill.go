@@ -0,0 +1,363 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/trendscout/backend/internal/models"
+	"github.com/trendscout/backend/internal/timezone"
+)
+
+const (
+	// maxArchivePages caps the number of ?paged=N pages read per feed
+	maxArchivePages = 30
+	// maxArchiveSitemaps caps the number of child sitemaps read per sitemap index
+	maxArchiveSitemaps = 30
+)
+
+// archiveFeeds are feeds that serve older entries via WordPress-style ?paged=N
+// pagination. Feeds that ignore the parameter stop after the first page.
+var archiveFeeds = []struct {
+	source  string
+	feedURL string
+}{
+	{"hypebeast.com", "https://hypebeast.com/fashion/feed"},
+	{"vogue.com", "https://www.vogue.com/feed"},
+	{"elle.com", "https://www.elle.com/rss/all.xml"},
+	{"wwd.com", "https://wwd.com/feed/"},
+	{"fashionista.com", "https://fashionista.com/feed"},
+	{"refinery29.com", "https://www.refinery29.com/en-us/rss.xml"},
+	{"popsugar.com", "https://www.popsugar.com/fashion/feed"},
+	{"harpersbazaar.com", "https://www.harpersbazaar.com/rss/all.xml/"},
+}
+
+// archiveSitemaps are sitemap indexes whose lastmod dates are walked back
+var archiveSitemaps = []struct {
+	source   string
+	indexURL string
+}{
+	{"vogue.com", "https://www.vogue.com/sitemap.xml"},
+}
+
+// BackfillReport summarises a historical collection run
+type BackfillReport struct {
+	KeywordID    int
+	Since        time.Time
+	Until        time.Time
+	Days         []models.BackfillCoverage
+	Reach        map[string]time.Time // earliest date each source's archive reached
+	ItemsStored  int
+	ItemsUndated int // items dropped because they had no publication date
+}
+
+// archiveWalk is the outcome of walking one source's archive
+type archiveWalk struct {
+	source  string
+	items   []ScrapedItem
+	reached time.Time // oldest entry seen, relevant to the keyword or not
+	undated int
+}
+
+// BackfillKeyword collects real historical content for a keyword back to
+// since. Items are bucketed by their actual publication date, and the per-day
+// coverage is recorded so days no source could reach are reported as
+// uncovered rather than as zero. No synthetic or adapted content is used.
+func (s *Service) BackfillKeyword(ctx context.Context, keywordID int, keyword string, since time.Time) (*BackfillReport, error) {
+	since = truncateToDay(since)
+	until := truncateToDay(time.Now())
+
+	var walks []archiveWalk
+	for _, feed := range archiveFeeds {
+		walk, err := s.walkFeedArchive(ctx, feed.source, feed.feedURL, keyword, since)
+		if err != nil {
+			log.Printf("Backfill: feed archive %s failed: %v", feed.feedURL, err)
+		}
+		if !walk.reached.IsZero() {
+			walks = append(walks, walk)
+		}
+	}
+	for _, sitemap := range archiveSitemaps {
+		walk, err := s.walkSitemapArchive(ctx, sitemap.source, sitemap.indexURL, keyword, since)
+		if err != nil {
+			log.Printf("Backfill: sitemap archive %s failed: %v", sitemap.indexURL, err)
+		}
+		if !walk.reached.IsZero() {
+			walks = append(walks, walk)
+		}
+	}
+
+	// Social media APIs only return recent posts; their dates are still real
+	if posts, err := s.scrapeSocialMedia(ctx, keyword, sourceFetches{}); err != nil {
+		log.Printf("Backfill: social media collection failed: %v", err)
+	} else {
+		walks = append(walks, groupWalksBySource(posts)...)
+	}
+
+	report := &BackfillReport{
+		KeywordID: keywordID,
+		Since:     since,
+		Until:     until,
+	}
+
+	var itemsByDate map[time.Time][]ScrapedItem
+	report.Reach, itemsByDate, report.ItemsUndated = bucketWalks(walks, since, until)
+
+	for day := since; !day.After(until); day = day.AddDate(0, 0, 1) {
+		dayItems := itemsByDate[day]
+		coverage := models.BackfillCoverage{
+			KeywordID: keywordID,
+			Date:      day,
+			ItemCount: len(dayItems),
+			Sources:   reachingSources(report.Reach, day),
+		}
+		coverage.Status = coverageStatus(coverage.ItemCount, coverage.Sources)
+		report.Days = append(report.Days, coverage)
+
+		// Uncovered days get no trend record: their volume is unknown, not zero
+		if coverage.Status == models.BackfillStatusUncovered {
+			continue
+		}
+
+		if err := s.storeTrendItems(ctx, keywordID, keyword, dayItems); err != nil {
+			log.Printf("Backfill: failed to store items for %s on %s: %v", keyword, day.Format("2006-01-02"), err)
+		}
+		if err := s.aggregateBucket(ctx, keywordID, day, coverage.Sources); err != nil {
+			log.Printf("Backfill: failed to store trend record for %s on %s: %v", keyword, day.Format("2006-01-02"), err)
+		}
+
+		if len(dayItems) > 0 {
+			if err := s.StoreContent(ctx, keywordID, dayItems); err != nil {
+				log.Printf("Backfill: failed to store content for %s on %s: %v", keyword, day.Format("2006-01-02"), err)
+			}
+			report.ItemsStored += len(dayItems)
+		}
+	}
+
+	if err := models.SaveBackfillCoverage(ctx, keywordID, report.Days); err != nil {
+		return report, fmt.Errorf("failed to save backfill coverage: %w", err)
+	}
+
+	return report, nil
+}
+
+// walkFeedArchive reads ?paged=N pages of a feed until entries older than
+// since are reached, the feed runs out, or it stops returning new entries
+func (s *Service) walkFeedArchive(ctx context.Context, source, feedURL, keyword string, since time.Time) (archiveWalk, error) {
+	walk := archiveWalk{source: source}
+	seenLinks := make(map[string]bool)
+
+	for page := 1; page <= maxArchivePages; page++ {
+		pageURL, err := pagedFeedURL(feedURL, page)
+		if err != nil {
+			return walk, err
+		}
+
+		feed, err := s.fetchRSSFeed(ctx, pageURL)
+		if err != nil {
+			if page == 1 {
+				return walk, err
+			}
+			break // Past the last archive page
+		}
+
+		newEntries := 0
+		for _, entry := range feed.Channel.Items {
+			if seenLinks[entry.Link] {
+				continue
+			}
+			seenLinks[entry.Link] = true
+			newEntries++
+
+			publishedAt, ok := parsePublishedDate(entry.PubDate)
+			if !ok {
+				walk.undated++
+				continue
+			}
+			if walk.reached.IsZero() || publishedAt.Before(walk.reached) {
+				walk.reached = publishedAt
+			}
+
+			if s.isRelevantContent(entry.Title, entry.Description, keyword) {
+				if item, ok := s.newFeedItem(entry, keyword, source, "fashion"); ok {
+					walk.items = append(walk.items, item)
+				}
+			}
+		}
+
+		// The feed ignores ?paged or has no more entries
+		if newEntries == 0 || (!walk.reached.IsZero() && walk.reached.Before(since)) {
+			break
+		}
+
+		time.Sleep(1 * time.Second) // Rate limiting
+	}
+
+	return walk, nil
+}
+
+// walkSitemapArchive reads the child sitemaps of an index whose lastmod is on
+// or after since. Sitemap lastmod is the last modification time, so it is
+// only an approximation of the publication date.
+func (s *Service) walkSitemapArchive(ctx context.Context, source, indexURL, keyword string, since time.Time) (archiveWalk, error) {
+	walk := archiveWalk{source: source}
+
+	var index SitemapIndex
+	if err := s.fetchSitemapXML(ctx, indexURL, &index); err != nil {
+		return walk, err
+	}
+
+	processed := 0
+	for _, child := range index.Sitemaps {
+		if processed >= maxArchiveSitemaps {
+			break
+		}
+
+		lastMod, ok := parsePublishedDate(child.LastMod)
+		if ok && lastMod.Before(since) {
+			continue // Only lists URLs modified before the backfill window
+		}
+		if !ok && !isArticleSitemap(child.Loc) {
+			continue
+		}
+
+		var urlSet URLSet
+		if err := s.fetchSitemapXML(ctx, child.Loc, &urlSet); err != nil {
+			log.Printf("Backfill: failed to read sitemap %s: %v", child.Loc, err)
+			continue
+		}
+		processed++
+
+		for _, entry := range urlSet.URLs {
+			item, ok := s.newSitemapItem(entry, keyword, source)
+			if !ok {
+				walk.undated++
+				continue
+			}
+			if walk.reached.IsZero() || item.PublishedAt.Before(walk.reached) {
+				walk.reached = item.PublishedAt
+			}
+			if s.isRelevantURL(entry.Loc, keyword) {
+				walk.items = append(walk.items, item)
+			}
+		}
+
+		time.Sleep(2 * time.Second) // Rate limiting
+	}
+
+	return walk, nil
+}
+
+// bucketWalks buckets the items of archive walks by publication day,
+// dropping duplicates and dates outside [since, until], and returns the day
+// each source reached back to. A source can have both a feed and a sitemap
+// archive, so the deeper one is kept; a walk without any dated entry reaches
+// no day.
+func bucketWalks(walks []archiveWalk, since, until time.Time) (reach map[string]time.Time, itemsByDate map[time.Time][]ScrapedItem, undated int) {
+	reach = make(map[string]time.Time)
+	itemsByDate = make(map[time.Time][]ScrapedItem)
+	seen := make(map[string]bool)
+	for _, walk := range walks {
+		undated += walk.undated
+		if !walk.reached.IsZero() {
+			reachedDay := truncateToDay(walk.reached)
+			if reached, ok := reach[walk.source]; !ok || reachedDay.Before(reached) {
+				reach[walk.source] = reachedDay
+			}
+		}
+		for _, item := range walk.items {
+			key := item.Source + "|" + item.URL + "|" + item.ExternalID
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			day := truncateToDay(item.PublishedAt)
+			if day.Before(since) || day.After(until) {
+				continue
+			}
+			itemsByDate[day] = append(itemsByDate[day], item)
+		}
+	}
+	return reach, itemsByDate, undated
+}
+
+// groupWalksBySource turns a mixed list of items into one walk per source,
+// each reaching back to its oldest item
+func groupWalksBySource(items []ScrapedItem) []archiveWalk {
+	bySource := make(map[string]*archiveWalk)
+	var order []string
+	for _, item := range items {
+		walk, ok := bySource[item.Source]
+		if !ok {
+			walk = &archiveWalk{source: item.Source}
+			bySource[item.Source] = walk
+			order = append(order, item.Source)
+		}
+		if item.PublishedAt.IsZero() {
+			walk.undated++
+			continue
+		}
+		if walk.reached.IsZero() || item.PublishedAt.Before(walk.reached) {
+			walk.reached = item.PublishedAt
+		}
+		walk.items = append(walk.items, item)
+	}
+
+	walks := make([]archiveWalk, 0, len(order))
+	for _, source := range order {
+		walks = append(walks, *bySource[source])
+	}
+	return walks
+}
+
+// reachingSources returns the sources whose archives reached back to day
+func reachingSources(reach map[string]time.Time, day time.Time) []string {
+	var sources []string
+	for source, reached := range reach {
+		if !reached.After(day) {
+			sources = append(sources, source)
+		}
+	}
+	sort.Strings(sources)
+	return sources
+}
+
+// coverageStatus classifies a backfilled day: collected when it has items,
+// empty when sources reached it without finding any, and uncovered when no
+// source reached back that far
+func coverageStatus(itemCount int, sources []string) string {
+	switch {
+	case itemCount > 0:
+		return models.BackfillStatusCollected
+	case len(sources) > 0:
+		return models.BackfillStatusEmpty
+	default:
+		return models.BackfillStatusUncovered
+	}
+}
+
+// pagedFeedURL returns the URL of the given archive page of a feed
+func pagedFeedURL(feedURL string, page int) (string, error) {
+	if page <= 1 {
+		return feedURL, nil
+	}
+
+	u, err := url.Parse(feedURL)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("paged", strconv.Itoa(page))
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// truncateToDay returns the start of t's day in the collection time zone
+func truncateToDay(t time.Time) time.Time {
+	return timezone.CollectionDay(t)
+}
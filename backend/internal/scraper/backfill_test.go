@@ -0,0 +1,139 @@
+package scraper
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/trendscout/backend/internal/models"
+)
+
+func day(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestPagedFeedURL(t *testing.T) {
+	tests := []struct {
+		feedURL string
+		page    int
+		want    string
+	}{
+		{"https://wwd.com/feed/", 1, "https://wwd.com/feed/"},
+		{"https://wwd.com/feed/", 0, "https://wwd.com/feed/"},
+		{"https://wwd.com/feed/", 3, "https://wwd.com/feed/?paged=3"},
+		// Existing query parameters are kept and a previous page replaced
+		{"https://example.com/rss?cat=fashion", 2, "https://example.com/rss?cat=fashion&paged=2"},
+		{"https://example.com/rss?paged=5", 2, "https://example.com/rss?paged=2"},
+	}
+
+	for _, tt := range tests {
+		got, err := pagedFeedURL(tt.feedURL, tt.page)
+		if err != nil || got != tt.want {
+			t.Errorf("pagedFeedURL(%q, %d) = %q, %v, want %q", tt.feedURL, tt.page, got, err, tt.want)
+		}
+	}
+	if _, err := pagedFeedURL("://bad", 2); err == nil {
+		t.Error("expected an error for an invalid URL")
+	}
+}
+
+func TestReachingSources(t *testing.T) {
+	reach := map[string]time.Time{
+		"vogue.com": day("2026-01-01"),
+		"wwd.com":   day("2026-01-10"),
+		"bluesky":   day("2026-01-20"),
+	}
+	tests := []struct {
+		day  string
+		want string
+	}{
+		{"2025-12-31", ""},
+		{"2026-01-01", "vogue.com"},
+		{"2026-01-10", "vogue.com,wwd.com"},
+		{"2026-01-25", "bluesky,vogue.com,wwd.com"},
+	}
+
+	for _, tt := range tests {
+		if got := strings.Join(reachingSources(reach, day(tt.day)), ","); got != tt.want {
+			t.Errorf("reachingSources on %s = %q, want %q", tt.day, got, tt.want)
+		}
+	}
+}
+
+func TestGroupWalksBySource(t *testing.T) {
+	items := []ScrapedItem{
+		{Source: "mastodon", URL: "a", PublishedAt: day("2026-01-05")},
+		{Source: "bluesky", URL: "b", PublishedAt: day("2026-01-07")},
+		{Source: "mastodon", URL: "c", PublishedAt: day("2026-01-02")},
+		{Source: "mastodon", URL: "d"}, // undated
+	}
+
+	walks := groupWalksBySource(items)
+	if len(walks) != 2 || walks[0].source != "mastodon" || walks[1].source != "bluesky" {
+		t.Fatalf("walks = %+v, want mastodon then bluesky", walks)
+	}
+	mastodon := walks[0]
+	if len(mastodon.items) != 2 || mastodon.undated != 1 || !mastodon.reached.Equal(day("2026-01-02")) {
+		t.Errorf("mastodon walk = %+v, want 2 items, 1 undated, reaching 2026-01-02", mastodon)
+	}
+	if !walks[1].reached.Equal(day("2026-01-07")) {
+		t.Errorf("bluesky reached %v, want 2026-01-07", walks[1].reached)
+	}
+}
+
+func TestCoverageStatus(t *testing.T) {
+	tests := []struct {
+		items   int
+		sources []string
+		want    string
+	}{
+		{3, []string{"vogue.com"}, models.BackfillStatusCollected},
+		// Items always count, even from a source that is not in the reach
+		{1, nil, models.BackfillStatusCollected},
+		{0, []string{"vogue.com"}, models.BackfillStatusEmpty},
+		{0, nil, models.BackfillStatusUncovered},
+	}
+
+	for _, tt := range tests {
+		if got := coverageStatus(tt.items, tt.sources); got != tt.want {
+			t.Errorf("coverageStatus(%d, %v) = %s, want %s", tt.items, tt.sources, got, tt.want)
+		}
+	}
+}
+
+func TestBucketWalks(t *testing.T) {
+	walks := []archiveWalk{
+		{source: "vogue.com", reached: day("2026-01-05").Add(9 * time.Hour), items: []ScrapedItem{
+			{Source: "vogue.com", URL: "a", PublishedAt: day("2026-01-05").Add(9 * time.Hour)},
+			{Source: "vogue.com", URL: "b", PublishedAt: day("2026-01-03")}, // before since
+		}},
+		// The sitemap of the same source reaches further back and repeats an item
+		{source: "vogue.com", reached: day("2026-01-02"), undated: 2, items: []ScrapedItem{
+			{Source: "vogue.com", URL: "a", PublishedAt: day("2026-01-05").Add(9 * time.Hour)},
+			{Source: "vogue.com", URL: "c", PublishedAt: day("2026-01-06")},
+		}},
+		// Only undated posts: the source reaches no day
+		{source: "mastodon", undated: 3},
+	}
+
+	reach, itemsByDate, undated := bucketWalks(walks, day("2026-01-04"), day("2026-01-10"))
+	if len(reach) != 1 || !reach["vogue.com"].Equal(day("2026-01-02")) {
+		t.Errorf("reach = %v, want vogue.com from 2026-01-02 only", reach)
+	}
+	if undated != 5 {
+		t.Errorf("undated = %d, want 5", undated)
+	}
+	if len(itemsByDate) != 2 || len(itemsByDate[day("2026-01-05")]) != 1 || len(itemsByDate[day("2026-01-06")]) != 1 {
+		t.Errorf("items by date = %v, want one item on 01-05 and 01-06", itemsByDate)
+	}
+	if got := coverageStatus(0, reachingSources(reach, day("2026-01-04"))); got != models.BackfillStatusEmpty {
+		t.Errorf("day without items reached by vogue.com is %s, want empty", got)
+	}
+	if got := coverageStatus(0, reachingSources(reach, day("2026-01-01"))); got != models.BackfillStatusUncovered {
+		t.Errorf("day before every archive is %s, want uncovered", got)
+	}
+}
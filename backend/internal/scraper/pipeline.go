@@ -0,0 +1,296 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/trendscout/backend/internal/metrics"
+	"github.com/trendscout/backend/internal/models"
+	"github.com/trendscout/backend/internal/timezone"
+)
+
+// PipelineVersion identifies the rules that turn stored items into trend
+// records: relevance scoring, the sentiment lexicon, engagement weights and
+// day bucketing. Bump it whenever one of them changes, then run
+// cmd/reaggregate to rebuild history with the new rules.
+const PipelineVersion = 1
+
+// searchMatchRelevance is the minimum relevance of social posts, which the
+// platforms already matched against the keyword
+const searchMatchRelevance = 0.5
+
+// ReaggregateReport summarises a re-aggregation run
+type ReaggregateReport struct {
+	KeywordID       int
+	From            time.Time
+	To              time.Time
+	PipelineVersion int
+	ItemsRescored   int
+	ItemsRelevant   int
+	DaysRebuilt     []time.Time
+	DaysRemoved     []time.Time // buckets left behind by a change of collection time zone
+}
+
+// storeTrendItems scores items with the current pipeline and stores them as
+// item-level records. Synthetic items are not real observations and are not
+// stored, so they never count towards trend records.
+func (s *Service) storeTrendItems(ctx context.Context, keywordID int, keyword string, items []ScrapedItem) error {
+	var records []models.TrendItem
+	for _, item := range items {
+		if item.Provenance == ProvenanceSynthetic || item.PublishedAt.IsZero() {
+			continue
+		}
+		record := newTrendItem(keywordID, item)
+		s.scoreTrendItem(&record, keyword)
+		records = append(records, record)
+	}
+
+	return models.UpsertTrendItems(ctx, records)
+}
+
+// scoreTrendItem applies the current pipeline to a stored item: its bucket,
+// relevance, sentiment and engagement
+func (s *Service) scoreTrendItem(record *models.TrendItem, keyword string) {
+	item := scrapedItemFromTrendItem(*record)
+	record.BucketStart = timezone.CollectionDay(record.PublishedAt)
+	record.Relevance = s.itemRelevance(item, keyword)
+	record.Sentiment = s.itemSentiment(item)
+	record.Engagement = metrics.EngagementScore(item.Source, item.LikeCount, item.CommentCount, item.RepostCount)
+	record.PipelineVersion = PipelineVersion
+}
+
+// itemRelevance scores an item against the keyword using the rule that
+// selected it at collection time
+func (s *Service) itemRelevance(item ScrapedItem, keyword string) float64 {
+	switch {
+	case item.Kind == ItemKindPost:
+		return math.Max(s.relevanceScore(item.Title, item.Content, keyword), searchMatchRelevance)
+	case item.Provenance == ProvenanceSitemap:
+		// Sitemap entries have no body; only the URL can be matched
+		if strings.Contains(strings.ToLower(item.URL), strings.ToLower(keyword)) {
+			return 1.0
+		}
+		if s.isRelevantURL(item.URL, keyword) {
+			return 0.5
+		}
+		return 0
+	default:
+		return s.relevanceScore(item.Title, item.Content, keyword)
+	}
+}
+
+// aggregateBucket rebuilds a keyword's trend record for one day from its
+// stored items, together with its lineage (the items it counted), the
+// per-source breakdown and the item metrics. Sources that had items in the
+// bucket but none relevant, and sources listed in zeroSources, get explicit
+// zero rows.
+func (s *Service) aggregateBucket(ctx context.Context, keywordID int, day time.Time, zeroSources []string) error {
+	records, err := models.GetTrendItemsForBucket(ctx, keywordID, day)
+	if err != nil {
+		return fmt.Errorf("failed to load items: %w", err)
+	}
+
+	var items []ScrapedItem
+	var itemIDs []int64
+	var engagement, sentiment float64
+	for _, record := range records {
+		if !hasString(zeroSources, record.Source) {
+			zeroSources = append(zeroSources, record.Source)
+		}
+		if !countsTowardsTrend(record) {
+			continue
+		}
+		items = append(items, scrapedItemFromTrendItem(record))
+		itemIDs = append(itemIDs, record.ID)
+		engagement += record.Engagement
+		sentiment += record.Sentiment
+	}
+
+	if len(items) > 0 {
+		sentiment /= float64(len(items))
+	} else {
+		sentiment = 0.5 // neutral when there are no items
+	}
+
+	if _, err := models.CreateTrendRecord(ctx, keywordID, day, len(items), engagement, sentiment, PipelineVersion); err != nil {
+		return fmt.Errorf("failed to store trend record: %w", err)
+	}
+	if err := models.ReplaceTrendRecordItems(ctx, keywordID, day, itemIDs); err != nil {
+		return fmt.Errorf("failed to store trend record lineage: %w", err)
+	}
+	s.storeSourceBreakdown(ctx, keywordID, day, items, zeroSources)
+	s.storeItemMetrics(ctx, keywordID, day, items)
+	s.storeHourlyMetrics(ctx, keywordID, items)
+
+	return nil
+}
+
+// countsTowardsTrend reports whether a stored item is counted in its day's
+// trend record. Synthetic items are never stored, but are excluded here too so
+// the total always equals the sum of the per-source breakdown.
+func countsTowardsTrend(record models.TrendItem) bool {
+	return record.Relevance > 0 && record.Provenance != ProvenanceSynthetic
+}
+
+// Reaggregate rescores a keyword's stored items published between from and to
+// (whole days in the collection time zone) with the current pipeline and
+// rebuilds the trend records of every day they fall into. Days without stored
+// items are left untouched: their records predate item-level storage and
+// cannot be recomputed.
+func (s *Service) Reaggregate(ctx context.Context, keywordID int, from, to time.Time) (*ReaggregateReport, error) {
+	keyword, err := models.GetKeywordByID(ctx, keywordID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get keyword: %w", err)
+	}
+	if keyword == nil {
+		return nil, fmt.Errorf("keyword not found: %d", keywordID)
+	}
+
+	report := &ReaggregateReport{
+		KeywordID:       keywordID,
+		From:            timezone.CollectionDay(from),
+		To:              timezone.CollectionDay(to),
+		PipelineVersion: PipelineVersion,
+	}
+
+	items, err := models.GetTrendItems(ctx, keywordID, report.From, report.To.AddDate(0, 0, 1).Add(-time.Nanosecond))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load items: %w", err)
+	}
+
+	// Rescore every item, remembering both its old and its new bucket
+	oldBuckets := make([]time.Time, len(items))
+	newBuckets := make([]time.Time, len(items))
+	for i := range items {
+		oldBuckets[i] = items[i].BucketStart
+		s.scoreTrendItem(&items[i], keyword.Keyword)
+		newBuckets[i] = items[i].BucketStart
+		if items[i].Relevance > 0 {
+			report.ItemsRelevant++
+		}
+	}
+	report.ItemsRescored = len(items)
+
+	if err := models.UpdateTrendItemScores(ctx, items); err != nil {
+		return nil, fmt.Errorf("failed to store item scores: %w", err)
+	}
+
+	var remove []time.Time
+	report.DaysRebuilt, remove = bucketChanges(oldBuckets, newBuckets)
+	for _, bucket := range remove {
+		if err := models.DeleteTrendBucket(ctx, keywordID, bucket); err != nil {
+			return report, fmt.Errorf("failed to remove bucket %s: %w", bucket.Format(time.RFC3339), err)
+		}
+		report.DaysRemoved = append(report.DaysRemoved, bucket)
+	}
+
+	for _, day := range report.DaysRebuilt {
+		if err := s.aggregateBucket(ctx, keywordID, day, nil); err != nil {
+			return report, fmt.Errorf("failed to rebuild %s: %w", day.Format("2006-01-02"), err)
+		}
+	}
+
+	log.Printf("Reaggregated keyword %d with pipeline v%d: %d items, %d days", keywordID, PipelineVersion,
+		report.ItemsRescored, len(report.DaysRebuilt))
+	return report, nil
+}
+
+// bucketChanges returns the days to rebuild after items moved from
+// oldBuckets to newBuckets (one entry per item), oldest first: every new
+// bucket, and each old bucket that is still a day of the collection time zone,
+// which is rebuilt (possibly to zero). The other old buckets were cut in a
+// previous time zone and are returned as the buckets to remove.
+func bucketChanges(oldBuckets, newBuckets []time.Time) (rebuild, remove []time.Time) {
+	days := make(map[time.Time]bool)
+	for _, bucket := range newBuckets {
+		days[bucket] = true
+	}
+
+	stale := make(map[time.Time]bool)
+	for i, bucket := range oldBuckets {
+		if bucket.Equal(newBuckets[i]) || stale[bucket] {
+			continue
+		}
+		stale[bucket] = true
+		if timezone.CollectionDay(bucket).Equal(bucket) {
+			days[bucket] = true
+		} else {
+			remove = append(remove, bucket)
+		}
+	}
+
+	for day := range days {
+		rebuild = append(rebuild, day)
+	}
+	sortTimes(rebuild)
+	sortTimes(remove)
+	return rebuild, remove
+}
+
+// sortTimes sorts times in ascending order
+func sortTimes(times []time.Time) {
+	sort.Slice(times, func(i, j int) bool {
+		return times[i].Before(times[j])
+	})
+}
+
+// newTrendItem converts a scraped item into an unscored item-level record
+func newTrendItem(keywordID int, item ScrapedItem) models.TrendItem {
+	kind := item.Kind
+	if kind == "" {
+		kind = ItemKindArticle
+	}
+	return models.TrendItem{
+		KeywordID:    keywordID,
+		ItemKey:      models.TrendItemKey(item.Source, item.URL, item.ExternalID),
+		Kind:         kind,
+		Source:       item.Source,
+		URL:          item.URL,
+		ExternalID:   item.ExternalID,
+		Title:        item.Title,
+		Content:      item.Content,
+		ImageURL:     item.ImageURL,
+		Author:       item.Author,
+		Language:     item.Language,
+		PublishedAt:  item.PublishedAt,
+		LikeCount:    item.LikeCount,
+		CommentCount: item.CommentCount,
+		RepostCount:  item.RepostCount,
+		Provenance:   item.Provenance,
+	}
+}
+
+// scrapedItemFromTrendItem converts a stored item back into a scraped item
+func scrapedItemFromTrendItem(record models.TrendItem) ScrapedItem {
+	return ScrapedItem{
+		Kind:         record.Kind,
+		Source:       record.Source,
+		URL:          record.URL,
+		Title:        record.Title,
+		Content:      record.Content,
+		ImageURL:     record.ImageURL,
+		PublishedAt:  record.PublishedAt,
+		ExternalID:   record.ExternalID,
+		Author:       record.Author,
+		LikeCount:    record.LikeCount,
+		CommentCount: record.CommentCount,
+		RepostCount:  record.RepostCount,
+		Language:     record.Language,
+		Provenance:   record.Provenance,
+	}
+}
+
+// hasString reports whether list contains value
+func hasString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
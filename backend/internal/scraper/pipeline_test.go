@@ -0,0 +1,154 @@
+package scraper
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/trendscout/backend/internal/timezone"
+)
+
+func TestItemRelevance(t *testing.T) {
+	s := &Service{}
+	tests := []struct {
+		name string
+		item ScrapedItem
+		want float64
+	}{
+		{"article matching the keyword", ScrapedItem{Title: "The new Denim edit", Content: "Jeans all season"}, 1.0},
+		{"article with fashion terms only", ScrapedItem{Title: "Paris runway collection", Content: "Designers showed"}, 0.6},
+		{"unrelated article", ScrapedItem{Title: "Quarterly earnings", Content: "Revenue rose"}, 0},
+		{"post matching the keyword", ScrapedItem{Kind: ItemKindPost, Content: "my denim jacket"}, 1.0},
+		// Platforms matched posts against the keyword at search time
+		{"unrelated post", ScrapedItem{Kind: ItemKindPost, Content: "coffee time"}, searchMatchRelevance},
+		{"sitemap URL with the keyword", ScrapedItem{Provenance: ProvenanceSitemap, URL: "https://www.vogue.com/article/DENIM-guide"}, 1.0},
+		{"sitemap URL in a fashion path", ScrapedItem{Provenance: ProvenanceSitemap, URL: "https://www.vogue.com/fashion/spring-coats"}, 0.5},
+		{"unrelated sitemap URL", ScrapedItem{Provenance: ProvenanceSitemap, URL: "https://www.vogue.com/article/recipes"}, 0},
+		// Sitemap entries are matched on the URL only, never on the title
+		{"sitemap title ignored", ScrapedItem{Provenance: ProvenanceSitemap, Title: "Denim", URL: "https://www.vogue.com/article/recipes"}, 0},
+	}
+
+	for _, tt := range tests {
+		if got := s.itemRelevance(tt.item, "denim"); got != tt.want {
+			t.Errorf("%s: relevance = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNewTrendItem(t *testing.T) {
+	published := time.Date(2024, 3, 1, 14, 30, 0, 0, time.UTC)
+	item := ScrapedItem{
+		Source:      "vogue.com",
+		URL:         "https://www.vogue.com/article/denim",
+		Title:       "Denim",
+		PublishedAt: published,
+		Provenance:  ProvenanceFeed,
+	}
+
+	record := newTrendItem(3, item)
+	if record.KeywordID != 3 || record.Source != "vogue.com" || !record.PublishedAt.Equal(published) {
+		t.Errorf("record = %+v, want keyword 3 from vogue.com published %s", record, published)
+	}
+	// Items without a kind are articles
+	if record.Kind != ItemKindArticle {
+		t.Errorf("kind = %q, want %q", record.Kind, ItemKindArticle)
+	}
+	if want := "https://www.vogue.com/article/denim"; record.URL != want {
+		t.Errorf("url = %q, want %q", record.URL, want)
+	}
+	// The record is not scored yet
+	if record.Relevance != 0 || record.PipelineVersion != 0 || !record.BucketStart.IsZero() {
+		t.Errorf("record is scored: relevance %v, pipeline v%d, bucket %s",
+			record.Relevance, record.PipelineVersion, record.BucketStart)
+	}
+
+	// Posts keep their kind, and the key tells items of a source apart
+	post := newTrendItem(3, ScrapedItem{Kind: ItemKindPost, Source: "bluesky", ExternalID: "abc"})
+	other := newTrendItem(3, ScrapedItem{Kind: ItemKindPost, Source: "bluesky", ExternalID: "abd"})
+	if post.Kind != ItemKindPost {
+		t.Errorf("post kind = %q, want %q", post.Kind, ItemKindPost)
+	}
+	if post.ItemKey == "" || post.ItemKey == other.ItemKey {
+		t.Errorf("item keys %q and %q should be distinct and non-empty", post.ItemKey, other.ItemKey)
+	}
+}
+
+func TestScrapedItemRoundTrip(t *testing.T) {
+	items := []ScrapedItem{
+		{
+			Kind:        ItemKindArticle,
+			Source:      "wwd.com",
+			URL:         "https://wwd.com/fashion-news/denim",
+			Title:       "Denim returns",
+			Content:     "Wide legs are back",
+			ImageURL:    "https://wwd.com/image.jpg",
+			PublishedAt: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC),
+			Author:      "Editor",
+			Language:    "en",
+			Provenance:  ProvenanceFeed,
+		},
+		{
+			Kind:         ItemKindPost,
+			Source:       "bluesky",
+			URL:          "https://bsky.app/profile/a/post/1",
+			Content:      "denim on denim",
+			PublishedAt:  time.Date(2024, 3, 2, 18, 0, 0, 0, time.UTC),
+			ExternalID:   "at://a/1",
+			Author:       "a.bsky.social",
+			LikeCount:    10,
+			CommentCount: 2,
+			RepostCount:  1,
+			Language:     "en",
+			Provenance:   ProvenanceAdapted,
+		},
+	}
+
+	for _, item := range items {
+		got := scrapedItemFromTrendItem(newTrendItem(1, item))
+		if !reflect.DeepEqual(got, item) {
+			t.Errorf("round trip of %s item:\n got %+v\nwant %+v", item.Source, got, item)
+		}
+	}
+
+	// Tags are not stored
+	tagged := items[0]
+	tagged.Tags = []string{"fashion"}
+	if got := scrapedItemFromTrendItem(newTrendItem(1, tagged)); got.Tags != nil {
+		t.Errorf("tags = %v, want none", got.Tags)
+	}
+}
+
+func TestBucketChanges(t *testing.T) {
+	loc := timezone.Collection()
+	day := func(d int) time.Time {
+		return time.Date(2024, 3, d, 0, 0, 0, 0, loc)
+	}
+	// A bucket cut at midnight of another time zone
+	foreign := day(3).Add(9 * time.Hour)
+
+	oldBuckets := []time.Time{day(1), day(1), foreign, foreign, day(5)}
+	newBuckets := []time.Time{day(1), day(2), day(4), day(4), day(5)}
+
+	rebuild, remove := bucketChanges(oldBuckets, newBuckets)
+
+	// Day 1 is rebuilt without the item that moved to day 2, and the foreign
+	// bucket is removed once although two items left it
+	wantRebuild := []time.Time{day(1), day(2), day(4), day(5)}
+	if !reflect.DeepEqual(rebuild, wantRebuild) {
+		t.Errorf("rebuild = %v, want %v", rebuild, wantRebuild)
+	}
+	if wantRemove := []time.Time{foreign}; !reflect.DeepEqual(remove, wantRemove) {
+		t.Errorf("remove = %v, want %v", remove, wantRemove)
+	}
+
+	// An item moving to another collection day leaves its old day to rebuild
+	rebuild, remove = bucketChanges([]time.Time{day(6)}, []time.Time{day(7)})
+	if want := []time.Time{day(6), day(7)}; !reflect.DeepEqual(rebuild, want) || remove != nil {
+		t.Errorf("moved item: rebuild = %v, remove = %v, want %v and none", rebuild, remove, want)
+	}
+
+	// Nothing to do without items
+	if rebuild, remove := bucketChanges(nil, nil); rebuild != nil || remove != nil {
+		t.Errorf("no items: rebuild = %v, remove = %v, want none", rebuild, remove)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"math/rand"
 	"net/http"
 	"net/url"
@@ -13,23 +14,64 @@ import (
 	"strings"
 	"time"
 
+	"github.com/trendscout/backend/internal/collector"
+	"github.com/trendscout/backend/internal/metrics"
 	"github.com/trendscout/backend/internal/models"
+	"github.com/trendscout/backend/internal/timezone"
 )
 
 // Service provides scraping operations using RSS feeds and APIs
 type Service struct {
-	client *http.Client
+	client     *http.Client
+	connectors []collector.Connector
 }
 
+// Item kinds distinguish editorial content from social media posts
+const (
+	ItemKindArticle = "article"
+	ItemKindPost    = "post"
+)
+
+// Provenance records how an item was obtained, so generated stand-in content
+// can be told apart from content that was actually published
+const (
+	ProvenanceFeed      = "feed"      // RSS feed entry matching the keyword
+	ProvenanceSitemap   = "sitemap"   // sitemap URL without article body
+	ProvenanceAdapted   = "adapted"   // unrelated feed entry rewritten for the keyword
+	ProvenanceSynthetic = "synthetic" // generated when no real data was available
+	ProvenanceAPI       = "api"       // social media API
+)
+
 // ScrapedItem represents an item scraped from fashion websites
 type ScrapedItem struct {
-	Source      string    // website name
-	URL         string    // original URL
-	Title       string    // title or empty for social posts
-	Content     string    // post content/caption
-	ImageURL    string    // image URL if available
-	Tags        []string  // keywords or categories
-	PublishedAt time.Time // publication date
+	Kind         string    // ItemKindArticle (default) or ItemKindPost
+	Source       string    // website name or social platform
+	URL          string    // original URL
+	Title        string    // title or empty for social posts
+	Content      string    // post content/caption
+	ImageURL     string    // image URL if available
+	Tags         []string  // keywords or categories
+	PublishedAt  time.Time // publication date
+	ExternalID   string    // platform post ID for social posts
+	Author       string    // author or account name
+	LikeCount    int       // likes/favourites for social posts
+	CommentCount int       // comments/replies for social posts
+	RepostCount  int       // reposts/boosts for social posts
+	Language     string    // ISO 639-1 language code
+	Provenance   string    // how the item was obtained (Provenance* constants)
+}
+
+// sourceFetches records whether each source could be fetched during a scrape,
+// keyed by source name. A source with several feeds counts as fetched when
+// any of them succeeded; otherwise the last error is kept.
+type sourceFetches map[string]error
+
+// record notes the outcome of one fetch from source
+func (f sourceFetches) record(source string, err error) {
+	if prev, seen := f[source]; seen && prev == nil {
+		return
+	}
+	f[source] = err
 }
 
 // RSS Feed structures
@@ -76,7 +118,10 @@ func NewService() *Service {
 			DisableCompression:  false,
 		},
 	}
-	return &Service{client: client}
+	return &Service{
+		client:     client,
+		connectors: collector.DefaultConnectors(),
+	}
 }
 
 // generateFallbackData creates synthetic fashion data when RSS feeds fail
@@ -135,6 +180,7 @@ func (s *Service) generateFallbackData(keyword string) []ScrapedItem {
 			ImageURL:    fmt.Sprintf("https://picsum.photos/600/400?random=%d", rand.Intn(1000)),
 			Tags:        []string{keyword, "fashion", topic.category, "trend2025"},
 			PublishedAt: time.Now().AddDate(0, 0, -rand.Intn(7)), // Random date in last 7 days
+			Provenance:  ProvenanceSynthetic,
 		}
 		
 		items = append(items, item)
@@ -146,11 +192,12 @@ func (s *Service) generateFallbackData(keyword string) []ScrapedItem {
 // ScrapeKeyword collects fashion data using RSS feeds and APIs
 func (s *Service) ScrapeKeyword(ctx context.Context, keyword string) ([]ScrapedItem, error) {
 	var allItems []ScrapedItem
+	fetches := sourceFetches{}
 
 	log.Printf("Starting data collection for keyword: %s", keyword)
 
 	// 1. Hypebeast RSS フィード（複数カテゴリ）
-	hypebeastItems, err := s.scrapeHypebeastRSS(ctx, keyword)
+	hypebeastItems, err := s.scrapeHypebeastRSS(ctx, keyword, fetches)
 	if err != nil {
 		log.Printf("Hypebeast RSS scraping failed: %v", err)
 	} else {
@@ -159,7 +206,7 @@ func (s *Service) ScrapeKeyword(ctx context.Context, keyword string) ([]ScrapedI
 	}
 
 	// 2. Vogue サイトマップ/RSS
-	vogueItems, err := s.scrapeVogueContent(ctx, keyword)
+	vogueItems, err := s.scrapeVogueContent(ctx, keyword, fetches)
 	if err != nil {
 		log.Printf("Vogue content scraping failed: %v", err)
 	} else {
@@ -168,7 +215,7 @@ func (s *Service) ScrapeKeyword(ctx context.Context, keyword string) ([]ScrapedI
 	}
 
 	// 3. Elle RSS/API
-	elleItems, err := s.scrapeElleContent(ctx, keyword)
+	elleItems, err := s.scrapeElleContent(ctx, keyword, fetches)
 	if err != nil {
 		log.Printf("Elle content scraping failed: %v", err)
 	} else {
@@ -186,7 +233,7 @@ func (s *Service) ScrapeKeyword(ctx context.Context, keyword string) ([]ScrapedI
 	}
 
 	// 5. Alternative RSS feeds with better success rate
-	alternativeItems, err := s.scrapeAlternativeFeeds(ctx, keyword)
+	alternativeItems, err := s.scrapeAlternativeFeeds(ctx, keyword, fetches)
 	if err != nil {
 		log.Printf("Alternative feeds scraping failed: %v", err)
 	} else {
@@ -194,6 +241,15 @@ func (s *Service) ScrapeKeyword(ctx context.Context, keyword string) ([]ScrapedI
 		allItems = append(allItems, alternativeItems...)
 	}
 
+	// 6. SNS (Mastodon / Bluesky / Reddit)
+	socialItems, err := s.scrapeSocialMedia(ctx, keyword, fetches)
+	if err != nil {
+		log.Printf("Social media collection failed: %v", err)
+	} else {
+		log.Printf("Collected %d posts from social media", len(socialItems))
+		allItems = append(allItems, socialItems...)
+	}
+
 	// If no real data was collected, generate fallback data
 	if len(allItems) == 0 {
 		log.Printf("No data collected from RSS feeds, generating fallback data for keyword: %s", keyword)
@@ -206,7 +262,7 @@ func (s *Service) ScrapeKeyword(ctx context.Context, keyword string) ([]ScrapedI
 
 	// Store items in database
 	if len(allItems) > 0 {
-		if err := s.storeScrapedItems(ctx, keyword, allItems); err != nil {
+		if err := s.storeScrapedItems(ctx, keyword, allItems, fetches); err != nil {
 			return allItems, fmt.Errorf("failed to store scraped items: %w", err)
 		}
 		log.Printf("Successfully stored %d items in database", len(allItems))
@@ -215,8 +271,45 @@ func (s *Service) ScrapeKeyword(ctx context.Context, keyword string) ([]ScrapedI
 	return allItems, nil
 }
 
+// scrapeSocialMedia collects posts from the configured social media connectors
+func (s *Service) scrapeSocialMedia(ctx context.Context, keyword string, fetches sourceFetches) ([]ScrapedItem, error) {
+	posts, fetchErrs := collector.CollectFromConnectorsWithStatus(ctx, s.connectors, keyword)
+	failed := 0
+	for platform, err := range fetchErrs {
+		fetches.record(platform, err)
+		if err != nil {
+			failed++
+		}
+	}
+	if len(s.connectors) > 0 && failed == len(s.connectors) {
+		return nil, fmt.Errorf("all social connectors failed")
+	}
+
+	items := make([]ScrapedItem, 0, len(posts))
+	for _, post := range posts {
+		items = append(items, ScrapedItem{
+			Kind:         ItemKindPost,
+			Source:       post.Platform,
+			URL:          post.URL,
+			Content:      post.Caption,
+			ImageURL:     post.ImageURL,
+			Tags:         post.Tags,
+			PublishedAt:  post.PostDate,
+			ExternalID:   post.PostID,
+			Author:       post.Username,
+			LikeCount:    post.LikeCount,
+			CommentCount: post.CommentCount,
+			RepostCount:  post.RepostCount,
+			Language:     post.Language,
+			Provenance:   ProvenanceAPI,
+		})
+	}
+
+	return items, nil
+}
+
 // scrapeHypebeastRSS scrapes multiple Hypebeast RSS feeds
-func (s *Service) scrapeHypebeastRSS(ctx context.Context, keyword string) ([]ScrapedItem, error) {
+func (s *Service) scrapeHypebeastRSS(ctx context.Context, keyword string, fetches sourceFetches) ([]ScrapedItem, error) {
 	var allItems []ScrapedItem
 
 	// Hypebeast RSS feeds for different categories
@@ -231,6 +324,7 @@ func (s *Service) scrapeHypebeastRSS(ctx context.Context, keyword string) ([]Scr
 
 	for category, feedURL := range rssFeeds {
 		items, err := s.parseRSSFeed(ctx, feedURL, keyword, "hypebeast.com", category)
+		fetches.record("hypebeast.com", err)
 		if err != nil {
 			log.Printf("Failed to parse Hypebeast %s RSS: %v", category, err)
 			continue
@@ -245,7 +339,7 @@ func (s *Service) scrapeHypebeastRSS(ctx context.Context, keyword string) ([]Scr
 }
 
 // scrapeVogueContent scrapes Vogue content using sitemap
-func (s *Service) scrapeVogueContent(ctx context.Context, keyword string) ([]ScrapedItem, error) {
+func (s *Service) scrapeVogueContent(ctx context.Context, keyword string, fetches sourceFetches) ([]ScrapedItem, error) {
 	var allItems []ScrapedItem
 
 	// Try multiple Vogue RSS/sitemap approaches
@@ -257,6 +351,7 @@ func (s *Service) scrapeVogueContent(ctx context.Context, keyword string) ([]Scr
 
 	for _, feedURL := range vogueFeeds {
 		items, err := s.parseRSSFeed(ctx, feedURL, keyword, "vogue.com", "fashion")
+		fetches.record("vogue.com", err)
 		if err != nil {
 			log.Printf("Failed to parse Vogue RSS %s: %v", feedURL, err)
 			continue
@@ -268,7 +363,9 @@ func (s *Service) scrapeVogueContent(ctx context.Context, keyword string) ([]Scr
 
 	// If RSS failed, try sitemap index approach
 	if len(allItems) == 0 {
-		if items, err := s.scrapeSitemapIndex(ctx, "https://www.vogue.com/sitemap.xml", keyword, "vogue.com"); err == nil {
+		items, err := s.scrapeSitemapIndex(ctx, "https://www.vogue.com/sitemap.xml", keyword, "vogue.com")
+		fetches.record("vogue.com", err)
+		if err == nil {
 			allItems = append(allItems, items...)
 		}
 	}
@@ -277,7 +374,7 @@ func (s *Service) scrapeVogueContent(ctx context.Context, keyword string) ([]Scr
 }
 
 // scrapeElleContent scrapes Elle content
-func (s *Service) scrapeElleContent(ctx context.Context, keyword string) ([]ScrapedItem, error) {
+func (s *Service) scrapeElleContent(ctx context.Context, keyword string, fetches sourceFetches) ([]ScrapedItem, error) {
 	var allItems []ScrapedItem
 
 	// Try Elle RSS feeds with correct URLs
@@ -289,6 +386,7 @@ func (s *Service) scrapeElleContent(ctx context.Context, keyword string) ([]Scra
 
 	for _, feedURL := range elleFeeds {
 		items, err := s.parseRSSFeed(ctx, feedURL, keyword, "elle.com", "fashion")
+		fetches.record("elle.com", err)
 		if err != nil {
 			log.Printf("Failed to parse Elle RSS %s: %v", feedURL, err)
 			continue
@@ -301,47 +399,22 @@ func (s *Service) scrapeElleContent(ctx context.Context, keyword string) ([]Scra
 	return allItems, nil
 }
 
+// SitemapIndex lists the child sitemaps of a sitemap index file
+type SitemapIndex struct {
+	XMLName  xml.Name `xml:"sitemapindex"`
+	Sitemaps []struct {
+		Loc     string `xml:"loc"`
+		LastMod string `xml:"lastmod"`
+	} `xml:"sitemap"`
+}
+
 // scrapeSitemapIndex handles sitemap index files
 func (s *Service) scrapeSitemapIndex(ctx context.Context, sitemapURL, keyword, source string) ([]ScrapedItem, error) {
 	var allItems []ScrapedItem
 
-	req, err := http.NewRequestWithContext(ctx, "GET", sitemapURL, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	// Use realistic browser headers
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
-	req.Header.Set("Accept", "application/xml, text/xml, */*")
-	req.Header.Set("Accept-Language", "en-US,en;q=0.9,ja;q=0.8")
-	
-	resp, err := s.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch sitemap index: status %d", resp.StatusCode)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	// Try to parse as sitemap index
-	type SitemapIndex struct {
-		XMLName  xml.Name `xml:"sitemapindex"`
-		Sitemaps []struct {
-			Loc     string `xml:"loc"`
-			LastMod string `xml:"lastmod"`
-		} `xml:"sitemap"`
-	}
-
 	var sitemapIndex SitemapIndex
-	if err := xml.Unmarshal(body, &sitemapIndex); err != nil {
-		return nil, fmt.Errorf("failed to parse sitemap index XML: %w", err)
+	if err := s.fetchSitemapXML(ctx, sitemapURL, &sitemapIndex); err != nil {
+		return nil, fmt.Errorf("failed to fetch sitemap index: %w", err)
 	}
 
 	// Process first few sitemaps (to avoid too many requests)
@@ -350,12 +423,9 @@ func (s *Service) scrapeSitemapIndex(ctx context.Context, sitemapURL, keyword, s
 		if i >= maxSitemaps {
 			break
 		}
-		
+
 		// Only process recent sitemaps (fashion, news, etc)
-		if strings.Contains(strings.ToLower(sitemap.Loc), "fashion") ||
-		   strings.Contains(strings.ToLower(sitemap.Loc), "news") ||
-		   strings.Contains(strings.ToLower(sitemap.Loc), "article") {
-			
+		if isArticleSitemap(sitemap.Loc) {
 			items, err := s.scrapeSingleSitemap(ctx, sitemap.Loc, keyword, source)
 			if err != nil {
 				log.Printf("Failed to scrape sitemap %s: %v", sitemap.Loc, err)
@@ -369,64 +439,93 @@ func (s *Service) scrapeSitemapIndex(ctx context.Context, sitemapURL, keyword, s
 	return allItems, nil
 }
 
+// isArticleSitemap reports whether a child sitemap is likely to list articles
+func isArticleSitemap(loc string) bool {
+	loc = strings.ToLower(loc)
+	return strings.Contains(loc, "fashion") ||
+		strings.Contains(loc, "news") ||
+		strings.Contains(loc, "article")
+}
+
 // scrapeSingleSitemap processes a single sitemap
 func (s *Service) scrapeSingleSitemap(ctx context.Context, sitemapURL, keyword, source string) ([]ScrapedItem, error) {
 	var allItems []ScrapedItem
 
+	var urlSet URLSet
+	if err := s.fetchSitemapXML(ctx, sitemapURL, &urlSet); err != nil {
+		return nil, err
+	}
+
+	// Filter URLs related to keyword and fashion (limit to avoid too many)
+	maxURLs := 5 // Reduced from 10 for better performance
+	for _, sitemapURL := range urlSet.URLs {
+		if len(allItems) >= maxURLs {
+			break
+		}
+
+		if s.isRelevantURL(sitemapURL.Loc, keyword) {
+			item, ok := s.newSitemapItem(sitemapURL, keyword, source)
+			if !ok {
+				continue // No lastmod: the publication day is unknown
+			}
+			allItems = append(allItems, item)
+		}
+	}
+
+	return allItems, nil
+}
+
+// fetchSitemapXML downloads a sitemap or sitemap index and decodes it into v
+func (s *Service) fetchSitemapXML(ctx context.Context, sitemapURL string, v interface{}) error {
 	req, err := http.NewRequestWithContext(ctx, "GET", sitemapURL, nil)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	// Use realistic browser headers
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
 	req.Header.Set("Accept", "application/xml, text/xml, */*")
 	req.Header.Set("Accept-Language", "en-US,en;q=0.9,ja;q=0.8")
-	
+
 	resp, err := s.client.Do(req)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch sitemap: status %d", resp.StatusCode)
+		return fmt.Errorf("failed to fetch sitemap: status %d", resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	var urlSet URLSet
-	if err := xml.Unmarshal(body, &urlSet); err != nil {
-		return nil, fmt.Errorf("failed to parse sitemap XML: %w", err)
+	if err := xml.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse sitemap XML: %w", err)
 	}
 
-	// Filter URLs related to keyword and fashion (limit to avoid too many)
-	maxURLs := 5 // Reduced from 10 for better performance
-	count := 0
-	for _, sitemapURL := range urlSet.URLs {
-		if count >= maxURLs {
-			break
-		}
-		
-		if s.isRelevantURL(sitemapURL.Loc, keyword) {
-			item := ScrapedItem{
-				Source:      source,
-				URL:         sitemapURL.Loc,
-				Title:       s.extractTitleFromURL(sitemapURL.Loc),
-				Content:     fmt.Sprintf("Fashion content related to %s from %s", keyword, source),
-				ImageURL:    fmt.Sprintf("https://picsum.photos/400/300?random=%d", rand.Intn(1000)),
-				Tags:        []string{keyword, "fashion", source},
-				PublishedAt: s.parseLastMod(sitemapURL.LastMod),
-			}
-			allItems = append(allItems, item)
-			count++
-		}
-	}
+	return nil
+}
 
-	return allItems, nil
+// newSitemapItem converts a sitemap URL into a scraped item. It reports false
+// when the URL has no usable lastmod date.
+func (s *Service) newSitemapItem(sitemapURL SitemapURL, keyword, source string) (ScrapedItem, bool) {
+	publishedAt, ok := parsePublishedDate(sitemapURL.LastMod)
+	if !ok {
+		return ScrapedItem{}, false
+	}
+
+	return ScrapedItem{
+		Source:      source,
+		URL:         sitemapURL.Loc,
+		Title:       s.extractTitleFromURL(sitemapURL.Loc),
+		Content:     fmt.Sprintf("Fashion content related to %s from %s", keyword, source),
+		Tags:        []string{keyword, "fashion", source},
+		PublishedAt: publishedAt,
+		Provenance:  ProvenanceSitemap,
+	}, true
 }
 
 // scrapeRunwayContent generates runway/fashion week related content
@@ -459,6 +558,7 @@ func (s *Service) scrapeRunwayContent(_ context.Context, keyword string) ([]Scra
 				ImageURL:    fmt.Sprintf("https://via.placeholder.com/600x400?text=%s+%s", url.QueryEscape(data.designer), url.QueryEscape(data.season)),
 				Tags:        []string{keyword, "runway", "fashion-week", strings.ToLower(data.designer), data.trend},
 				PublishedAt: time.Now().AddDate(0, 0, -rand.Intn(30)),
+				Provenance:  ProvenanceSynthetic,
 			}
 			allItems = append(allItems, item)
 		}
@@ -469,6 +569,60 @@ func (s *Service) scrapeRunwayContent(_ context.Context, keyword string) ([]Scra
 
 // parseRSSFeed parses an RSS feed and filters for keyword relevance
 func (s *Service) parseRSSFeed(ctx context.Context, feedURL, keyword, source, category string) ([]ScrapedItem, error) {
+	feed, err := s.fetchRSSFeed(ctx, feedURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []ScrapedItem
+	maxItems := 10 // Limit items per feed to avoid overwhelming
+	undated := 0
+
+	for _, item := range feed.Channel.Items {
+		if len(items) >= maxItems {
+			break
+		}
+
+		if s.isRelevantContent(item.Title, item.Description, keyword) {
+			scrapedItem, ok := s.newFeedItem(item, keyword, source, category)
+			if !ok {
+				undated++
+				continue
+			}
+			items = append(items, scrapedItem)
+		}
+	}
+
+	if undated > 0 {
+		log.Printf("Skipped %d undated items from %s", undated, feedURL)
+	}
+
+	// If no relevant items found but feed was accessible, create at least one item
+	if len(items) == 0 && len(feed.Channel.Items) > 0 && len(feed.Channel.Items[0].Title) > 0 {
+		// Take the first item and adapt it to the keyword
+		firstItem := feed.Channel.Items[0]
+		if publishedAt, ok := parsePublishedDate(firstItem.PubDate); ok {
+			adaptedItem := ScrapedItem{
+				Source:      source,
+				URL:         firstItem.Link,
+				Title:       fmt.Sprintf("%s Fashion Trend: %s", keyword, s.extractRelevantPart(firstItem.Title)),
+				Content:     fmt.Sprintf("Latest fashion insights related to %s from %s. %s", keyword, source, s.cleanDescription(firstItem.Description)),
+				ImageURL:    s.extractImageURL(firstItem.Description),
+				Tags:        []string{keyword, category, "fashion", "adapted"},
+				PublishedAt: publishedAt,
+				Author:      strings.TrimSpace(firstItem.Creator),
+				Language:    models.DetectLanguage(firstItem.Title + " " + firstItem.Description),
+				Provenance:  ProvenanceAdapted,
+			}
+			items = append(items, adaptedItem)
+		}
+	}
+
+	return items, nil
+}
+
+// fetchRSSFeed downloads and decodes an RSS feed
+func (s *Service) fetchRSSFeed(ctx context.Context, feedURL string) (*RSSFeed, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", feedURL, nil)
 	if err != nil {
 		return nil, err
@@ -481,7 +635,7 @@ func (s *Service) parseRSSFeed(ctx context.Context, feedURL, keyword, source, ca
 	req.Header.Set("Accept-Encoding", "gzip, deflate, br")
 	req.Header.Set("Cache-Control", "no-cache")
 	req.Header.Set("Pragma", "no-cache")
-	
+
 	resp, err := s.client.Do(req)
 	if err != nil {
 		return nil, err
@@ -502,53 +656,36 @@ func (s *Service) parseRSSFeed(ctx context.Context, feedURL, keyword, source, ca
 		return nil, fmt.Errorf("failed to parse RSS XML: %w", err)
 	}
 
-	var items []ScrapedItem
-	maxItems := 10 // Limit items per feed to avoid overwhelming
-	count := 0
-	
-	for _, item := range feed.Channel.Items {
-		if count >= maxItems {
-			break
-		}
-		
-		if s.isRelevantContent(item.Title, item.Description, keyword) {
-			scrapedItem := ScrapedItem{
-				Source:      source,
-				URL:         item.Link,
-				Title:       item.Title,
-				Content:     s.cleanDescription(item.Description),
-				ImageURL:    s.extractImageURL(item.Description),
-				Tags:        []string{keyword, category, "fashion"},
-				PublishedAt: s.parseRSSDate(item.PubDate),
-			}
-			
-			// Add category if available
-			if item.Category != "" {
-				scrapedItem.Tags = append(scrapedItem.Tags, strings.ToLower(item.Category))
-			}
-			
-			items = append(items, scrapedItem)
-			count++
-		}
+	return &feed, nil
+}
+
+// newFeedItem converts an RSS entry into a scraped item. It reports false
+// when the entry has no usable publication date.
+func (s *Service) newFeedItem(item Item, keyword, source, category string) (ScrapedItem, bool) {
+	publishedAt, ok := parsePublishedDate(item.PubDate)
+	if !ok {
+		return ScrapedItem{}, false
 	}
 
-	// If no relevant items found but feed was accessible, create at least one item
-	if len(items) == 0 && len(feed.Channel.Items) > 0 && len(feed.Channel.Items[0].Title) > 0 {
-		// Take the first item and adapt it to the keyword
-		firstItem := feed.Channel.Items[0]
-		adaptedItem := ScrapedItem{
-			Source:      source,
-			URL:         firstItem.Link,
-			Title:       fmt.Sprintf("%s Fashion Trend: %s", keyword, s.extractRelevantPart(firstItem.Title)),
-			Content:     fmt.Sprintf("Latest fashion insights related to %s from %s. %s", keyword, source, s.cleanDescription(firstItem.Description)),
-			ImageURL:    s.extractImageURL(firstItem.Description),
-			Tags:        []string{keyword, category, "fashion", "adapted"},
-			PublishedAt: s.parseRSSDate(firstItem.PubDate),
-		}
-		items = append(items, adaptedItem)
+	scrapedItem := ScrapedItem{
+		Source:      source,
+		URL:         item.Link,
+		Title:       item.Title,
+		Content:     s.cleanDescription(item.Description),
+		ImageURL:    s.extractImageURL(item.Description),
+		Tags:        []string{keyword, category, "fashion"},
+		PublishedAt: publishedAt,
+		Author:      strings.TrimSpace(item.Creator),
+		Language:    models.DetectLanguage(item.Title + " " + item.Description),
+		Provenance:  ProvenanceFeed,
 	}
 
-	return items, nil
+	// Add category if available
+	if item.Category != "" {
+		scrapedItem.Tags = append(scrapedItem.Tags, strings.ToLower(item.Category))
+	}
+
+	return scrapedItem, true
 }
 
 // extractRelevantPart extracts fashion-related words from title
@@ -574,25 +711,42 @@ func (s *Service) extractRelevantPart(title string) string {
 
 // isRelevantContent checks if content is relevant to the keyword
 func (s *Service) isRelevantContent(title, description, keyword string) bool {
+	return s.relevanceScore(title, description, keyword) > 0
+}
+
+// relevanceScore rates how strongly content relates to the keyword, from 0
+// (not relevant) to 1 (the keyword itself appears). It takes the best of the
+// matching rules below.
+func (s *Service) relevanceScore(title, description, keyword string) float64 {
 	content := strings.ToLower(title + " " + description)
 	keywordLower := strings.ToLower(keyword)
-	
+
 	// Direct keyword match
 	if strings.Contains(content, keywordLower) {
-		return true
+		return 1.0
 	}
-	
-	// Fashion-related terms for broader matching
-	fashionTerms := []string{
-		"fashion", "style", "trend", "outfit", "clothing", "apparel", "design", 
-		"runway", "collection", "designer", "model", "beauty", "makeup", "hair",
-		"accessories", "jewelry", "shoes", "bag", "dress", "shirt", "pants",
-		"jacket", "coat", "skirt", "blazer", "sweater", "casual", "formal",
-		"streetwear", "luxury", "brand", "shopping", "wear", "look", "chic",
-		"elegant", "stylish", "trendy", "fashionable", "季節", "トレンド", "ファッション",
-		"スタイル", "ブランド", "デザイン", "コーデ", "おしゃれ", "流行", "春", "夏", "秋", "冬",
+
+	score := 0.0
+
+	// For Japanese keywords, also check romanized versions
+	if s.containsJapanese(keyword) {
+		romanized := s.toRomanized(keyword)
+		if romanized != keyword && strings.Contains(content, strings.ToLower(romanized)) {
+			score = math.Max(score, 0.9)
+		}
 	}
-	
+
+	// Check for partial keyword matches (for compound keywords)
+	keywordParts := strings.Fields(keywordLower)
+	if len(keywordParts) > 1 {
+		for _, part := range keywordParts {
+			if len(part) > 3 && strings.Contains(content, part) {
+				score = math.Max(score, 0.8)
+				break
+			}
+		}
+	}
+
 	// Count fashion term matches
 	fashionMatches := 0
 	for _, term := range fashionTerms {
@@ -600,41 +754,35 @@ func (s *Service) isRelevantContent(title, description, keyword string) bool {
 			fashionMatches++
 		}
 	}
-	
+
 	// If we have multiple fashion terms, it's likely relevant
 	if fashionMatches >= 2 {
-		return true
+		score = math.Max(score, 0.6)
 	}
-	
+
 	// If keyword is fashion-related, match broader content
 	for _, term := range fashionTerms {
 		if strings.Contains(keywordLower, term) {
 			// For fashion keywords, be more lenient
 			if fashionMatches >= 1 || strings.Contains(content, "2025") || strings.Contains(content, "new") {
-				return true
+				score = math.Max(score, 0.4)
 			}
+			break
 		}
 	}
-	
-	// Check for partial keyword matches (for compound keywords)
-	keywordParts := strings.Fields(keywordLower)
-	if len(keywordParts) > 1 {
-		for _, part := range keywordParts {
-			if len(part) > 3 && strings.Contains(content, part) {
-				return true
-			}
-		}
-	}
-	
-	// For Japanese keywords, also check romanized versions
-	if s.containsJapanese(keyword) {
-		romanized := s.toRomanized(keyword)
-		if romanized != keyword && strings.Contains(content, strings.ToLower(romanized)) {
-			return true
-		}
-	}
-	
-	return false
+
+	return score
+}
+
+// fashionTerms are fashion-related terms for broader relevance matching
+var fashionTerms = []string{
+	"fashion", "style", "trend", "outfit", "clothing", "apparel", "design",
+	"runway", "collection", "designer", "model", "beauty", "makeup", "hair",
+	"accessories", "jewelry", "shoes", "bag", "dress", "shirt", "pants",
+	"jacket", "coat", "skirt", "blazer", "sweater", "casual", "formal",
+	"streetwear", "luxury", "brand", "shopping", "wear", "look", "chic",
+	"elegant", "stylish", "trendy", "fashionable", "季節", "トレンド", "ファッション",
+	"スタイル", "ブランド", "デザイン", "コーデ", "おしゃれ", "流行", "春", "夏", "秋", "冬",
 }
 
 // containsJapanese checks if string contains Japanese characters
@@ -755,52 +903,43 @@ func (s *Service) extractImageURL(description string) string {
 		}
 	}
 	
-	// Fallback: generate placeholder image URL
-	return "https://via.placeholder.com/400x300?text=Fashion+Image"
+	// No image in the feed item
+	return ""
 }
 
-// parseRSSDate parses RSS publication date
-func (s *Service) parseRSSDate(dateStr string) time.Time {
+// publishedDateFormats lists the date formats used by RSS pubDate and sitemap lastmod
+var publishedDateFormats = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"Mon, 02 Jan 2006 15:04 -0700",
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04-07:00",
+	"2006-01-02",
+}
+
+// parsePublishedDate parses an RSS pubDate or sitemap lastmod. It reports
+// false for missing or unrecognised dates; callers drop such items instead of
+// guessing a date, since a guessed date would be counted on the wrong day.
+func parsePublishedDate(dateStr string) (time.Time, bool) {
+	dateStr = strings.TrimSpace(dateStr)
 	if dateStr == "" {
-		return time.Now().AddDate(0, 0, -1) // Default to yesterday
+		return time.Time{}, false
 	}
-	
-	formats := []string{
-		time.RFC1123,
-		time.RFC1123Z,
-		"Mon, 02 Jan 2006 15:04:05 -0700",
-		"2006-01-02T15:04:05Z",
-		"2006-01-02T15:04:05-07:00",
-	}
-	
-	for _, format := range formats {
+
+	for _, format := range publishedDateFormats {
 		if t, err := time.Parse(format, dateStr); err == nil {
-			return t
+			return t, true
 		}
 	}
-	
-	return time.Now().AddDate(0, 0, -1)
-}
 
-// parseLastMod parses sitemap lastmod date
-func (s *Service) parseLastMod(dateStr string) time.Time {
-	if dateStr == "" {
-		return time.Now().AddDate(0, 0, -1)
-	}
-	
-	if t, err := time.Parse("2006-01-02T15:04:05Z", dateStr); err == nil {
-		return t
-	}
-	
-	if t, err := time.Parse("2006-01-02", dateStr); err == nil {
-		return t
-	}
-	
-	return time.Now().AddDate(0, 0, -1)
+	return time.Time{}, false
 }
 
 // storeScrapedItems stores the scraped items in MongoDB and updates trend records
-func (s *Service) storeScrapedItems(ctx context.Context, keyword string, items []ScrapedItem) error {
+func (s *Service) storeScrapedItems(ctx context.Context, keyword string, items []ScrapedItem, fetches sourceFetches) error {
 	// Get keyword ID from database
 	keywordObj, err := models.GetKeywordByName(ctx, keyword)
 	if err != nil {
@@ -813,96 +952,325 @@ func (s *Service) storeScrapedItems(ctx context.Context, keyword string, items [
 
 	keywordID := keywordObj.ID
 
-	// Group items by date
+	// Group items by their day in the collection time zone
 	itemsByDate := make(map[time.Time][]ScrapedItem)
 	for _, item := range items {
-		date := time.Date(item.PublishedAt.Year(), item.PublishedAt.Month(), item.PublishedAt.Day(), 0, 0, 0, 0, time.UTC)
+		date := timezone.CollectionDay(item.PublishedAt)
 		itemsByDate[date] = append(itemsByDate[date], item)
 	}
 
-	// Store items in MongoDB and update trend records
+	// Store the raw items, then rebuild each day's trend record from them
+	if err := s.storeTrendItems(ctx, keywordID, keyword, items); err != nil {
+		return fmt.Errorf("failed to store trend items: %w", err)
+	}
+
 	for date, dateItems := range itemsByDate {
-		// Calculate volume and sentiment
-		volume := len(dateItems)
-		sentiment := s.calculateSentiment(dateItems) // Simple sentiment calculation
+		if err := s.aggregateBucket(ctx, keywordID, date, nil); err != nil {
+			log.Printf("Failed to aggregate %s on %s: %v", keyword, date.Format("2006-01-02"), err)
+			continue
+		}
+
+		// Store items in MongoDB
+		if err := s.StoreContent(ctx, keywordID, dateItems); err != nil {
+			log.Printf("Failed to store content for %s on %s: %v", keyword, date.Format("2006-01-02"), err)
+		}
+	}
+
+	// Record today's fetch outcome for every source, so a source that worked
+	// but had nothing shows up as zero and a broken feed as a failure
+	today := timezone.CollectionDay(time.Now())
+	var fetched []string
+	for source, fetchErr := range fetches {
+		if fetchErr != nil {
+			if err := models.RecordSourceFetchFailure(ctx, keywordID, today, source, fetchErr.Error()); err != nil {
+				log.Printf("Failed to record fetch failure of %s: %v", source, err)
+			}
+			continue
+		}
+		if !hasSource(itemsByDate[today], source) {
+			fetched = append(fetched, source)
+		}
+	}
+	s.storeSourceBreakdown(ctx, keywordID, today, nil, fetched)
+
+	return nil
+}
+
+// storeSourceBreakdown stores the per-source volume of the items published on
+// date. Sources listed in zeroSources get an explicit zero row when they had no
+// items.
+func (s *Service) storeSourceBreakdown(ctx context.Context, keywordID int, date time.Time, items []ScrapedItem, zeroSources []string) {
+	for source, sourceItems := range sourceBreakdown(items, zeroSources) {
+		record := &models.TrendRecordSource{
+			KeywordID:  keywordID,
+			Date:       date,
+			Source:     source,
+			Volume:     len(sourceItems),
+			Engagement: CalculateEngagement(sourceItems),
+			Sentiment:  s.calculateSentiment(sourceItems),
+		}
+		if err := models.UpsertTrendRecordSource(ctx, record); err != nil {
+			log.Printf("Failed to store %s breakdown for %s: %v", source, date.Format("2006-01-02"), err)
+		}
+	}
+}
 
-		// Store trend record in PostgreSQL
-		_, err := models.CreateTrendRecord(ctx, keywordID, date, volume, sentiment)
+// sourceBreakdown groups items by source, with an empty group for each of
+// zeroSources. Synthetic items are not attributed to any source; like the day
+// total (see countsTowardsTrend) they are not counted at all.
+func sourceBreakdown(items []ScrapedItem, zeroSources []string) map[string][]ScrapedItem {
+	bySource := make(map[string][]ScrapedItem)
+	for _, source := range zeroSources {
+		bySource[source] = nil
+	}
+	for _, item := range items {
+		if item.Provenance == ProvenanceSynthetic {
+			continue
+		}
+		bySource[item.Source] = append(bySource[item.Source], item)
+	}
+	return bySource
+}
+
+// storeItemMetrics stores the per-kind item counts of a day in the metric series store
+func (s *Service) storeItemMetrics(ctx context.Context, keywordID int, date time.Time, items []ScrapedItem) {
+	var articles, posts, images int
+	for _, item := range items {
+		if item.Kind == ItemKindPost {
+			posts++
+		} else {
+			articles++
+		}
+		if s.realImageURL(item.ImageURL) != "" {
+			images++
+		}
+	}
+
+	points := []models.MetricPoint{
+		{KeywordID: keywordID, Metric: metrics.ArticleCount, BucketStart: date, Value: float64(articles)},
+		{KeywordID: keywordID, Metric: metrics.PostCount, BucketStart: date, Value: float64(posts)},
+		{KeywordID: keywordID, Metric: metrics.ImageCount, BucketStart: date, Value: float64(images)},
+	}
+	if err := models.UpsertMetricPoints(ctx, points); err != nil {
+		log.Printf("Failed to store item metrics for %s: %v", date.Format("2006-01-02"), err)
+	}
+}
+
+// storeHourlyMetrics stores volume, engagement and sentiment per publication
+// hour, in total and per source, so movement within a day can be followed.
+// Synthetic items have no real publication time and are left out.
+func (s *Service) storeHourlyMetrics(ctx context.Context, keywordID int, items []ScrapedItem) {
+	type bucketKey struct {
+		hour   time.Time
+		source string // empty for the keyword total
+	}
+	buckets := make(map[bucketKey][]ScrapedItem)
+	for _, item := range items {
+		if item.Provenance == ProvenanceSynthetic || item.PublishedAt.IsZero() {
+			continue
+		}
+		hour := item.PublishedAt.UTC().Truncate(time.Hour)
+		total := bucketKey{hour: hour}
+		bySource := bucketKey{hour: hour, source: item.Source}
+		buckets[total] = append(buckets[total], item)
+		buckets[bySource] = append(buckets[bySource], item)
+	}
+
+	var points []models.MetricPoint
+	for key, bucketItems := range buckets {
+		var dims map[string]string
+		if key.source != "" {
+			dims = map[string]string{"source": key.source}
+		}
+		points = append(points,
+			models.MetricPoint{KeywordID: keywordID, Metric: metrics.Volume, Granularity: metrics.GranularityHour,
+				BucketStart: key.hour, Value: float64(len(bucketItems)), Dimensions: dims},
+			models.MetricPoint{KeywordID: keywordID, Metric: metrics.Engagement, Granularity: metrics.GranularityHour,
+				BucketStart: key.hour, Value: CalculateEngagement(bucketItems), Dimensions: dims},
+			models.MetricPoint{KeywordID: keywordID, Metric: metrics.Sentiment, Granularity: metrics.GranularityHour,
+				BucketStart: key.hour, Value: s.calculateSentiment(bucketItems), Dimensions: dims},
+		)
+	}
+
+	if err := models.UpsertMetricPoints(ctx, points); err != nil {
+		log.Printf("Failed to store hourly metrics for keyword %d: %v", keywordID, err)
+	}
+}
+
+// hasSource reports whether any item came from source
+func hasSource(items []ScrapedItem, source string) bool {
+	for _, item := range items {
+		if item.Source == source {
+			return true
+		}
+	}
+	return false
+}
+
+// StoreContent persists scraped items in MongoDB: articles go to
+// blog_articles, posts to social_posts, and real (non-placeholder) images to
+// images. Writes are upserts, so storing the same item twice is harmless.
+func (s *Service) StoreContent(ctx context.Context, keywordID int, items []ScrapedItem) error {
+	var failed int
+	for _, item := range items {
+		var err error
+		if item.Kind == ItemKindPost {
+			err = models.UpsertSocialMediaPost(ctx, &models.SocialMediaPost{
+				KeywordID:    keywordID,
+				Platform:     item.Source,
+				PostID:       item.ExternalID,
+				Username:     item.Author,
+				Caption:      item.Content,
+				URL:          item.URL,
+				ImageURL:     item.ImageURL,
+				Tags:         item.Tags,
+				LikeCount:    item.LikeCount,
+				CommentCount: item.CommentCount,
+				RepostCount:  item.RepostCount,
+				Language:     item.languageOrDetect(),
+				PostDate:     item.PublishedAt,
+			})
+		} else {
+			err = models.UpsertBlogArticle(ctx, &models.BlogArticle{
+				KeywordID:   keywordID,
+				Source:      item.Source,
+				Title:       item.Title,
+				URL:         item.URL,
+				Author:      item.Author,
+				Content:     item.Content,
+				ImageURL:    s.realImageURL(item.ImageURL),
+				Tags:        item.Tags,
+				Language:    item.languageOrDetect(),
+				Provenance:  item.provenanceOrDefault(),
+				PublishDate: item.PublishedAt,
+			})
+		}
 		if err != nil {
-			// Record might already exist, continue with other dates
-			log.Printf("Failed to create trend record for %s on %s (possibly duplicate): %v", keyword, date.Format("2006-01-02"), err)
+			log.Printf("Failed to store %s from %s: %v", item.kindOrDefault(), item.Source, err)
+			failed++
 			continue
 		}
 
-		// Store items in MongoDB
-		for _, item := range dateItems {
+		if imageURL := s.realImageURL(item.ImageURL); imageURL != "" {
+			caption := item.Title
+			if caption == "" {
+				caption = item.Content
+			}
 			image := &models.Image{
 				KeywordID: keywordID,
-				ImageURL:  item.ImageURL,
-				Caption:   fmt.Sprintf("[%s] %s - %s", item.Source, item.Title, item.Content),
-				Tags:      item.Tags,
-				FetchedAt: item.PublishedAt,
+				ImageURL:  imageURL,
+				Caption:   caption,
+				Source:    item.Source,
+				PageURL:    item.URL,
+				Tags:       item.Tags,
+				Language:   item.languageOrDetect(),
+				Provenance: item.provenanceOrDefault(),
+				FetchedAt:  item.PublishedAt,
 			}
-
-			if err := models.CreateImage(ctx, image); err != nil {
+			if err := models.UpsertImage(ctx, image); err != nil {
 				log.Printf("Failed to store image: %v", err)
-				continue
 			}
 		}
 	}
 
+	if failed > 0 {
+		return fmt.Errorf("%d of %d items could not be stored", failed, len(items))
+	}
 	return nil
 }
 
+// realImageURL returns imageURL, or an empty string for placeholder images
+func (s *Service) realImageURL(imageURL string) string {
+	if models.IsPlaceholderImageURL(imageURL) {
+		return ""
+	}
+	return imageURL
+}
+
+// kindOrDefault returns the item kind, treating an empty kind as an article
+func (item ScrapedItem) kindOrDefault() string {
+	if item.Kind == "" {
+		return ItemKindArticle
+	}
+	return item.Kind
+}
+
+// languageOrDetect returns the item language, detecting it from the text when unknown
+func (item ScrapedItem) languageOrDetect() string {
+	if item.Language != "" {
+		return item.Language
+	}
+	return models.DetectLanguage(item.Title + " " + item.Content)
+}
+
+// provenanceOrDefault returns the item provenance, treating posts as API content
+// and any other unlabelled item as a feed entry
+func (item ScrapedItem) provenanceOrDefault() string {
+	switch {
+	case item.Provenance != "":
+		return item.Provenance
+	case item.Kind == ItemKindPost:
+		return ProvenanceAPI
+	default:
+		return ProvenanceFeed
+	}
+}
+
+// CalculateEngagement sums the engagement-weighted volume of the items
+func CalculateEngagement(items []ScrapedItem) float64 {
+	var total float64
+	for _, item := range items {
+		total += metrics.EngagementScore(item.Source, item.LikeCount, item.CommentCount, item.RepostCount)
+	}
+	return total
+}
+
 // calculateSentiment calculates basic sentiment from scraped items
 func (s *Service) calculateSentiment(items []ScrapedItem) float64 {
 	if len(items) == 0 {
 		return 0.5 // neutral
 	}
-	
-	positiveWords := []string{"amazing", "beautiful", "stunning", "gorgeous", "elegant", "chic", "trendy", "stylish", "love", "perfect", "fabulous"}
-	negativeWords := []string{"ugly", "terrible", "awful", "disappointing", "boring", "outdated", "hate", "worst"}
-	
+
 	var totalScore float64
-	var count int
-	
 	for _, item := range items {
-		content := strings.ToLower(item.Title + " " + item.Content)
-		var score float64 = 0.5 // neutral baseline
-		
-		for _, word := range positiveWords {
-			if strings.Contains(content, word) {
-				score += 0.1
-			}
-		}
-		
-		for _, word := range negativeWords {
-			if strings.Contains(content, word) {
-				score -= 0.1
-			}
-		}
-		
-		// Clamp score between 0 and 1
-		if score > 1.0 {
-			score = 1.0
-		}
-		if score < 0.0 {
-			score = 0.0
+		totalScore += s.itemSentiment(item)
+	}
+
+	return totalScore / float64(len(items))
+}
+
+// sentimentLexicon lists the words that move an item's sentiment up or down
+var sentimentLexicon = struct {
+	positive []string
+	negative []string
+}{
+	positive: []string{"amazing", "beautiful", "stunning", "gorgeous", "elegant", "chic", "trendy", "stylish", "love", "perfect", "fabulous"},
+	negative: []string{"ugly", "terrible", "awful", "disappointing", "boring", "outdated", "hate", "worst"},
+}
+
+// itemSentiment scores a single item from 0 (negative) to 1 (positive)
+func (s *Service) itemSentiment(item ScrapedItem) float64 {
+	content := strings.ToLower(item.Title + " " + item.Content)
+	score := 0.5 // neutral baseline
+
+	for _, word := range sentimentLexicon.positive {
+		if strings.Contains(content, word) {
+			score += 0.1
 		}
-		
-		totalScore += score
-		count++
 	}
-	
-	if count == 0 {
-		return 0.5
+
+	for _, word := range sentimentLexicon.negative {
+		if strings.Contains(content, word) {
+			score -= 0.1
+		}
 	}
-	
-	return totalScore / float64(count)
+
+	// Clamp score between 0 and 1
+	return math.Max(0, math.Min(1, score))
 }
 
 // scrapeAlternativeFeeds tries alternative RSS feeds that are more reliable
-func (s *Service) scrapeAlternativeFeeds(ctx context.Context, keyword string) ([]ScrapedItem, error) {
+func (s *Service) scrapeAlternativeFeeds(ctx context.Context, keyword string, fetches sourceFetches) ([]ScrapedItem, error) {
 	var allItems []ScrapedItem
 
 	// More reliable fashion RSS feeds
@@ -916,6 +1284,7 @@ func (s *Service) scrapeAlternativeFeeds(ctx context.Context, keyword string) ([
 
 	for source, feedURL := range alternativeFeeds {
 		items, err := s.parseRSSFeed(ctx, feedURL, keyword, source, "fashion")
+		fetches.record(source, err)
 		if err != nil {
 			log.Printf("Failed to parse %s RSS: %v", source, err)
 			continue
@@ -0,0 +1,51 @@
+package scraper
+
+import (
+	"testing"
+
+	"github.com/trendscout/backend/internal/models"
+)
+
+func TestSourceBreakdownAddsUpToTotal(t *testing.T) {
+	records := []models.TrendItem{
+		{Source: "vogue.com", Relevance: 1},
+		{Source: "vogue.com", Relevance: 0.4},
+		{Source: "bluesky", Relevance: 0.5},
+		{Source: "reddit", Relevance: 0}, // not relevant
+		{Source: "runway.fashion", Relevance: 1, Provenance: ProvenanceSynthetic},
+	}
+
+	// The items aggregateBucket counts in the day total
+	var items []ScrapedItem
+	for _, record := range records {
+		if countsTowardsTrend(record) {
+			items = append(items, scrapedItemFromTrendItem(record))
+		}
+	}
+
+	breakdown := sourceBreakdown(items, []string{"reddit", "wwd.com"})
+	sum := 0
+	for _, sourceItems := range breakdown {
+		sum += len(sourceItems)
+	}
+	if sum != len(items) || len(items) != 3 {
+		t.Errorf("sources add up to %d of a total of %d, want 3", sum, len(items))
+	}
+	if len(breakdown["vogue.com"]) != 2 || len(breakdown["bluesky"]) != 1 {
+		t.Errorf("breakdown = %v", breakdown)
+	}
+	for _, source := range []string{"reddit", "wwd.com"} {
+		if sourceItems, ok := breakdown[source]; !ok || len(sourceItems) != 0 {
+			t.Errorf("%s should have an explicit zero row", source)
+		}
+	}
+	if _, ok := breakdown["runway.fashion"]; ok {
+		t.Error("synthetic items should not be attributed to a source")
+	}
+
+	// Synthetic items passed directly are not attributed either
+	synthetic := []ScrapedItem{{Source: "runway.fashion", Provenance: ProvenanceSynthetic}}
+	if got := sourceBreakdown(synthetic, nil); len(got) != 0 {
+		t.Errorf("breakdown of synthetic items = %v, want none", got)
+	}
+}
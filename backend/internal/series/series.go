@@ -0,0 +1,210 @@
+// Package series resamples time series into regular buckets and fills gaps
+package series
+
+import (
+	"sort"
+	"time"
+)
+
+// Granularities of a regular series
+const (
+	Hour  = "hour"
+	Day   = "day"
+	Week  = "week" // weeks start on Monday
+	Month = "month"
+)
+
+// Aggregations combining the points that fall into one bucket
+const (
+	Sum = "sum"
+	Avg = "avg"
+)
+
+// Gap policies for buckets without data
+const (
+	FillNone        = "none"        // leave missing buckets out
+	FillZero        = "zero"        // missing buckets are zero
+	FillNull        = "null"        // missing buckets are present but invalid
+	FillForward     = "ffill"       // carry the last observed value forward
+	FillInterpolate = "interpolate" // linear interpolation between neighbours
+)
+
+// Point is a single value of a series. Valid is false for a missing value
+// (e.g. a gap under FillNull); Filled marks values produced by gap filling.
+type Point struct {
+	Time   time.Time
+	Value  float64
+	Valid  bool
+	Filled bool
+}
+
+// IsGranularity reports whether s is a supported granularity
+func IsGranularity(s string) bool {
+	return s == Hour || s == Day || s == Week || s == Month
+}
+
+// IsAggregation reports whether s is a supported aggregation
+func IsAggregation(s string) bool {
+	return s == Sum || s == Avg
+}
+
+// IsFillPolicy reports whether s is a supported gap policy
+func IsFillPolicy(s string) bool {
+	switch s {
+	case FillNone, FillZero, FillNull, FillForward, FillInterpolate:
+		return true
+	}
+	return false
+}
+
+// BucketStart returns the start of the bucket containing t, in t's location
+func BucketStart(t time.Time, granularity string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch granularity {
+	case Hour:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+	case Week:
+		offset := (int(day.Weekday()) + 6) % 7 // days since Monday
+		return day.AddDate(0, 0, -offset)
+	case Month:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	default:
+		return day
+	}
+}
+
+// NextBucket returns the start of the bucket following the one starting at t
+func NextBucket(t time.Time, granularity string) time.Time {
+	switch granularity {
+	case Hour:
+		return t.Add(time.Hour)
+	case Week:
+		return t.AddDate(0, 0, 7)
+	case Month:
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// Resample groups valid points into buckets of the given granularity and
+// combines each bucket with the aggregation. Only buckets with data are
+// returned, ordered by time.
+func Resample(points []Point, granularity, aggregation string) []Point {
+	sums := make(map[time.Time]float64)
+	counts := make(map[time.Time]int)
+	for _, p := range points {
+		if !p.Valid {
+			continue
+		}
+		bucket := BucketStart(p.Time, granularity)
+		sums[bucket] += p.Value
+		counts[bucket]++
+	}
+
+	result := make([]Point, 0, len(sums))
+	for bucket, sum := range sums {
+		value := sum
+		if aggregation == Avg {
+			value = sum / float64(counts[bucket])
+		}
+		result = append(result, Point{Time: bucket, Value: value, Valid: true})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Time.Before(result[j].Time)
+	})
+	return result
+}
+
+// FillGaps returns one point per bucket from the bucket containing start to
+// the bucket containing end, filling buckets without data according to
+// policy. points must already be bucketed (see Resample). A zero start or end
+// defaults to the first or last point. Under FillForward and FillInterpolate,
+// buckets before the first observation (and, for interpolation, after the
+// last) have no value to derive from and are left invalid.
+func FillGaps(points []Point, granularity string, start, end time.Time, policy string) []Point {
+	if len(points) == 0 && (start.IsZero() || end.IsZero()) {
+		return nil
+	}
+	if start.IsZero() {
+		start = points[0].Time
+	}
+	if end.IsZero() {
+		end = points[len(points)-1].Time
+	}
+
+	observed := make(map[time.Time]Point, len(points))
+	for _, p := range points {
+		if p.Valid {
+			observed[BucketStart(p.Time, granularity)] = p
+		}
+	}
+
+	var result []Point
+	last := BucketStart(end, granularity)
+	for bucket := BucketStart(start, granularity); !bucket.After(last); bucket = NextBucket(bucket, granularity) {
+		if p, ok := observed[bucket]; ok {
+			result = append(result, Point{Time: bucket, Value: p.Value, Valid: true})
+			continue
+		}
+		if policy == FillNone {
+			continue
+		}
+		gap := Point{Time: bucket, Filled: true}
+		if policy == FillZero {
+			gap.Valid = true
+		}
+		result = append(result, gap)
+	}
+
+	switch policy {
+	case FillForward:
+		fillForward(result)
+	case FillInterpolate:
+		interpolate(result)
+	}
+
+	return result
+}
+
+// fillForward replaces each filled gap with the previous valid value
+func fillForward(points []Point) {
+	for i := range points {
+		if points[i].Filled && i > 0 && points[i-1].Valid {
+			points[i].Value = points[i-1].Value
+			points[i].Valid = true
+		}
+	}
+}
+
+// interpolate replaces filled gaps between two observations with values on
+// the straight line between them, spaced by bucket index
+func interpolate(points []Point) {
+	prev := -1
+	for i := range points {
+		if points[i].Filled {
+			continue
+		}
+		if prev >= 0 && i-prev > 1 {
+			step := (points[i].Value - points[prev].Value) / float64(i-prev)
+			for j := prev + 1; j < i; j++ {
+				points[j].Value = points[prev].Value + step*float64(j-prev)
+				points[j].Valid = true
+			}
+		}
+		prev = i
+	}
+}
+
+// TrimInvalid drops invalid points at both ends of a series
+func TrimInvalid(points []Point) []Point {
+	start, end := 0, len(points)
+	for start < end && !points[start].Valid {
+		start++
+	}
+	for end > start && !points[end-1].Valid {
+		end--
+	}
+	return points[start:end]
+}
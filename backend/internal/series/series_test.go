@@ -0,0 +1,105 @@
+package series
+
+import (
+	"testing"
+	"time"
+)
+
+func day(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestBucketStart(t *testing.T) {
+	// 2024-05-15 is a Wednesday
+	if got := BucketStart(day("2024-05-15"), Week); !got.Equal(day("2024-05-13")) {
+		t.Errorf("week bucket = %v, want 2024-05-13", got)
+	}
+	// Sunday belongs to the week starting the previous Monday
+	if got := BucketStart(day("2024-05-19"), Week); !got.Equal(day("2024-05-13")) {
+		t.Errorf("week bucket for Sunday = %v, want 2024-05-13", got)
+	}
+	at := time.Date(2024, 5, 15, 13, 45, 10, 0, time.UTC)
+	if got := BucketStart(at, Hour); !got.Equal(time.Date(2024, 5, 15, 13, 0, 0, 0, time.UTC)) {
+		t.Errorf("hour bucket = %v, want 13:00", got)
+	}
+	if got := BucketStart(day("2024-05-15"), Month); !got.Equal(day("2024-05-01")) {
+		t.Errorf("month bucket = %v, want 2024-05-01", got)
+	}
+}
+
+func TestResample(t *testing.T) {
+	points := []Point{
+		{Time: day("2024-05-13"), Value: 2, Valid: true},
+		{Time: day("2024-05-14"), Value: 4, Valid: true},
+		{Time: day("2024-05-15"), Value: 100, Valid: false},
+		{Time: day("2024-05-20"), Value: 6, Valid: true},
+	}
+
+	sum := Resample(points, Week, Sum)
+	if len(sum) != 2 || sum[0].Value != 6 || sum[1].Value != 6 {
+		t.Fatalf("weekly sum = %+v", sum)
+	}
+
+	avg := Resample(points, Week, Avg)
+	if avg[0].Value != 3 {
+		t.Errorf("weekly avg = %v, want 3", avg[0].Value)
+	}
+}
+
+func TestResampleHourlyToDaily(t *testing.T) {
+	var points []Point
+	for h := 0; h < 48; h++ {
+		points = append(points, Point{Time: day("2024-05-01").Add(time.Duration(h) * time.Hour), Value: 1, Valid: true})
+	}
+	daily := Resample(points, Day, Sum)
+	if len(daily) != 2 || daily[0].Value != 24 || daily[1].Value != 24 {
+		t.Errorf("daily rollup = %+v", daily)
+	}
+}
+
+func TestFillGaps(t *testing.T) {
+	points := []Point{
+		{Time: day("2024-05-02"), Value: 2, Valid: true},
+		{Time: day("2024-05-05"), Value: 8, Valid: true},
+	}
+	start, end := day("2024-05-01"), day("2024-05-06")
+
+	tests := []struct {
+		policy string
+		want   []float64 // NaN-free; invalid points are checked separately
+		valid  []bool
+	}{
+		{FillZero, []float64{0, 2, 0, 0, 8, 0}, []bool{true, true, true, true, true, true}},
+		{FillNull, []float64{0, 2, 0, 0, 8, 0}, []bool{false, true, false, false, true, false}},
+		{FillForward, []float64{0, 2, 2, 2, 8, 8}, []bool{false, true, true, true, true, true}},
+		{FillInterpolate, []float64{0, 2, 4, 6, 8, 0}, []bool{false, true, true, true, true, false}},
+	}
+
+	for _, tt := range tests {
+		got := FillGaps(points, Day, start, end, tt.policy)
+		if len(got) != len(tt.want) {
+			t.Fatalf("%s: got %d points, want %d", tt.policy, len(got), len(tt.want))
+		}
+		for i := range got {
+			if got[i].Valid != tt.valid[i] || (got[i].Valid && got[i].Value != tt.want[i]) {
+				t.Errorf("%s: point %d = %+v, want value %v valid %v", tt.policy, i, got[i], tt.want[i], tt.valid[i])
+			}
+		}
+	}
+
+	if got := FillGaps(points, Day, start, end, FillNone); len(got) != 2 {
+		t.Errorf("none: got %d points, want 2", len(got))
+	}
+}
+
+func TestTrimInvalid(t *testing.T) {
+	points := []Point{{Valid: false}, {Value: 1, Valid: true}, {Valid: false}, {Value: 2, Valid: true}, {Valid: false}}
+	got := TrimInvalid(points)
+	if len(got) != 3 || got[0].Value != 1 || got[2].Value != 2 {
+		t.Errorf("TrimInvalid = %+v", got)
+	}
+}
@@ -0,0 +1,75 @@
+// Package timezone resolves the time zones used to bucket and present data
+package timezone
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultUser is the time zone of users who have not chosen one
+const DefaultUser = "Asia/Tokyo"
+
+var (
+	collectionOnce     sync.Once
+	collectionLocation *time.Location
+)
+
+// Collection returns the zone whose calendar days collected data is bucketed
+// into, read from COLLECTION_TIMEZONE (an IANA name). Defaults to UTC.
+func Collection() *time.Location {
+	collectionOnce.Do(func() {
+		collectionLocation = time.UTC
+		if name := os.Getenv("COLLECTION_TIMEZONE"); name != "" {
+			loc, err := Load(name)
+			if err != nil {
+				log.Printf("Invalid COLLECTION_TIMEZONE %q, using UTC: %v", name, err)
+				return
+			}
+			collectionLocation = loc
+		}
+	})
+	return collectionLocation
+}
+
+// Load returns the location for an IANA time zone name. Unlike
+// time.LoadLocation it rejects the empty name and "Local", whose meaning
+// depends on the server.
+func Load(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	return time.LoadLocation(name)
+}
+
+// LoadOrDefault returns the location for name, falling back to DefaultUser
+func LoadOrDefault(name string) *time.Location {
+	if loc, err := Load(name); err == nil {
+		return loc
+	}
+	loc, err := time.LoadLocation(DefaultUser)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// StartOfDay returns midnight of t's calendar day in loc. Where a DST
+// change skips midnight, the day starts when the clocks resume.
+func StartOfDay(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	if start.Day() != t.Day() {
+		// time.Date resolved the skipped midnight into the previous day
+		_, end := start.ZoneBounds()
+		start = end.In(loc)
+	}
+	return start
+}
+
+// CollectionDay returns the start of t's day in the collection time zone
+func CollectionDay(t time.Time) time.Time {
+	return StartOfDay(t, Collection())
+}
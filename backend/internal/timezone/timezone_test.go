@@ -0,0 +1,68 @@
+package timezone
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // zones do not depend on the machine running the tests
+)
+
+func TestLoad(t *testing.T) {
+	for _, name := range []string{"", "Local", "Mars/Olympus_Mons"} {
+		if loc, err := Load(name); err == nil {
+			t.Errorf("Load(%q) = %v, want an error", name, loc)
+		}
+	}
+	loc, err := Load("Europe/Paris")
+	if err != nil || loc.String() != "Europe/Paris" {
+		t.Errorf("Load(Europe/Paris) = %v, %v", loc, err)
+	}
+}
+
+func TestLoadOrDefault(t *testing.T) {
+	for _, name := range []string{"", "Local", "Not/A_Zone"} {
+		if loc := LoadOrDefault(name); loc.String() != DefaultUser {
+			t.Errorf("LoadOrDefault(%q) = %v, want %s", name, loc, DefaultUser)
+		}
+	}
+	if loc := LoadOrDefault("America/New_York"); loc.String() != "America/New_York" {
+		t.Errorf("LoadOrDefault(America/New_York) = %v", loc)
+	}
+}
+
+func TestStartOfDay(t *testing.T) {
+	tokyo, _ := Load("Asia/Tokyo")
+	newYork, _ := Load("America/New_York")
+	saoPaulo, _ := Load("America/Sao_Paulo")
+
+	tests := []struct {
+		name string
+		at   time.Time
+		loc  *time.Location
+		want time.Time // in UTC
+	}{
+		// 20:00 UTC is already the next day in Tokyo
+		{"ahead of UTC", time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC), tokyo, time.Date(2026, 5, 1, 15, 0, 0, 0, time.UTC)},
+		// 03:00 UTC is still the previous day in New York
+		{"behind UTC", time.Date(2026, 5, 2, 3, 0, 0, 0, time.UTC), newYork, time.Date(2026, 5, 1, 4, 0, 0, 0, time.UTC)},
+		// The day clocks spring forward starts at EST midnight and lasts 23 hours
+		{"spring forward", time.Date(2024, 3, 10, 18, 0, 0, 0, time.UTC), newYork, time.Date(2024, 3, 10, 5, 0, 0, 0, time.UTC)},
+		{"after spring forward", time.Date(2024, 3, 11, 4, 30, 0, 0, time.UTC), newYork, time.Date(2024, 3, 11, 4, 0, 0, 0, time.UTC)},
+		// The day clocks fall back starts at EDT midnight and lasts 25 hours
+		{"fall back", time.Date(2024, 11, 4, 4, 30, 0, 0, time.UTC), newYork, time.Date(2024, 11, 3, 4, 0, 0, 0, time.UTC)},
+		// São Paulo skipped midnight on 2018-11-04; the day starts at 01:00
+		{"missing midnight", time.Date(2018, 11, 4, 12, 0, 0, 0, time.UTC), saoPaulo, time.Date(2018, 11, 4, 3, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		got := StartOfDay(tt.at, tt.loc)
+		if !got.Equal(tt.want) {
+			t.Errorf("%s: StartOfDay = %v, want %v", tt.name, got.UTC(), tt.want)
+		}
+		if got.Location() != tt.loc {
+			t.Errorf("%s: StartOfDay is in %v, want %v", tt.name, got.Location(), tt.loc)
+		}
+		if got.After(tt.at) || tt.at.Sub(got) >= 25*time.Hour {
+			t.Errorf("%s: StartOfDay = %v is not the start of the day of %v", tt.name, got, tt.at)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package trend
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/trendscout/backend/internal/analysis"
+	"github.com/trendscout/backend/internal/metrics"
+	"github.com/trendscout/backend/internal/models"
+	"github.com/trendscout/backend/internal/prediction"
+	"github.com/trendscout/backend/internal/timezone"
+)
+
+// AnomalyLookbackDays is how many days before today anomaly detection covers
+const AnomalyLookbackDays = 90
+
+// anomalyItemLimit is how many items are linked to an anomaly as its explanation
+const anomalyItemLimit = 5
+
+// AnomalyMetrics lists the metrics anomalies are detected in
+var AnomalyMetrics = []string{metrics.Volume, metrics.Sentiment}
+
+// IsAnomalyMetric reports whether anomalies are detected in metric
+func IsAnomalyMetric(metric string) bool {
+	for _, m := range AnomalyMetrics {
+		if m == metric {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectAnomalies detects spikes and drops in a keyword's daily metric over
+// the given number of days before today, links each to the items that explain
+// it and stores them, replacing the anomalies detected there before. Today is
+// left out as it has not been fully collected.
+func (s *Service) DetectAnomalies(ctx context.Context, keywordID int, metric string, days int) ([]models.Anomaly, error) {
+	end := timezone.CollectionDay(time.Now())
+	start := end.AddDate(0, 0, -days)
+	records, err := models.GetMetricTrendRecords(ctx, keywordID, metric, start, end.Add(-time.Nanosecond), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trend data of keyword %d: %w", keywordID, err)
+	}
+
+	collected := make(map[string]bool, len(records))
+	var points []TrendPoint
+	for _, r := range records {
+		collected[dayKey(r.Date)] = true
+		points = append(points, TrendPoint{Date: r.Date, Volume: r.MetricValue(metric)})
+	}
+	daily := RegularizeDaily(points)
+
+	var anomalies []models.Anomaly
+	for _, a := range analysis.DetectAnomalies(volumesOf(daily), analysis.AnomalyOptions{Period: prediction.WeeklyPeriod}) {
+		day := daily[a.Index].Date
+		// Interpolated days were not collected and explain nothing
+		if !collected[dayKey(day)] {
+			continue
+		}
+
+		items, err := models.GetTrendPointItems(ctx, models.TrendPointQuery{
+			KeywordID: keywordID,
+			Start:     day,
+			End:       day.AddDate(0, 0, 1),
+			OrderBy:   anomalyItemOrder(metric, a.Kind),
+			Limit:     anomalyItemLimit,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get items of keyword %d on %s: %w", keywordID, dayKey(day), err)
+		}
+
+		anomaly := models.Anomaly{
+			KeywordID: keywordID,
+			Metric:    metric,
+			Date:      day,
+			Kind:      a.Kind,
+			Severity:  a.Severity,
+			Method:    a.Method,
+			Value:     a.Value,
+			Expected:  a.Expected,
+			Score:     a.Score,
+			Items:     items,
+		}
+		for _, item := range items {
+			anomaly.ItemIDs = append(anomaly.ItemIDs, item.ID)
+		}
+		anomalies = append(anomalies, anomaly)
+	}
+
+	if err := models.ReplaceAnomalies(ctx, keywordID, metric, start, end, anomalies); err != nil {
+		return nil, fmt.Errorf("failed to store anomalies of keyword %d: %w", keywordID, err)
+	}
+	return anomalies, nil
+}
+
+// anomalyItemOrder ranks the items of an anomalous day by how much they
+// explain it: by sentiment in its direction for sentiment, by engagement
+// otherwise
+func anomalyItemOrder(metric, kind string) string {
+	if metric != metrics.Sentiment {
+		return models.TrendItemOrderEngagement
+	}
+	if kind == analysis.AnomalyDrop {
+		return models.TrendItemOrderNegative
+	}
+	return models.TrendItemOrderPositive
+}
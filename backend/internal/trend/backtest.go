@@ -0,0 +1,217 @@
+package trend
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/trendscout/backend/internal/prediction"
+)
+
+// minBacktestTraining is the shortest training window of a backtest fold
+const minBacktestTraining = 14
+
+// BacktestOptions configures a rolling-origin backtest
+type BacktestOptions struct {
+	Horizon        int       // days forecast from each origin
+	Folds          int       // number of forecast origins, each Horizon days apart
+	Models         []string  // volume models to compare; all models when empty
+	IntervalLevels []float64 // prediction interval levels in (0, 1); prediction.DefaultIntervalLevels when empty
+}
+
+// BacktestCoverage is the share of actual values inside the prediction
+// intervals of one level
+type BacktestCoverage struct {
+	Level    float64
+	Coverage float64
+}
+
+// BacktestResult holds the accuracy of one model over all folds. Errors are
+// NaN when undefined, such as MAPE on a series of zeros or a model that failed.
+type BacktestResult struct {
+	Model     string
+	MAE       float64
+	RMSE      float64
+	MAPE      float64 // in percent, over the non-zero actual values
+	SMAPE     float64 // in percent
+	MASE      float64 // scaled by the in-sample error of the weekly seasonal naive forecast
+	Coverage  []BacktestCoverage
+	Points    int // forecast points evaluated
+	Fallbacks int // folds where the model could not be fitted and fell back to Holt-Winters
+	Err       string
+}
+
+// Backtest evaluates volume models with rolling-origin forecasts: the series
+// is cut at Folds origins Horizon days apart, ending Horizon days before the
+// last observation, and each model forecasts the Horizon days after every
+// origin from the data before it. Results are ordered by MASE, best first.
+func (e *PredictionEngine) Backtest(historical []TrendPoint, opts BacktestOptions) ([]BacktestResult, error) {
+	if opts.Horizon < 1 || opts.Folds < 1 {
+		return nil, fmt.Errorf("horizon and folds must be positive")
+	}
+	if len(opts.Models) == 0 {
+		opts.Models = VolumeModels()
+	}
+	for _, model := range opts.Models {
+		if !IsVolumeModel(model) {
+			return nil, fmt.Errorf("unknown volume model %q", model)
+		}
+	}
+	if len(opts.IntervalLevels) == 0 {
+		opts.IntervalLevels = prediction.DefaultIntervalLevels
+	}
+	if err := prediction.ValidateLevels(opts.IntervalLevels); err != nil {
+		return nil, err
+	}
+
+	series := RegularizeDaily(historical)
+	origins := backtestOrigins(len(series), opts.Folds, opts.Horizon)
+	if origins == nil {
+		return nil, fmt.Errorf("insufficient data for %d folds of %d days (%d days available, %d needed)",
+			opts.Folds, opts.Horizon, len(series), minBacktestTraining+opts.Folds*opts.Horizon)
+	}
+
+	results := make([]BacktestResult, len(opts.Models))
+	for i, model := range opts.Models {
+		results[i] = e.backtestModel(series, origins, model, opts)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return lessNaNLast(results[i].MASE, results[j].MASE)
+	})
+	return results, nil
+}
+
+// backtestOrigins returns the forecast origins of a rolling-origin backtest
+// of a series of n days: folds origins horizon days apart, the last one
+// horizon days before the end. It returns nil when the first origin would
+// leave less than minBacktestTraining days to train on.
+func backtestOrigins(n, folds, horizon int) []int {
+	first := n - folds*horizon
+	if first < minBacktestTraining {
+		return nil
+	}
+	origins := make([]int, folds)
+	for i := range origins {
+		origins[i] = first + i*horizon
+	}
+	return origins
+}
+
+// backtestModel runs every fold of a backtest for one model
+func (e *PredictionEngine) backtestModel(series []TrendPoint, origins []int, model string, opts BacktestOptions) BacktestResult {
+	result := BacktestResult{Model: model}
+	accuracy := newBacktestAccuracy(len(opts.IntervalLevels))
+
+	for _, origin := range origins {
+		training := series[:origin]
+		predictions, fit, err := e.PredictTrendWithOptions(training, opts.Horizon, PredictionOptions{
+			Model:          model,
+			IntervalLevels: opts.IntervalLevels,
+		})
+		if err != nil {
+			result.Err = err.Error()
+			accuracy = newBacktestAccuracy(len(opts.IntervalLevels))
+			break
+		}
+		if fit.Model != model {
+			result.Fallbacks++
+		}
+
+		scale := naiveScale(training)
+		for h, pred := range predictions {
+			accuracy.add(pred.Volume, series[origin+h].Volume, scale, pred.Intervals)
+		}
+	}
+
+	accuracy.summarize(&result, opts.IntervalLevels)
+	return result
+}
+
+// backtestAccuracy accumulates the errors of backtest forecasts
+type backtestAccuracy struct {
+	absSum, sqSum, apeSum, sapeSum, scaledSum float64
+	apeCount, scaledCount, points             int
+	covered                                   []int // per interval level
+}
+
+// newBacktestAccuracy returns an empty accumulator for the given number of
+// interval levels
+func newBacktestAccuracy(levels int) *backtestAccuracy {
+	return &backtestAccuracy{covered: make([]int, levels)}
+}
+
+// add records a forecast and its actual value. scale is the naive error
+// MASE divides by; forecasts with a zero scale are left out of MASE, and
+// zero actual values are left out of MAPE.
+func (a *backtestAccuracy) add(forecast, actual, scale float64, intervals []prediction.Interval) {
+	diff := math.Abs(forecast - actual)
+
+	a.absSum += diff
+	a.sqSum += diff * diff
+	if actual != 0 {
+		a.apeSum += diff / math.Abs(actual)
+		a.apeCount++
+	}
+	if denominator := math.Abs(forecast) + math.Abs(actual); denominator > 0 {
+		a.sapeSum += 2 * diff / denominator
+	}
+	if scale > 0 {
+		a.scaledSum += diff / scale
+		a.scaledCount++
+	}
+	for i, interval := range intervals {
+		if actual >= interval.Lower && actual <= interval.Upper {
+			a.covered[i]++
+		}
+	}
+	a.points++
+}
+
+// summarize sets the errors and coverage of result; errors without any
+// forecast to average over are NaN
+func (a *backtestAccuracy) summarize(result *BacktestResult, levels []float64) {
+	nan := math.NaN()
+	result.MAE, result.RMSE, result.MAPE, result.SMAPE, result.MASE = nan, nan, nan, nan, nan
+	result.Points = a.points
+	if a.points == 0 {
+		return
+	}
+
+	n := float64(a.points)
+	result.MAE = a.absSum / n
+	result.RMSE = math.Sqrt(a.sqSum / n)
+	result.SMAPE = 100 * a.sapeSum / n
+	if a.apeCount > 0 {
+		result.MAPE = 100 * a.apeSum / float64(a.apeCount)
+	}
+	if a.scaledCount > 0 {
+		result.MASE = a.scaledSum / float64(a.scaledCount)
+	}
+	for i, level := range levels {
+		result.Coverage = append(result.Coverage, BacktestCoverage{Level: level, Coverage: float64(a.covered[i]) / n})
+	}
+}
+
+// naiveScale is the in-sample mean absolute error of the weekly seasonal
+// naive forecast, or of the naive forecast for training data under two weeks
+func naiveScale(training []TrendPoint) float64 {
+	lag := prediction.WeeklyPeriod
+	if len(training) < 2*lag {
+		lag = 1
+	}
+
+	sum := 0.0
+	for t := lag; t < len(training); t++ {
+		sum += math.Abs(training[t].Volume - training[t-lag].Volume)
+	}
+	return sum / float64(len(training)-lag)
+}
+
+// lessNaNLast orders numbers ascending with NaN last
+func lessNaNLast(a, b float64) bool {
+	if math.IsNaN(b) {
+		return !math.IsNaN(a)
+	}
+	return a < b
+}
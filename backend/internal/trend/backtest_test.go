@@ -0,0 +1,148 @@
+package trend
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/trendscout/backend/internal/prediction"
+)
+
+// dailySeries returns one point a day from 2026-01-01 with the given volumes
+func dailySeries(volumes []float64) []TrendPoint {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	points := make([]TrendPoint, len(volumes))
+	for i, v := range volumes {
+		points[i] = TrendPoint{Date: start.AddDate(0, 0, i), Volume: v}
+	}
+	return points
+}
+
+// closeTo reports whether got is within 1e-9 of want, or both are NaN
+func closeTo(got, want float64) bool {
+	if math.IsNaN(want) {
+		return math.IsNaN(got)
+	}
+	return math.Abs(got-want) < 1e-9
+}
+
+func TestBacktestOrigins(t *testing.T) {
+	got := backtestOrigins(40, 3, 7)
+	want := []int{19, 26, 33}
+	if len(got) != len(want) {
+		t.Fatalf("origins = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("origins = %v, want %v", got, want)
+		}
+	}
+	// The first fold trains on exactly minBacktestTraining days
+	if got := backtestOrigins(minBacktestTraining+14, 2, 7); len(got) != 2 || got[0] != minBacktestTraining {
+		t.Errorf("origins = %v, want to start at %d", got, minBacktestTraining)
+	}
+	if got := backtestOrigins(minBacktestTraining+13, 2, 7); got != nil {
+		t.Errorf("origins = %v, want nil for too short a series", got)
+	}
+}
+
+func TestBacktestAccuracy(t *testing.T) {
+	// Forecasts of 10 against 12, 8, 0 and 10, with a naive error of 2
+	levels := []float64{0.8, 0.95}
+	intervals := []prediction.Interval{{Level: 0.8, Lower: 9, Upper: 11}, {Level: 0.95, Lower: 7, Upper: 13}}
+	accuracy := newBacktestAccuracy(len(levels))
+	for _, actual := range []float64{12, 8, 0, 10} {
+		accuracy.add(10, actual, 2, intervals)
+	}
+	var result BacktestResult
+	accuracy.summarize(&result, levels)
+
+	// Errors are 2, 2, 10 and 0
+	checks := []struct {
+		name      string
+		got, want float64
+	}{
+		{"MAE", result.MAE, 14.0 / 4},
+		{"RMSE", result.RMSE, math.Sqrt(108.0 / 4)},
+		// The zero actual value is left out of MAPE
+		{"MAPE", result.MAPE, 100 * (2.0/12 + 2.0/8 + 0) / 3},
+		{"sMAPE", result.SMAPE, 100 * (4.0/22 + 4.0/18 + 20.0/10 + 0) / 4},
+		{"MASE", result.MASE, (14.0 / 4) / 2},
+		{"80% coverage", result.Coverage[0].Coverage, 0.25},
+		{"95% coverage", result.Coverage[1].Coverage, 0.75},
+	}
+	for _, c := range checks {
+		if !closeTo(c.got, c.want) {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+	if result.Points != 4 {
+		t.Errorf("points = %d, want 4", result.Points)
+	}
+}
+
+func TestBacktestAccuracyZeros(t *testing.T) {
+	// A zero forecast of zero actuals with a flat training series: MAPE and
+	// MASE are undefined, sMAPE counts the exact forecasts as no error
+	accuracy := newBacktestAccuracy(0)
+	for i := 0; i < 3; i++ {
+		accuracy.add(0, 0, 0, nil)
+	}
+	var result BacktestResult
+	accuracy.summarize(&result, nil)
+	if !math.IsNaN(result.MAPE) || !math.IsNaN(result.MASE) {
+		t.Errorf("MAPE = %v, MASE = %v, want NaN", result.MAPE, result.MASE)
+	}
+	if result.MAE != 0 || result.SMAPE != 0 {
+		t.Errorf("MAE = %v, sMAPE = %v, want 0", result.MAE, result.SMAPE)
+	}
+
+	// Without any forecast every error is NaN
+	newBacktestAccuracy(0).summarize(&result, nil)
+	if !math.IsNaN(result.MAE) || !math.IsNaN(result.SMAPE) || result.Points != 0 {
+		t.Errorf("empty result = %+v, want NaN errors", result)
+	}
+}
+
+func TestNaiveScale(t *testing.T) {
+	// Under two weeks the naive error is of the previous day
+	if got := naiveScale(dailySeries([]float64{1, 2, 4, 7})); !closeTo(got, 2) {
+		t.Errorf("naive scale = %v, want 2", got)
+	}
+	// From two weeks it is of the same day a week before
+	volumes := make([]float64, 21)
+	for i := range volumes {
+		volumes[i] = float64(i%7) + 3*float64(i/7)
+	}
+	if got := naiveScale(dailySeries(volumes)); !closeTo(got, 3) {
+		t.Errorf("weekly naive scale = %v, want 3", got)
+	}
+}
+
+func TestBacktestNaiveModel(t *testing.T) {
+	// A line rising by 1 a day: the naive forecast from origin o is o-1, so
+	// the errors of a 7-day fold are 1 to 7, and the weekly naive error is 7
+	volumes := make([]float64, 28)
+	for i := range volumes {
+		volumes[i] = float64(i)
+	}
+	results, err := NewPredictionEngine().Backtest(dailySeries(volumes), BacktestOptions{
+		Horizon: 7,
+		Folds:   2,
+		Models:  []string{VolumeModelNaive},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := results[0]
+	if r.Err != "" || r.Fallbacks != 0 || r.Points != 14 {
+		t.Fatalf("result = %+v", r)
+	}
+	if !closeTo(r.MAE, 4) || !closeTo(r.MASE, 4.0/7) {
+		t.Errorf("MAE = %v, MASE = %v, want 4 and 4/7", r.MAE, r.MASE)
+	}
+
+	if _, err := NewPredictionEngine().Backtest(dailySeries(volumes), BacktestOptions{Horizon: 7, Folds: 3}); err == nil {
+		t.Error("expected an error for too few days to train on")
+	}
+}
@@ -0,0 +1,27 @@
+package trend
+
+import (
+	"time"
+
+	"github.com/trendscout/backend/internal/analysis"
+)
+
+// Lifecycle is the lifecycle stage of a trend series
+type Lifecycle struct {
+	analysis.Lifecycle
+	PeakDate      time.Time // zero without enough data
+	DaysSincePeak int
+	Days          int // days of the regularized series classified
+}
+
+// ClassifyLifecycle regularizes the series to days and classifies the
+// lifecycle stage of its volume
+func ClassifyLifecycle(historical []TrendPoint) *Lifecycle {
+	daily := RegularizeDaily(historical)
+	lifecycle := &Lifecycle{Lifecycle: *analysis.ClassifyLifecycle(volumesOf(daily)), Days: len(daily)}
+	if lifecycle.PeakIndex >= 0 {
+		lifecycle.PeakDate = daily[lifecycle.PeakIndex].Date
+		lifecycle.DaysSincePeak = len(daily) - 1 - lifecycle.PeakIndex
+	}
+	return lifecycle
+}
@@ -0,0 +1,206 @@
+package trend
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/trendscout/backend/internal/analysis"
+	"github.com/trendscout/backend/internal/prediction"
+)
+
+// How a peak estimate was made
+const (
+	PeakFitted   = "fitted"      // from a diffusion curve that fits well
+	PeakObserved = "observed"    // no curve fits well, but the trend has already peaked
+	PeakNone     = "no_estimate" // no curve fits well and the trend has not peaked yet
+)
+
+// Quality a diffusion curve must reach for its peak to be reported
+const (
+	// minPeakDays is the shortest series diffusion curves are fitted to
+	minPeakDays = 21
+	// minPeakFitR2 is the share of the variance of the daily volume the
+	// curve must explain; the cumulative volume is fitted closely by any curve
+	minPeakFitR2 = 0.5
+	// maxSaturationRatio bounds the saturation relative to the volume so far.
+	// Before the peak the saturation is not identified and curves grow it freely.
+	maxSaturationRatio = 10
+	// maxPeakLeadDays is how far after the data a fitted peak may lie
+	maxPeakLeadDays = 365
+	// peakBaselineQuantile is the quantile of the daily volume taken as the
+	// background volume every keyword has regardless of the trend
+	peakBaselineQuantile = 0.1
+)
+
+// DiffusionFit is a diffusion curve fitted to the cumulative volume above the baseline
+type DiffusionFit struct {
+	Model      string // prediction.DiffusionBass, DiffusionLogistic or DiffusionGompertz
+	Spec       string
+	Parameters map[string]float64
+	PeakDate   time.Time
+	PeakVolume float64 // daily volume at the peak, including the baseline
+	Saturation float64 // cumulative volume above the baseline the trend saturates at, from the first day
+	R2         float64 // of the cumulative volume
+	R2Daily    float64 // of the daily volume
+	RMSE       float64 // of the daily volume
+	AIC        float64
+	Acceptable bool
+	Reason     string // why the fit is not acceptable
+
+	curve *prediction.DiffusionModel
+}
+
+// PeakProjection is the volume a fitted curve projects for a day after the data
+type PeakProjection struct {
+	Date       time.Time
+	Volume     float64 // including the baseline
+	Cumulative float64 // cumulative volume above the baseline from the first day
+}
+
+// PeakEstimate is the estimated peak timing and size of a trend
+type PeakEstimate struct {
+	Status     string // PeakFitted, PeakObserved or PeakNone
+	Reason     string
+	PeakDate   time.Time // zero with PeakNone
+	PeakVolume float64
+	Saturation float64 // with PeakFitted
+	Observed   float64 // cumulative volume above the baseline so far
+	Baseline   float64 // daily background volume
+	Days       int     // days of the regularized series
+	Fit        *DiffusionFit
+	Candidates []DiffusionFit // by AIC, best first
+	Projection []PeakProjection
+}
+
+// IsDiffusionChoice reports whether model is ModelAuto or a diffusion curve
+func IsDiffusionChoice(model string) bool {
+	return model == ModelAuto || prediction.IsDiffusionKind(model)
+}
+
+// EstimatePeak fits diffusion curves to the cumulative volume above the
+// baseline and estimates when the trend peaks, how high and where its
+// cumulative volume saturates. With ModelAuto the acceptable curve with the
+// lowest AIC is used. When no curve fits well, a peak already passed is
+// reported as observed instead.
+func EstimatePeak(historical []TrendPoint, model string, horizon int) (*PeakEstimate, error) {
+	if model == "" {
+		model = ModelAuto
+	}
+	if !IsDiffusionChoice(model) {
+		return nil, fmt.Errorf("unknown diffusion model %q", model)
+	}
+
+	daily := RegularizeDaily(historical)
+	if len(daily) < minPeakDays {
+		return nil, fmt.Errorf("at least %d days of data are required, got %d", minPeakDays, len(daily))
+	}
+
+	volumes := volumesOf(daily)
+	sorted := append([]float64(nil), volumes...)
+	sort.Float64s(sorted)
+	estimate := &PeakEstimate{
+		Baseline: math.Max(sorted[int(peakBaselineQuantile*float64(len(sorted)-1))], 0),
+		Days:     len(daily),
+	}
+	excess := make([]float64, len(volumes))
+	for i, v := range volumes {
+		excess[i] = math.Max(v-estimate.Baseline, 0)
+		estimate.Observed += excess[i]
+	}
+
+	kinds := prediction.DiffusionKinds
+	if model != ModelAuto {
+		kinds = []string{model}
+	}
+	first, last := daily[0].Date, daily[len(daily)-1].Date
+	for _, kind := range kinds {
+		curve, err := prediction.FitDiffusion(excess, kind)
+		if err != nil {
+			continue
+		}
+		estimate.Candidates = append(estimate.Candidates, estimate.newDiffusionFit(curve, first))
+	}
+	sort.SliceStable(estimate.Candidates, func(i, j int) bool {
+		return estimate.Candidates[i].AIC < estimate.Candidates[j].AIC
+	})
+
+	for i := range estimate.Candidates {
+		fit := &estimate.Candidates[i]
+		if !fit.Acceptable {
+			continue
+		}
+		estimate.Status = PeakFitted
+		estimate.Fit = fit
+		estimate.PeakDate = fit.PeakDate
+		estimate.PeakVolume = fit.PeakVolume
+		estimate.Saturation = fit.Saturation
+		estimate.Reason = fmt.Sprintf("%s curve explains %.0f%% of the daily volume", fit.Model, 100*fit.R2Daily)
+
+		cumulative := estimate.Observed
+		for h, v := range fit.curve.Forecast(horizon) {
+			cumulative += v
+			estimate.Projection = append(estimate.Projection, PeakProjection{
+				Date:       last.AddDate(0, 0, h+1),
+				Volume:     estimate.Baseline + v,
+				Cumulative: cumulative,
+			})
+		}
+		return estimate, nil
+	}
+
+	// Without a curve, a trend that has clearly turned down has peaked
+	reason := "no diffusion curve fits the volume"
+	if len(estimate.Candidates) > 0 {
+		reason = estimate.Candidates[0].Reason
+	}
+	lifecycle := analysis.ClassifyLifecycle(volumes)
+	peaked := lifecycle.Stage == analysis.StageDeclining || lifecycle.Stage == analysis.StageFad ||
+		lifecycle.DistanceFromPeak >= 0.2 && len(volumes)-1-lifecycle.PeakIndex >= analysis.DefaultMinSize
+	if peaked {
+		window := volumes[max(0, lifecycle.PeakIndex-3):min(len(volumes), lifecycle.PeakIndex+4)]
+		for _, v := range window {
+			estimate.PeakVolume += v / float64(len(window))
+		}
+		estimate.Status = PeakObserved
+		estimate.PeakDate = daily[lifecycle.PeakIndex].Date
+		estimate.Reason = reason + "; the weekly average already peaked"
+	} else {
+		estimate.Status = PeakNone
+		estimate.Reason = reason + "; the trend has not peaked yet"
+	}
+	return estimate, nil
+}
+
+// newDiffusionFit converts a fitted curve to dates and volumes and checks
+// whether its peak can be trusted
+func (e *PeakEstimate) newDiffusionFit(curve *prediction.DiffusionModel, first time.Time) DiffusionFit {
+	fit := DiffusionFit{
+		Model:      curve.Kind,
+		Spec:       curve.String(),
+		Parameters: curve.Parameters(),
+		PeakDate:   first.AddDate(0, 0, int(math.Round(math.Max(math.Min(curve.PeakDay, 1e6), -1e6)))),
+		PeakVolume: e.Baseline + curve.PeakRate,
+		Saturation: curve.Saturation,
+		R2:         curve.R2,
+		R2Daily:    curve.R2Daily,
+		RMSE:       curve.RMSE,
+		AIC:        curve.AIC,
+		curve:      curve,
+	}
+
+	switch {
+	case curve.R2Daily < minPeakFitR2:
+		fit.Reason = fmt.Sprintf("%s curve explains only %.0f%% of the daily volume", curve.Kind, 100*math.Max(curve.R2Daily, 0))
+	case curve.Saturation > maxSaturationRatio*e.Observed:
+		fit.Reason = fmt.Sprintf("%s curve saturation is not identified by the data so far", curve.Kind)
+	case curve.PeakDay > float64(e.Days+maxPeakLeadDays):
+		fit.Reason = fmt.Sprintf("%s curve peaks more than %d days ahead", curve.Kind, maxPeakLeadDays)
+	case curve.PeakDay < -float64(e.Days):
+		fit.Reason = fmt.Sprintf("%s curve peaks long before the data", curve.Kind)
+	default:
+		fit.Acceptable = true
+	}
+	return fit
+}
@@ -0,0 +1,128 @@
+package trend
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/trendscout/backend/internal/prediction"
+)
+
+// logisticVolumes returns n days of a baseline of 10 and the daily volume of
+// a logistic curve saturating at 3000 with the peak on the given day
+func logisticVolumes(rng *rand.Rand, n int, peak float64) []float64 {
+	volumes := make([]float64, n)
+	for i := range volumes {
+		e := math.Exp(-0.12 * (float64(i) + 0.5 - peak))
+		volumes[i] = 10 + 3000*0.12*e/((1+e)*(1+e)) + rng.NormFloat64()*2
+	}
+	return volumes
+}
+
+func TestEstimatePeak(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+
+	// A bump that has passed, too noisy for any curve to fit well
+	bump := make([]float64, 90)
+	for i := range bump {
+		bump[i] = 40 + 40*math.Exp(-math.Pow(float64(i-25)/12, 2)) + rng.NormFloat64()*15
+	}
+	flat := make([]float64, 60)
+	for i := range flat {
+		flat[i] = 50 + rng.NormFloat64()*10
+	}
+
+	tests := []struct {
+		name    string
+		volumes []float64
+		model   string
+		status  string
+		peakDay int    // -1 without a peak date
+		reason  string // part of the reason
+	}{
+		{"past its peak", logisticVolumes(rng, 100, 50), ModelAuto, PeakFitted, 50, "explains"},
+		{"requested curve", logisticVolumes(rng, 100, 50), prediction.DiffusionGompertz, PeakFitted, 50, "gompertz"},
+		{"noisy bump", bump, ModelAuto, PeakObserved, 25, "already peaked"},
+		{"before its peak", logisticVolumes(rng, 40, 70), ModelAuto, PeakNone, -1, "saturation is not identified"},
+		{"flat", flat, ModelAuto, PeakNone, -1, "explains only"},
+	}
+
+	for _, tt := range tests {
+		estimate, err := EstimatePeak(dailySeries(tt.volumes), tt.model, 14)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if estimate.Status != tt.status || !strings.Contains(estimate.Reason, tt.reason) {
+			t.Errorf("%s: status %s (%s), want %s with %q", tt.name, estimate.Status, estimate.Reason, tt.status, tt.reason)
+			continue
+		}
+		if estimate.Days != len(tt.volumes) || estimate.Observed <= 0 {
+			t.Errorf("%s: days = %d, observed = %v", tt.name, estimate.Days, estimate.Observed)
+		}
+
+		first := dailySeries(tt.volumes)[0].Date
+		if tt.peakDay < 0 {
+			if !estimate.PeakDate.IsZero() || estimate.Fit != nil || len(estimate.Projection) != 0 {
+				t.Errorf("%s: estimate without a peak has peak %v, fit %v", tt.name, estimate.PeakDate, estimate.Fit)
+			}
+			continue
+		}
+		if days := estimate.PeakDate.Sub(first).Hours() / 24; math.Abs(days-float64(tt.peakDay)) > 5 {
+			t.Errorf("%s: peak on day %.0f, want about %d", tt.name, days, tt.peakDay)
+		}
+		if tt.status != PeakFitted {
+			if estimate.Fit != nil || estimate.Saturation != 0 {
+				t.Errorf("%s: observed peak has a fit %+v", tt.name, estimate.Fit)
+			}
+			continue
+		}
+		if tt.model != ModelAuto && (estimate.Fit.Model != tt.model || len(estimate.Candidates) != 1) {
+			t.Errorf("%s: fitted %s of %d candidates, want only %s", tt.name, estimate.Fit.Model, len(estimate.Candidates), tt.model)
+		}
+		if math.Abs(estimate.Saturation-3000) > 300 {
+			t.Errorf("%s: saturation = %v, want about 3000", tt.name, estimate.Saturation)
+		}
+		if len(estimate.Projection) != 14 || estimate.Projection[13].Cumulative < estimate.Observed {
+			t.Errorf("%s: projection = %+v", tt.name, estimate.Projection)
+		}
+	}
+
+	if _, err := EstimatePeak(dailySeries(flat[:minPeakDays-1]), ModelAuto, 14); err == nil {
+		t.Error("expected an error for too short a series")
+	}
+	if _, err := EstimatePeak(dailySeries(flat), "weibull", 14); err == nil {
+		t.Error("expected an error for an unknown curve")
+	}
+}
+
+func TestNewDiffusionFitRejections(t *testing.T) {
+	// 60 days with a cumulative volume of 1000 above the baseline
+	estimate := &PeakEstimate{Observed: 1000, Days: 60, Baseline: 5}
+	first := dailySeries([]float64{0})[0].Date
+
+	tests := []struct {
+		name   string
+		curve  prediction.DiffusionModel
+		reason string // empty when acceptable
+	}{
+		{"good fit", prediction.DiffusionModel{R2Daily: 0.9, Saturation: 1500, PeakDay: 40, PeakRate: 50}, ""},
+		{"poor fit", prediction.DiffusionModel{R2Daily: 0.3, Saturation: 1500, PeakDay: 40}, "explains only 30%"},
+		{"saturation not identified", prediction.DiffusionModel{R2Daily: 0.9, Saturation: 20000, PeakDay: 40}, "saturation is not identified"},
+		{"peak too far ahead", prediction.DiffusionModel{R2Daily: 0.9, Saturation: 5000, PeakDay: 60 + maxPeakLeadDays + 1}, "days ahead"},
+		{"peak before the data", prediction.DiffusionModel{R2Daily: 0.9, Saturation: 1200, PeakDay: -61}, "long before the data"},
+	}
+
+	for _, tt := range tests {
+		tt.curve.Kind = prediction.DiffusionLogistic
+		fit := estimate.newDiffusionFit(&tt.curve, first)
+		if fit.Acceptable != (tt.reason == "") || !strings.Contains(fit.Reason, tt.reason) {
+			t.Errorf("%s: acceptable %v (%s), want reason %q", tt.name, fit.Acceptable, fit.Reason, tt.reason)
+		}
+	}
+
+	good := estimate.newDiffusionFit(&prediction.DiffusionModel{Kind: prediction.DiffusionBass, R2Daily: 0.9, Saturation: 1500, PeakDay: 40.4, PeakRate: 50}, first)
+	if !good.PeakDate.Equal(first.AddDate(0, 0, 40)) || good.PeakVolume != 55 {
+		t.Errorf("peak on %v with volume %v, want day 40 with 55", good.PeakDate, good.PeakVolume)
+	}
+}
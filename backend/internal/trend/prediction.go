@@ -4,8 +4,52 @@ import (
 	"fmt"
 	"math"
 	"time"
+
+	"github.com/trendscout/backend/internal/prediction"
 )
 
+// confidenceLevel is the prediction interval level the confidence score is derived from
+const confidenceLevel = 0.8
+
+// volumeForecaster is a fitted volume model
+type volumeForecaster interface {
+	Forecast(horizon int) ([]float64, []float64)
+	Intervals(horizon int, levels []float64) [][]prediction.Interval
+	Parameters() map[string]float64
+	String() string
+}
+
+// PredictionOptions configures a prediction
+type PredictionOptions struct {
+	Model          string    // volume model, ModelAuto or ModelEnsemble; ModelAuto when empty
+	IntervalLevels []float64 // prediction interval levels in (0, 1); prediction.DefaultIntervalLevels when empty
+	FullHistory    bool      // train volume models on the whole series instead of the current regime
+}
+
+// How the volume model of a prediction was chosen
+const (
+	SelectionRequested = "requested" // the requested model
+	SelectionFallback  = "fallback"  // Holt-Winters, as the requested model could not be fitted
+	SelectionBacktest  = "backtest"  // the model with the lowest backtest MASE
+	SelectionDefault   = "default"   // Holt-Winters, as no model could be backtested on the series
+	SelectionEnsemble  = "ensemble"  // a weighted ensemble of the best models
+)
+
+// VolumeFit describes the volume model fitted for a prediction
+type VolumeFit struct {
+	Model      string             // volume model used, or ModelEnsemble
+	Spec       string             // fitted specification, such as HW(Ad,M)[7]
+	Parameters map[string]float64 // fitted parameters; the member weights of an ensemble
+	AIC        float64            // NaN for an ensemble
+	Selection  string             // how the model was chosen
+	Reason     string             // explanation of the choice
+	Candidates []ModelScore       // models compared by automatic selection, best first
+
+	TrainingStart  time.Time // first day the model was trained on
+	TrainingPoints int
+	RegimeStart    time.Time // start of the current volume regime; zero when no change was detected
+}
+
 // PredictionEngine handles trend prediction calculations
 type PredictionEngine struct{}
 
@@ -26,25 +70,61 @@ type EnhancedPredictionResult struct {
 	Date            time.Time
 	Volume          float64
 	Sentiment       float64
-	Confidence      float64
+	Confidence      float64 // score derived from the width of the 80% interval
 	TrendDirection  string
-	SeasonalFactor  float64
+	VolumeStdErr    float64               // forecast standard error of Volume
+	Intervals       []prediction.Interval // prediction intervals of Volume, one per level
 }
 
 // PredictTrend performs advanced trend prediction using multiple algorithms
 func (e *PredictionEngine) PredictTrend(historical []TrendPoint, horizon int) ([]EnhancedPredictionResult, error) {
+	results, _, err := e.PredictTrendWithOptions(historical, horizon, PredictionOptions{})
+	return results, err
+}
+
+// PredictTrendWithOptions performs trend prediction with the given volume
+// model and interval levels and describes the fitted model and why it was
+// used. A requested model that cannot be fitted falls back to Holt-Winters.
+func (e *PredictionEngine) PredictTrendWithOptions(historical []TrendPoint, horizon int, opts PredictionOptions) ([]EnhancedPredictionResult, *VolumeFit, error) {
+	if opts.Model == "" {
+		opts.Model = ModelAuto
+	}
+	if !IsModelChoice(opts.Model) {
+		return nil, nil, fmt.Errorf("unknown volume model %q", opts.Model)
+	}
+	if len(opts.IntervalLevels) == 0 {
+		opts.IntervalLevels = prediction.DefaultIntervalLevels
+	}
+	if err := prediction.ValidateLevels(opts.IntervalLevels); err != nil {
+		return nil, nil, err
+	}
 	if len(historical) < 7 {
-		return nil, fmt.Errorf("insufficient data for prediction (minimum 7 points required)")
+		return nil, nil, fmt.Errorf("insufficient data for prediction (minimum 7 points required)")
 	}
 
-	// Sort data by date
-	sortByDate(historical)
+	// Models assume one point per day; fill the days that were not collected
+	historical = RegularizeDaily(historical)
+
+	// Train volume models on the current regime only, so that a trend that
+	// took off recently is not averaged with the weeks before it
+	regime := currentRegime(historical)
+	start := 0
+	if !opts.FullHistory {
+		start = trainingStart(historical, regime)
+	}
 
 	// Perform different types of predictions
-	volumePredictions := e.predictVolume(historical, horizon)
+	volume, fit, err := e.predictVolume(historical[start:], horizon, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	fit.TrainingStart = historical[start].Date
+	fit.TrainingPoints = len(historical) - start
+	if regime.Start > 0 {
+		fit.RegimeStart = historical[regime.Start].Date
+	}
 	sentimentPredictions := e.predictSentiment(historical, horizon)
-	trendAnalysis := e.analyzeTrend(historical)
-	seasonalFactors := e.calculateSeasonalFactors(historical)
+	trendAnalysis := SlopeDirection(regime.Slope, regime.Mean)
 
 	// Combine predictions
 	var results []EnhancedPredictionResult
@@ -52,59 +132,101 @@ func (e *PredictionEngine) PredictTrend(historical []TrendPoint, horizon int) ([
 
 	for i := 0; i < horizon; i++ {
 		predDate := baseDate.AddDate(0, 0, i+1)
-		seasonalIndex := i % len(seasonalFactors)
-		
-		result := EnhancedPredictionResult{
+
+		results = append(results, EnhancedPredictionResult{
 			Date:            predDate,
-			Volume:          volumePredictions[i],
+			Volume:          volume.predictions[i],
 			Sentiment:       sentimentPredictions[i],
-			Confidence:      e.calculateConfidence(historical, i),
+			Confidence:      intervalConfidence(volume.predictions[i], volume.stdErrs[i]),
 			TrendDirection:  trendAnalysis,
-			SeasonalFactor:  seasonalFactors[seasonalIndex],
-		}
-
-		// Apply seasonal adjustment
-		result.Volume *= result.SeasonalFactor
-
-		results = append(results, result)
+			VolumeStdErr:    volume.stdErrs[i],
+			Intervals:       volume.intervals[i],
+		})
 	}
 
-	return results, nil
+	return results, fit, nil
 }
 
-// predictVolume predicts volume using Exponential Smoothing with Trend
-func (e *PredictionEngine) predictVolume(data []TrendPoint, horizon int) []float64 {
-	if len(data) < 2 {
-		return make([]float64, horizon)
+// volumeForecast holds the volume predictions of a fitted model
+type volumeForecast struct {
+	predictions []float64
+	stdErrs     []float64
+	intervals   [][]prediction.Interval
+}
+
+// predictVolume predicts volume, its standard errors and prediction
+// intervals with the requested model, or with the model or ensemble chosen
+// for the series
+func (e *PredictionEngine) predictVolume(data []TrendPoint, horizon int, opts PredictionOptions) (*volumeForecast, *VolumeFit, error) {
+	volumes := volumesOf(data)
+
+	var forecaster volumeForecaster
+	var fit *VolumeFit
+	var err error
+	switch opts.Model {
+	case ModelAuto:
+		forecaster, fit, err = e.selectVolumeModel(data, volumes, horizon)
+	case ModelEnsemble:
+		forecaster, fit, err = e.ensembleVolumeModel(data, volumes, horizon)
+	default:
+		forecaster, fit, err = fitRequestedModel(volumes, opts.Model)
+	}
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Holt's Exponential Smoothing parameters
-	alpha := 0.3 // Level smoothing parameter
-	beta := 0.1  // Trend smoothing parameter
+	forecast := &volumeForecast{}
+	forecast.predictions, forecast.stdErrs = forecaster.Forecast(horizon)
+	forecast.intervals = forecaster.Intervals(horizon, opts.IntervalLevels)
+	for i := range forecast.predictions {
+		// Ensure non-negative values
+		forecast.predictions[i] = math.Max(0, forecast.predictions[i])
+		for j := range forecast.intervals[i] {
+			forecast.intervals[i][j].Lower = math.Max(0, forecast.intervals[i][j].Lower)
+			forecast.intervals[i][j].Upper = math.Max(0, forecast.intervals[i][j].Upper)
+		}
+	}
 
-	// Initialize
-	level := data[0].Volume
-	trend := data[1].Volume - data[0].Volume
+	return forecast, fit, nil
+}
 
-	// Apply Holt's method to historical data
-	for i := 1; i < len(data); i++ {
-		prevLevel := level
-		level = alpha*data[i].Volume + (1-alpha)*(level+trend)
-		trend = beta*(level-prevLevel) + (1-beta)*trend
+// fitRequestedModel fits the requested volume model, falling back to
+// Holt-Winters when the series cannot be fitted
+func fitRequestedModel(volumes []float64, model string) (volumeForecaster, *VolumeFit, error) {
+	forecaster, aic, err := fitVolumeModel(volumes, model)
+	if err == nil {
+		return forecaster, newVolumeFit(model, forecaster, aic, SelectionRequested, "requested"), nil
+	}
+	if model == VolumeModelHoltWinters {
+		return nil, nil, fmt.Errorf("failed to fit %s model: %w", model, err)
 	}
 
-	// Generate predictions
-	predictions := make([]float64, horizon)
-	for i := 0; i < horizon; i++ {
-		predictions[i] = level + float64(i+1)*trend
-		
-		// Ensure non-negative values
-		if predictions[i] < 0 {
-			predictions[i] = 0
-		}
+	reason := fmt.Sprintf("%s could not be fitted (%v)", model, err)
+	forecaster, aic, fallbackErr := fitVolumeModel(volumes, VolumeModelHoltWinters)
+	if fallbackErr != nil {
+		return nil, nil, fmt.Errorf("failed to fit %s model: %w", model, err)
 	}
+	return forecaster, newVolumeFit(VolumeModelHoltWinters, forecaster, aic, SelectionFallback, reason), nil
+}
 
-	return predictions
+// newVolumeFit describes a fitted volume model
+func newVolumeFit(model string, forecaster volumeForecaster, aic float64, selection, reason string) *VolumeFit {
+	return &VolumeFit{
+		Model:      model,
+		Spec:       forecaster.String(),
+		Parameters: forecaster.Parameters(),
+		AIC:        aic,
+		Selection:  selection,
+		Reason:     reason,
+	}
+}
+
+// intervalConfidence derives the confidence score from the relative width of
+// the 80% prediction interval: 1 for an exact forecast, falling towards 0 as
+// the interval grows relative to the forecast
+func intervalConfidence(forecast, stdErr float64) float64 {
+	halfWidth := prediction.NormalQuantile(0.5+confidenceLevel/2) * stdErr
+	return 1 / (1 + halfWidth/math.Max(math.Abs(forecast), 1))
 }
 
 // predictSentiment predicts sentiment using moving average with momentum
@@ -165,160 +287,6 @@ func (e *PredictionEngine) predictSentiment(data []TrendPoint, horizon int) []fl
 	return predictions
 }
 
-// analyzeTrend determines the overall trend direction
-func (e *PredictionEngine) analyzeTrend(data []TrendPoint) string {
-	if len(data) < 3 {
-		return "stable"
-	}
-
-	// Calculate slope using linear regression
-	n := float64(len(data))
-	sumX := 0.0
-	sumY := 0.0
-	sumXY := 0.0
-	sumX2 := 0.0
-
-	for i, point := range data {
-		x := float64(i)
-		y := point.Volume
-		
-		sumX += x
-		sumY += y
-		sumXY += x * y
-		sumX2 += x * x
-	}
-
-	slope := (n*sumXY - sumX*sumY) / (n*sumX2 - sumX*sumX)
-
-	// Classify trend based on slope
-	if slope > 2.0 {
-		return "strong_upward"
-	} else if slope > 0.5 {
-		return "upward"
-	} else if slope > -0.5 {
-		return "stable"
-	} else if slope > -2.0 {
-		return "downward"
-	} else {
-		return "strong_downward"
-	}
-}
-
-// calculateSeasonalFactors calculates weekly seasonal patterns
-func (e *PredictionEngine) calculateSeasonalFactors(data []TrendPoint) []float64 {
-	// Default seasonal factors (Sunday to Saturday)
-	// Fashion trends often peak on weekends and Wednesdays
-	defaultFactors := []float64{1.1, 0.9, 0.95, 1.05, 1.0, 1.1, 1.15}
-
-	if len(data) < 14 { // Need at least 2 weeks for seasonal analysis
-		return defaultFactors
-	}
-
-	// Calculate day-of-week averages
-	dayAverages := make([]float64, 7)
-	dayCounts := make([]int, 7)
-
-	for _, point := range data {
-		dayOfWeek := int(point.Date.Weekday())
-		dayAverages[dayOfWeek] += point.Volume
-		dayCounts[dayOfWeek]++
-	}
-
-	// Calculate averages and normalize
-	overallAverage := 0.0
-	validDays := 0
-
-	for i := 0; i < 7; i++ {
-		if dayCounts[i] > 0 {
-			dayAverages[i] /= float64(dayCounts[i])
-			overallAverage += dayAverages[i]
-			validDays++
-		}
-	}
-
-	if validDays == 0 {
-		return defaultFactors
-	}
-
-	overallAverage /= float64(validDays)
-
-	// Normalize to create seasonal factors
-	seasonalFactors := make([]float64, 7)
-	for i := 0; i < 7; i++ {
-		if dayCounts[i] > 0 && overallAverage > 0 {
-			seasonalFactors[i] = dayAverages[i] / overallAverage
-		} else {
-			seasonalFactors[i] = defaultFactors[i]
-		}
-
-		// Clamp factors to reasonable range
-		if seasonalFactors[i] < 0.7 {
-			seasonalFactors[i] = 0.7
-		} else if seasonalFactors[i] > 1.5 {
-			seasonalFactors[i] = 1.5
-		}
-	}
-
-	return seasonalFactors
-}
-
-// calculateConfidence determines prediction confidence based on data quality
-func (e *PredictionEngine) calculateConfidence(data []TrendPoint, predictionDay int) float64 {
-	baseConfidence := 0.8
-
-	// Reduce confidence based on prediction distance
-	distanceDecay := math.Exp(-float64(predictionDay) * 0.1)
-	
-	// Reduce confidence based on data variability
-	if len(data) > 2 {
-		variance := e.calculateVariance(data)
-		variabilityFactor := math.Max(0.3, 1.0-variance*0.01)
-		baseConfidence *= variabilityFactor
-	}
-
-	// Reduce confidence based on data recency
-	if len(data) > 0 {
-		lastDataAge := time.Since(data[len(data)-1].Date).Hours() / 24
-		recencyFactor := math.Max(0.5, 1.0-lastDataAge*0.05)
-		baseConfidence *= recencyFactor
-	}
-
-	return math.Max(0.1, math.Min(0.95, baseConfidence*distanceDecay))
-}
-
-// calculateVariance calculates the variance in volume data
-func (e *PredictionEngine) calculateVariance(data []TrendPoint) float64 {
-	if len(data) < 2 {
-		return 0
-	}
-
-	mean := 0.0
-	for _, point := range data {
-		mean += point.Volume
-	}
-	mean /= float64(len(data))
-
-	variance := 0.0
-	for _, point := range data {
-		diff := point.Volume - mean
-		variance += diff * diff
-	}
-	variance /= float64(len(data) - 1)
-
-	return math.Sqrt(variance)
-}
-
-// sortByDate sorts trend points by date
-func sortByDate(data []TrendPoint) {
-	for i := 0; i < len(data)-1; i++ {
-		for j := 0; j < len(data)-i-1; j++ {
-			if data[j].Date.After(data[j+1].Date) {
-				data[j], data[j+1] = data[j+1], data[j]
-			}
-		}
-	}
-}
-
 // GetTrendInsights provides detailed trend analysis and insights
 func (e *PredictionEngine) GetTrendInsights(data []TrendPoint, predictions []EnhancedPredictionResult) map[string]interface{} {
 	insights := make(map[string]interface{})
@@ -336,16 +304,17 @@ func (e *PredictionEngine) GetTrendInsights(data []TrendPoint, predictions []Enh
 	}
 	avgSentiment /= float64(len(data))
 
-	// Growth metrics
-	if len(data) >= 7 {
-		recentWeek := data[len(data)-7:]
+	// Growth metrics compare calendar weeks, so work on the regular daily series
+	daily := RegularizeDaily(data)
+	if len(daily) >= 7 {
+		recentWeek := daily[len(daily)-7:]
 		weekVolume := 0.0
 		for _, point := range recentWeek {
 			weekVolume += point.Volume
 		}
 
-		if len(data) >= 14 {
-			prevWeek := data[len(data)-14 : len(data)-7]
+		if len(daily) >= 14 {
+			prevWeek := daily[len(daily)-14 : len(daily)-7]
 			prevWeekVolume := 0.0
 			for _, point := range prevWeek {
 				prevWeekVolume += point.Volume
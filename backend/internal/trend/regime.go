@@ -0,0 +1,87 @@
+package trend
+
+import (
+	"math"
+
+	"github.com/trendscout/backend/internal/analysis"
+	"github.com/trendscout/backend/internal/prediction"
+)
+
+// minRegimeTraining is the fewest days volume models are trained on when the
+// current regime is shorter, enough for four weekly cycles
+const minRegimeTraining = 28
+
+// RegimeAnalysis is the segmentation of a trend series into regimes with
+// their own level and slope
+type RegimeAnalysis struct {
+	Series []TrendPoint     // regularized daily series the indices refer to
+	Result *analysis.Result // offline changepoints and segments
+	Alarms []analysis.CUSUMAlarm
+	CUSUM  analysis.CUSUMState // online detector state after the last day
+}
+
+// DetectRegimes regularizes the series to days and detects changes in the
+// level and slope of its volume with weekly seasonality removed, both offline
+// and with the online CUSUM detector
+func DetectRegimes(historical []TrendPoint, opts analysis.Options) (*RegimeAnalysis, error) {
+	daily := RegularizeDaily(historical)
+	opts.Period = prediction.WeeklyPeriod
+
+	volumes := volumesOf(daily)
+	result, err := analysis.DetectChangepoints(volumes, opts)
+	if err != nil {
+		return nil, err
+	}
+	alarms, state, err := analysis.CUSUM(analysis.Deseasonalize(volumes, opts.Period), analysis.CUSUMOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &RegimeAnalysis{Series: daily, Result: result, Alarms: alarms, CUSUM: state}, nil
+}
+
+// CurrentRegime returns the last segment
+func (r *RegimeAnalysis) CurrentRegime() analysis.Segment {
+	return r.Result.Segments[len(r.Result.Segments)-1]
+}
+
+// currentRegime segments a regular daily series and returns its last regime
+func currentRegime(data []TrendPoint) analysis.Segment {
+	result, err := analysis.DetectChangepoints(volumesOf(data), analysis.Options{Period: prediction.WeeklyPeriod})
+	if err != nil || len(result.Segments) == 0 {
+		return analysis.Segment{End: len(data)}
+	}
+	return result.Segments[len(result.Segments)-1]
+}
+
+// trainingStart returns the index volume models are trained from: the start
+// of the current regime, moved back to keep at least minRegimeTraining days
+func trainingStart(data []TrendPoint, regime analysis.Segment) int {
+	return max(0, min(regime.Start, len(data)-minRegimeTraining))
+}
+
+// SlopeDirection classifies a daily slope relative to the level it changes,
+// so that the direction means the same for small and large keywords: upward
+// from 1% of the level a day and strongly upward from 4%
+func SlopeDirection(slope, level float64) string {
+	relative := slope / math.Max(math.Abs(level), 1e-9)
+	if relative > 0.04 {
+		return "strong_upward"
+	} else if relative > 0.01 {
+		return "upward"
+	} else if relative > -0.01 {
+		return "stable"
+	} else if relative > -0.04 {
+		return "downward"
+	}
+	return "strong_downward"
+}
+
+// volumesOf returns the volumes of a series
+func volumesOf(data []TrendPoint) []float64 {
+	volumes := make([]float64, len(data))
+	for i, point := range data {
+		volumes[i] = point.Volume
+	}
+	return volumes
+}
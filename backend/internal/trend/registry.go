@@ -0,0 +1,128 @@
+package trend
+
+import (
+	"github.com/trendscout/backend/internal/prediction"
+)
+
+// Volume models of the prediction engine
+const (
+	// VolumeModelNaive repeats the last observed value
+	VolumeModelNaive = "naive"
+	// VolumeModelSeasonalNaive repeats the last observed week
+	VolumeModelSeasonalNaive = "snaive"
+	// VolumeModelLinear extends a least-squares line through the series
+	VolumeModelLinear = "linear"
+	// VolumeModelHolt is Holt's linear exponential smoothing, optionally damped
+	VolumeModelHolt = "holt"
+	// VolumeModelHoltWinters is Holt-Winters with additive or multiplicative
+	// weekly seasonality, optionally damped
+	VolumeModelHoltWinters = "hw"
+	// VolumeModelARIMA is a SARIMA model with weekly seasonality and automatic order selection
+	VolumeModelARIMA = "arima"
+	// VolumeModelCroston is Croston's method for intermittent series with many zero days
+	VolumeModelCroston = "croston"
+)
+
+// Model choices that pick or combine volume models per series
+const (
+	// ModelAuto uses the volume model with the best backtest accuracy on the series
+	ModelAuto = "auto"
+	// ModelEnsemble combines the most accurate volume models with weights
+	ModelEnsemble = "ensemble"
+)
+
+// volumeModel is an entry of the volume model registry
+type volumeModel struct {
+	name string
+	fit  func(volumes []float64) (volumeForecaster, float64, error)
+}
+
+// volumeModels is the volume model registry, simplest models first
+var volumeModels = []volumeModel{
+	{VolumeModelNaive, func(v []float64) (volumeForecaster, float64, error) {
+		m, err := prediction.FitNaive(v, 0)
+		if err != nil {
+			return nil, 0, err
+		}
+		return m, m.AIC, nil
+	}},
+	{VolumeModelSeasonalNaive, func(v []float64) (volumeForecaster, float64, error) {
+		m, err := prediction.FitNaive(v, prediction.WeeklyPeriod)
+		if err != nil {
+			return nil, 0, err
+		}
+		return m, m.AIC, nil
+	}},
+	{VolumeModelLinear, func(v []float64) (volumeForecaster, float64, error) {
+		m, err := prediction.FitLinearTrend(v)
+		if err != nil {
+			return nil, 0, err
+		}
+		return m, m.AIC, nil
+	}},
+	{VolumeModelHolt, func(v []float64) (volumeForecaster, float64, error) {
+		m, err := prediction.AutoHoltWinters(v, 0)
+		if err != nil {
+			return nil, 0, err
+		}
+		return m, m.AIC, nil
+	}},
+	{VolumeModelHoltWinters, func(v []float64) (volumeForecaster, float64, error) {
+		m, err := prediction.AutoHoltWinters(v, prediction.WeeklyPeriod)
+		if err != nil {
+			return nil, 0, err
+		}
+		return m, m.AIC, nil
+	}},
+	{VolumeModelARIMA, func(v []float64) (volumeForecaster, float64, error) {
+		m, err := prediction.AutoARIMA(v, prediction.WeeklyPeriod)
+		if err != nil {
+			return nil, 0, err
+		}
+		return m, m.AIC, nil
+	}},
+	{VolumeModelCroston, func(v []float64) (volumeForecaster, float64, error) {
+		m, err := prediction.FitCroston(v)
+		if err != nil {
+			return nil, 0, err
+		}
+		return m, m.AIC, nil
+	}},
+}
+
+// VolumeModels lists the registered volume models
+func VolumeModels() []string {
+	names := make([]string, len(volumeModels))
+	for i, m := range volumeModels {
+		names[i] = m.name
+	}
+	return names
+}
+
+// IsVolumeModel reports whether model is a registered volume model
+func IsVolumeModel(model string) bool {
+	_, ok := lookupVolumeModel(model)
+	return ok
+}
+
+// IsModelChoice reports whether model can be requested for a prediction:
+// a registered volume model, ModelAuto or ModelEnsemble
+func IsModelChoice(model string) bool {
+	return model == ModelAuto || model == ModelEnsemble || IsVolumeModel(model)
+}
+
+// lookupVolumeModel finds a registered volume model by name
+func lookupVolumeModel(name string) (volumeModel, bool) {
+	for _, m := range volumeModels {
+		if m.name == name {
+			return m, true
+		}
+	}
+	return volumeModel{}, false
+}
+
+// fitVolumeModel fits a registered volume model, selecting its variant by AIC
+func fitVolumeModel(volumes []float64, name string) (volumeForecaster, float64, error) {
+	m, _ := lookupVolumeModel(name)
+	return m.fit(volumes)
+}
@@ -0,0 +1,44 @@
+package trend
+
+import (
+	"github.com/trendscout/backend/internal/series"
+	"github.com/trendscout/backend/internal/timezone"
+)
+
+// RegularizeDaily returns one point per day from the first to the last
+// observation. Several points on the same day are averaged, and days without
+// data are linearly interpolated from their neighbours: a missing day means
+// the keyword was not collected, not that interest dropped to zero.
+func RegularizeDaily(points []TrendPoint) []TrendPoint {
+	if len(points) == 0 {
+		return nil
+	}
+
+	volumes := make([]series.Point, len(points))
+	sentiments := make([]series.Point, len(points))
+	for i, p := range points {
+		date := p.Date.In(timezone.Collection())
+		volumes[i] = series.Point{Time: date, Value: p.Volume, Valid: true}
+		sentiments[i] = series.Point{Time: date, Value: p.Sentiment, Valid: true}
+	}
+
+	volumes = regularizeDaily(volumes)
+	sentiments = regularizeDaily(sentiments)
+
+	result := make([]TrendPoint, len(volumes))
+	for i := range volumes {
+		result[i] = TrendPoint{
+			Date:      volumes[i].Time,
+			Volume:    volumes[i].Value,
+			Sentiment: sentiments[i].Value,
+		}
+	}
+	return result
+}
+
+// regularizeDaily resamples a series to days and interpolates the gaps. The
+// range spans the observations, so every resulting point is valid.
+func regularizeDaily(points []series.Point) []series.Point {
+	daily := series.Resample(points, series.Day, series.Avg)
+	return series.FillGaps(daily, series.Day, daily[0].Time, daily[len(daily)-1].Time, series.FillInterpolate)
+}
@@ -0,0 +1,229 @@
+package trend
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/trendscout/backend/internal/prediction"
+)
+
+const (
+	// selectionFolds is the number of backtest folds used to score models
+	selectionFolds = 3
+	// maxSelectionHorizon caps the forecast horizon of selection backtests
+	maxSelectionHorizon = 14
+	// ensembleSize is the number of models combined by an ensemble
+	ensembleSize = 3
+)
+
+// ModelScore is the score of a candidate volume model in automatic selection.
+// AICs are reported for reference only: those of different models are not
+// comparable, as they are computed on differenced or partly used series.
+type ModelScore struct {
+	Model  string
+	MASE   float64 // backtest MASE; NaN when not backtested or the model failed
+	AIC    float64 // AIC of the fit to the whole series; NaN when it could not be fitted
+	Weight float64 // weight in the ensemble, if the model is a member
+}
+
+// modelScoring holds every registered model scored and fitted on a series
+type modelScoring struct {
+	scores  []ModelScore // ordered by MASE, best first
+	fitted  map[string]volumeForecaster
+	horizon int // horizon of the backtest folds
+	folds   int
+}
+
+// scoreVolumeModels fits every registered model to the series and scores it
+// with a backtest over the last weeks, ordering the models by MASE
+func (e *PredictionEngine) scoreVolumeModels(data []TrendPoint, volumes []float64, horizon int) *modelScoring {
+	scoring := &modelScoring{fitted: map[string]volumeForecaster{}}
+	for _, m := range volumeModels {
+		score := ModelScore{Model: m.name, MASE: math.NaN(), AIC: math.NaN()}
+		if forecaster, aic, err := m.fit(volumes); err == nil {
+			scoring.fitted[m.name] = forecaster
+			score.AIC = aic
+		}
+		scoring.scores = append(scoring.scores, score)
+	}
+
+	scoring.horizon = min(max(horizon, 1), maxSelectionHorizon)
+	scoring.folds = min(selectionFolds, (len(data)-minBacktestTraining)/scoring.horizon)
+	if scoring.folds >= 1 {
+		results, err := e.Backtest(data, BacktestOptions{Horizon: scoring.horizon, Folds: scoring.folds})
+		if err == nil {
+			mase := map[string]float64{}
+			for _, r := range results {
+				// A model that fell back to Holt-Winters was not itself evaluated
+				if r.Err == "" && r.Fallbacks == 0 {
+					mase[r.Model] = r.MASE
+				}
+			}
+			for i := range scoring.scores {
+				if v, ok := mase[scoring.scores[i].Model]; ok {
+					scoring.scores[i].MASE = v
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(scoring.scores, func(i, j int) bool {
+		return lessNaNLast(scoring.scores[i].MASE, scoring.scores[j].MASE)
+	})
+	return scoring
+}
+
+// eligible returns the models that were fitted to the whole series and have
+// a backtest score, best first
+func (s *modelScoring) eligible() []ModelScore {
+	var eligible []ModelScore
+	for _, score := range s.scores {
+		if _, ok := s.fitted[score.Model]; ok && !math.IsNaN(score.MASE) {
+			eligible = append(eligible, score)
+		}
+	}
+	return eligible
+}
+
+// fallback uses the Holt-Winters fit when no model could be backtested, as
+// AICs of different models cannot be compared
+func (s *modelScoring) fallback(days int) (volumeForecaster, *VolumeFit, error) {
+	reason := fmt.Sprintf("no model could be backtested on %d days", days)
+	if s.folds < 1 {
+		reason = fmt.Sprintf("%d days are too short to backtest (%d needed)", days, minBacktestTraining+s.horizon)
+	}
+
+	forecaster, ok := s.fitted[VolumeModelHoltWinters]
+	if !ok {
+		return nil, nil, fmt.Errorf("no volume model could be fitted: %s", reason)
+	}
+	var aic float64
+	for _, score := range s.scores {
+		if score.Model == VolumeModelHoltWinters {
+			aic = score.AIC
+		}
+	}
+	fit := newVolumeFit(VolumeModelHoltWinters, forecaster, aic, SelectionDefault, reason+"; using Holt-Winters")
+	fit.Candidates = s.scores
+	return forecaster, fit, nil
+}
+
+// basis describes the backtest the scores come from
+func (s *modelScoring) basis() string {
+	return fmt.Sprintf("backtest MASE over %d folds of %d days", s.folds, s.horizon)
+}
+
+// selectVolumeModel uses the model with the lowest backtest MASE on the series
+func (e *PredictionEngine) selectVolumeModel(data []TrendPoint, volumes []float64, horizon int) (volumeForecaster, *VolumeFit, error) {
+	scoring := e.scoreVolumeModels(data, volumes, horizon)
+	eligible := scoring.eligible()
+	if len(eligible) == 0 {
+		return scoring.fallback(len(data))
+	}
+
+	best := eligible[0]
+	forecaster := scoring.fitted[best.Model]
+	reason := fmt.Sprintf("lowest MASE (%.3f) of %d models; %s", best.MASE, len(eligible), scoring.basis())
+	fit := newVolumeFit(best.Model, forecaster, best.AIC, SelectionBacktest, reason)
+	fit.Candidates = scoring.scores
+	return forecaster, fit, nil
+}
+
+// ensembleVolumeModel combines the models with the lowest backtest MASE on
+// the series, weighted by inverse MASE
+func (e *PredictionEngine) ensembleVolumeModel(data []TrendPoint, volumes []float64, horizon int) (volumeForecaster, *VolumeFit, error) {
+	scoring := e.scoreVolumeModels(data, volumes, horizon)
+	eligible := scoring.eligible()
+	if len(eligible) == 0 {
+		return scoring.fallback(len(data))
+	}
+	if len(eligible) > ensembleSize {
+		eligible = eligible[:ensembleSize]
+	}
+
+	ensemble := &ensembleForecaster{}
+	total := 0.0
+	for _, score := range eligible {
+		weight := 1 / math.Max(score.MASE, 1e-6)
+		ensemble.names = append(ensemble.names, score.Model)
+		ensemble.members = append(ensemble.members, scoring.fitted[score.Model])
+		ensemble.weights = append(ensemble.weights, weight)
+		total += weight
+	}
+	weights := map[string]float64{}
+	for i := range ensemble.weights {
+		ensemble.weights[i] /= total
+		weights[ensemble.names[i]] = ensemble.weights[i]
+	}
+	for i := range scoring.scores {
+		scoring.scores[i].Weight = weights[scoring.scores[i].Model]
+	}
+
+	reason := fmt.Sprintf("best %d of %d models weighted by inverse MASE; %s", len(eligible), len(scoring.eligible()), scoring.basis())
+	fit := newVolumeFit(ModelEnsemble, ensemble, math.NaN(), SelectionEnsemble, reason)
+	fit.Candidates = scoring.scores
+	return ensemble, fit, nil
+}
+
+// ensembleForecaster combines fitted volume models with fixed weights
+type ensembleForecaster struct {
+	names   []string
+	members []volumeForecaster
+	weights []float64 // summing to 1
+}
+
+// Forecast returns the weighted forecasts. The standard error is the weighted
+// standard error of the members, which assumes their errors are perfectly
+// correlated and so does not understate the uncertainty.
+func (f *ensembleForecaster) Forecast(horizon int) ([]float64, []float64) {
+	forecasts := make([]float64, horizon)
+	stdErrs := make([]float64, horizon)
+	for i, member := range f.members {
+		values, errs := member.Forecast(horizon)
+		for h := range forecasts {
+			forecasts[h] += f.weights[i] * values[h]
+			stdErrs[h] += f.weights[i] * errs[h]
+		}
+	}
+	return forecasts, stdErrs
+}
+
+// Intervals returns the weighted average of the member interval bounds
+func (f *ensembleForecaster) Intervals(horizon int, levels []float64) [][]prediction.Interval {
+	intervals := make([][]prediction.Interval, horizon)
+	for h := range intervals {
+		intervals[h] = make([]prediction.Interval, len(levels))
+		for j, level := range levels {
+			intervals[h][j].Level = level
+		}
+	}
+	for i, member := range f.members {
+		for h, memberIntervals := range member.Intervals(horizon, levels) {
+			for j, interval := range memberIntervals {
+				intervals[h][j].Lower += f.weights[i] * interval.Lower
+				intervals[h][j].Upper += f.weights[i] * interval.Upper
+			}
+		}
+	}
+	return intervals
+}
+
+// Parameters returns the member weights by model name
+func (f *ensembleForecaster) Parameters() map[string]float64 {
+	params := map[string]float64{}
+	for i, name := range f.names {
+		params[name] = f.weights[i]
+	}
+	return params
+}
+
+// String lists the member specifications with their weights
+func (f *ensembleForecaster) String() string {
+	parts := make([]string, len(f.members))
+	for i, member := range f.members {
+		parts[i] = fmt.Sprintf("%.2f×%s", f.weights[i], member.String())
+	}
+	return "Ensemble(" + strings.Join(parts, " + ") + ")"
+}
@@ -0,0 +1,123 @@
+package trend
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// noisyLine returns 84 days of a line rising by 2 a day with a little noise,
+// which the linear trend model forecasts best
+func noisyLine() []float64 {
+	rng := rand.New(rand.NewSource(1))
+	volumes := make([]float64, 84)
+	for i := range volumes {
+		volumes[i] = 50 + 2*float64(i) + rng.NormFloat64()*0.5
+	}
+	return volumes
+}
+
+func TestSelectVolumeModelPicksLowestMASE(t *testing.T) {
+	volumes := noisyLine()
+	_, fit, err := NewPredictionEngine().selectVolumeModel(dailySeries(volumes), volumes, 14)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fit.Model != VolumeModelLinear || fit.Selection != SelectionBacktest {
+		t.Errorf("selected %s (%s: %s), want %s by backtest", fit.Model, fit.Selection, fit.Reason, VolumeModelLinear)
+	}
+	if len(fit.Candidates) != len(volumeModels) {
+		t.Fatalf("%d candidates, want %d", len(fit.Candidates), len(volumeModels))
+	}
+	for i := 1; i < len(fit.Candidates); i++ {
+		if lessNaNLast(fit.Candidates[i].MASE, fit.Candidates[i-1].MASE) {
+			t.Errorf("candidates not ordered by MASE: %+v", fit.Candidates)
+		}
+	}
+	// A seasonal naive forecast of a line is a week behind
+	for _, c := range fit.Candidates {
+		if c.Model == VolumeModelSeasonalNaive && c.MASE < 1 {
+			t.Errorf("seasonal naive MASE = %v, want above 1", c.MASE)
+		}
+	}
+}
+
+func TestSelectVolumeModelFallsBackToHoltWinters(t *testing.T) {
+	// An exactly repeating week has no naive error to scale MASE by
+	week := []float64{20, 35, 50, 45, 60, 90, 30}
+	repeating := make([]float64, 84)
+	for i := range repeating {
+		repeating[i] = week[i%7]
+	}
+	// Three weeks are too short for a 14-day backtest fold
+	short := noisyLine()[:21]
+
+	for name, volumes := range map[string][]float64{"repeating": repeating, "short": short} {
+		_, fit, err := NewPredictionEngine().selectVolumeModel(dailySeries(volumes), volumes, 14)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if fit.Model != VolumeModelHoltWinters || fit.Selection != SelectionDefault {
+			t.Errorf("%s: selected %s (%s), want Holt-Winters by default", name, fit.Model, fit.Selection)
+		}
+	}
+}
+
+func TestEnsembleVolumeModelWeights(t *testing.T) {
+	volumes := noisyLine()
+	engine := NewPredictionEngine()
+	forecaster, fit, err := engine.ensembleVolumeModel(dailySeries(volumes), volumes, 14)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fit.Model != ModelEnsemble || fit.Selection != SelectionEnsemble || !math.IsNaN(fit.AIC) {
+		t.Fatalf("fit = %+v", fit)
+	}
+
+	// The members are the ensembleSize models with the lowest MASE, weighted
+	// by inverse MASE
+	scoring := engine.scoreVolumeModels(dailySeries(volumes), volumes, 14)
+	members := scoring.eligible()[:ensembleSize]
+	if members[0].Model != VolumeModelLinear {
+		t.Errorf("best member %s, want %s", members[0].Model, VolumeModelLinear)
+	}
+	total := 0.0
+	for _, m := range members {
+		total += 1 / m.MASE
+	}
+	sum := 0.0
+	for _, m := range members {
+		want := (1 / m.MASE) / total
+		if got := fit.Parameters[m.Model]; math.Abs(got-want) > 1e-9 {
+			t.Errorf("weight of %s = %v, want %v", m.Model, got, want)
+		}
+		sum += fit.Parameters[m.Model]
+	}
+	if math.Abs(sum-1) > 1e-9 || len(fit.Parameters) != ensembleSize {
+		t.Errorf("weights = %v, want %d summing to 1", fit.Parameters, ensembleSize)
+	}
+	for _, c := range fit.Candidates {
+		if c.Weight != fit.Parameters[c.Model] {
+			t.Errorf("candidate %s weight = %v, want %v", c.Model, c.Weight, fit.Parameters[c.Model])
+		}
+	}
+	if !strings.HasPrefix(fit.Spec, "Ensemble(") {
+		t.Errorf("spec = %s", fit.Spec)
+	}
+
+	// The forecast is the weighted forecast of the members
+	forecasts, _ := forecaster.Forecast(7)
+	want := make([]float64, 7)
+	for _, m := range members {
+		values, _ := scoring.fitted[m.Model].Forecast(7)
+		for h := range want {
+			want[h] += fit.Parameters[m.Model] * values[h]
+		}
+	}
+	for h := range want {
+		if math.Abs(forecasts[h]-want[h]) > 1e-6 {
+			t.Errorf("forecast %d = %v, want %v", h, forecasts[h], want[h])
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package trend
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/trendscout/backend/internal/models"
+	"github.com/trendscout/backend/internal/timezone"
+)
+
+// scoreLookbackDays is how far back unscored forecast days are looked for.
+// Days without a trend record by then are left unscored.
+const scoreLookbackDays = 60
+
+// NewForecastRecord converts an issued prediction to a forecast to persist.
+// The issue date is the day before the first predicted day.
+func NewForecastRecord(keywordID int, metric string, sources []string, requestedModel string, fit *VolumeFit, predictions []EnhancedPredictionResult) *models.Forecast {
+	forecast := &models.Forecast{
+		KeywordID:      keywordID,
+		Metric:         metric,
+		Sources:        sources,
+		RequestedModel: requestedModel,
+		Model:          fit.Model,
+		Spec:           fit.Spec,
+		Horizon:        len(predictions),
+	}
+	if len(predictions) > 0 {
+		forecast.IssueDate = predictions[0].Date.AddDate(0, 0, -1)
+	}
+
+	for i, pred := range predictions {
+		point := models.ForecastPoint{
+			TargetDate: pred.Date,
+			Step:       i + 1,
+			Value:      pred.Volume,
+			StdErr:     pred.VolumeStdErr,
+		}
+		for _, interval := range pred.Intervals {
+			point.Intervals = append(point.Intervals, models.ForecastInterval{
+				Level: interval.Level,
+				Lower: interval.Lower,
+				Upper: interval.Upper,
+			})
+		}
+		forecast.Points = append(forecast.Points, point)
+	}
+
+	return forecast
+}
+
+// ScoreForecasts records the actual values of forecast days that have ended
+// and been collected, returning the number of days scored
+func (s *Service) ScoreForecasts(ctx context.Context) (int, error) {
+	today := timezone.CollectionDay(time.Now())
+	forecasts, err := models.GetDueForecasts(ctx, today.AddDate(0, 0, -scoreLookbackDays), today)
+	if err != nil {
+		return 0, err
+	}
+
+	// Forecasts of the same series share one lookup of the actual values
+	actualsBySeries := map[string]map[string]float64{}
+	scored := 0
+	for _, f := range forecasts {
+		key := fmt.Sprintf("%d/%s/%s", f.KeywordID, f.Metric, strings.Join(f.Sources, ","))
+		actuals, ok := actualsBySeries[key]
+		if !ok {
+			records, err := models.GetMetricTrendRecords(ctx, f.KeywordID, f.Metric,
+				today.AddDate(0, 0, -scoreLookbackDays), today.Add(-time.Nanosecond), f.Sources)
+			if err != nil {
+				return scored, fmt.Errorf("failed to get actual values of keyword %d: %w", f.KeywordID, err)
+			}
+			actuals = make(map[string]float64, len(records))
+			for _, r := range records {
+				actuals[dayKey(r.Date)] = r.MetricValue(f.Metric)
+			}
+			actualsBySeries[key] = actuals
+		}
+
+		values := map[time.Time]float64{}
+		for _, p := range f.Points {
+			if actual, ok := actuals[dayKey(p.TargetDate)]; ok {
+				values[p.TargetDate] = actual
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+		if err := models.SetForecastActuals(ctx, f.ID, values); err != nil {
+			return scored, fmt.Errorf("failed to score forecast %d: %w", f.ID, err)
+		}
+		scored += len(values)
+	}
+
+	return scored, nil
+}
+
+// dayKey identifies the collection day of t
+func dayKey(t time.Time) string {
+	return t.In(timezone.Collection()).Format("2006-01-02")
+}
+
+// ForecastAccuracy is the accuracy of the scored forecasts of one model
+type ForecastAccuracy struct {
+	Model    string
+	Points   int     // scored forecast days
+	MAE      float64 // NaN without scored days
+	MAPE     float64 // in percent, over the non-zero actual values; NaN when there are none
+	SMAPE    float64 // in percent
+	Coverage []BacktestCoverage
+	Rolling  []RollingAccuracy // by target date, oldest first
+}
+
+// RollingAccuracy is the accuracy of a model over the forecast days in the
+// window ending on Date
+type RollingAccuracy struct {
+	Date   time.Time
+	Points int
+	MAE    float64
+	MAPE   float64 // NaN when all actual values in the window are zero
+}
+
+// forecastError is a scored forecast day
+type forecastError struct {
+	date    time.Time
+	abs     float64
+	ape     float64 // NaN when the actual value is zero
+	sape    float64
+	covered map[float64]bool // by interval level
+}
+
+// MeasureForecastAccuracy computes the accuracy of scored forecasts per
+// volume model, with a rolling accuracy over windows of the given days.
+// Models are ordered by MAE, best first.
+func MeasureForecastAccuracy(forecasts []models.Forecast, windowDays int) []ForecastAccuracy {
+	errorsByModel := map[string][]forecastError{}
+	for _, f := range forecasts {
+		for _, p := range f.Points {
+			if p.Actual == nil {
+				continue
+			}
+			actual := *p.Actual
+			e := forecastError{date: p.TargetDate, abs: math.Abs(p.Value - actual), ape: math.NaN()}
+			if actual != 0 {
+				e.ape = e.abs / math.Abs(actual)
+			}
+			if denominator := math.Abs(p.Value) + math.Abs(actual); denominator > 0 {
+				e.sape = 2 * e.abs / denominator
+			}
+			e.covered = map[float64]bool{}
+			for _, interval := range p.Intervals {
+				e.covered[interval.Level] = actual >= interval.Lower && actual <= interval.Upper
+			}
+			errorsByModel[f.Model] = append(errorsByModel[f.Model], e)
+		}
+	}
+
+	var accuracies []ForecastAccuracy
+	for model, errs := range errorsByModel {
+		sort.SliceStable(errs, func(i, j int) bool { return errs[i].date.Before(errs[j].date) })
+
+		accuracy := ForecastAccuracy{Model: model, Points: len(errs)}
+		accuracy.MAE, accuracy.MAPE, accuracy.SMAPE = summarizeErrors(errs)
+		accuracy.Coverage = intervalCoverage(errs)
+
+		// One rolling point per target date, over the days in (date - window, date]
+		start := 0
+		for i, e := range errs {
+			if i+1 < len(errs) && errs[i+1].date.Equal(e.date) {
+				continue
+			}
+			for errs[start].date.Before(e.date.AddDate(0, 0, -windowDays+1)) {
+				start++
+			}
+			window := errs[start : i+1]
+			mae, mape, _ := summarizeErrors(window)
+			accuracy.Rolling = append(accuracy.Rolling, RollingAccuracy{Date: e.date, Points: len(window), MAE: mae, MAPE: mape})
+		}
+
+		accuracies = append(accuracies, accuracy)
+	}
+
+	sort.Slice(accuracies, func(i, j int) bool {
+		a, b := accuracies[i].MAE, accuracies[j].MAE
+		if a != b && !(math.IsNaN(a) && math.IsNaN(b)) {
+			return lessNaNLast(a, b)
+		}
+		return accuracies[i].Model < accuracies[j].Model
+	})
+	return accuracies
+}
+
+// summarizeErrors returns the MAE, MAPE and sMAPE of scored forecast days
+func summarizeErrors(errs []forecastError) (mae, mape, smape float64) {
+	var absSum, apeSum, sapeSum float64
+	apeCount := 0
+	for _, e := range errs {
+		absSum += e.abs
+		sapeSum += e.sape
+		if !math.IsNaN(e.ape) {
+			apeSum += e.ape
+			apeCount++
+		}
+	}
+
+	n := float64(len(errs))
+	mape = math.NaN()
+	if apeCount > 0 {
+		mape = 100 * apeSum / float64(apeCount)
+	}
+	return absSum / n, mape, 100 * sapeSum / n
+}
+
+// intervalCoverage returns the share of scored days inside the prediction
+// interval of each level, in ascending order of level
+func intervalCoverage(errs []forecastError) []BacktestCoverage {
+	covered := map[float64]int{}
+	total := map[float64]int{}
+	for _, e := range errs {
+		for level, inside := range e.covered {
+			total[level]++
+			if inside {
+				covered[level]++
+			}
+		}
+	}
+
+	var coverage []BacktestCoverage
+	for level, n := range total {
+		coverage = append(coverage, BacktestCoverage{Level: level, Coverage: float64(covered[level]) / float64(n)})
+	}
+	sort.Slice(coverage, func(i, j int) bool { return coverage[i].Level < coverage[j].Level })
+	return coverage
+}
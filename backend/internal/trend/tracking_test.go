@@ -0,0 +1,101 @@
+package trend
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/trendscout/backend/internal/models"
+)
+
+func TestMeasureForecastAccuracy(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+	actual := func(v float64) *float64 { return &v }
+	interval := func(level, lower, upper float64) models.ForecastInterval {
+		return models.ForecastInterval{Level: level, Lower: lower, Upper: upper}
+	}
+
+	forecasts := []models.Forecast{
+		{Model: VolumeModelHoltWinters, IssueDate: day(1), Points: []models.ForecastPoint{
+			{TargetDate: day(2), Value: 10, Actual: actual(12), Intervals: []models.ForecastInterval{interval(0.8, 9, 11)}},
+			{TargetDate: day(3), Value: 10, Actual: actual(8), Intervals: []models.ForecastInterval{interval(0.8, 9, 11)}},
+		}},
+		// The next day's forecast of the same model shares a target date
+		{Model: VolumeModelHoltWinters, IssueDate: day(2), Points: []models.ForecastPoint{
+			{TargetDate: day(3), Value: 9, Actual: actual(8), Intervals: []models.ForecastInterval{interval(0.8, 7, 11), interval(0.95, 6, 12)}},
+			{TargetDate: day(4), Value: 10, Actual: actual(0), Intervals: []models.ForecastInterval{interval(0.8, 5, 15), interval(0.95, 0, 20)}},
+			{TargetDate: day(5), Value: 10}, // not scored yet
+		}},
+		{Model: VolumeModelLinear, IssueDate: day(1), Points: []models.ForecastPoint{
+			{TargetDate: day(2), Value: 5, Actual: actual(5)},
+		}},
+		// Models whose errors are undefined go last, by name
+		{Model: VolumeModelNaive, IssueDate: day(1), Points: []models.ForecastPoint{
+			{TargetDate: day(2), Value: math.NaN(), Actual: actual(5)},
+		}},
+		{Model: VolumeModelARIMA, IssueDate: day(1), Points: []models.ForecastPoint{
+			{TargetDate: day(2), Value: math.NaN(), Actual: actual(5)},
+		}},
+		{Model: VolumeModelCroston, IssueDate: day(1), Points: []models.ForecastPoint{
+			{TargetDate: day(2), Value: 3},
+		}},
+	}
+
+	accuracies := MeasureForecastAccuracy(forecasts, 2)
+	order := []string{VolumeModelLinear, VolumeModelHoltWinters, VolumeModelARIMA, VolumeModelNaive}
+	if len(accuracies) != len(order) {
+		t.Fatalf("%d models, want %d: %+v", len(accuracies), len(order), accuracies)
+	}
+	for i, model := range order {
+		if accuracies[i].Model != model {
+			t.Errorf("model %d = %s, want %s", i, accuracies[i].Model, model)
+		}
+	}
+
+	// Errors of 2, 2 and 1 against actual values 12, 8 and 8, and of 10
+	// against a zero actual value that is left out of MAPE
+	hw := accuracies[1]
+	checks := []struct {
+		name      string
+		got, want float64
+	}{
+		{"MAE", hw.MAE, 15.0 / 4},
+		{"MAPE", hw.MAPE, 100 * (2.0/12 + 2.0/8 + 1.0/8) / 3},
+		{"sMAPE", hw.SMAPE, 100 * (4.0/22 + 4.0/18 + 2.0/17 + 2) / 4},
+		{"80% coverage", hw.Coverage[0].Coverage, 0.25},
+		// Only the days forecast with a 95% interval count towards its coverage
+		{"95% coverage", hw.Coverage[1].Coverage, 1},
+	}
+	for _, c := range checks {
+		if !closeTo(c.got, c.want) {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+	if hw.Points != 4 || len(hw.Coverage) != 2 || hw.Coverage[0].Level != 0.8 || hw.Coverage[1].Level != 0.95 {
+		t.Errorf("accuracy = %+v", hw)
+	}
+
+	// One rolling point per target date over the days in (date - 2, date]
+	rolling := []RollingAccuracy{
+		{Date: day(2), Points: 1, MAE: 2, MAPE: 100 * 2.0 / 12},
+		{Date: day(3), Points: 3, MAE: 5.0 / 3, MAPE: 100 * (2.0/12 + 2.0/8 + 1.0/8) / 3},
+		{Date: day(4), Points: 3, MAE: 13.0 / 3, MAPE: 100 * (2.0/8 + 1.0/8) / 2},
+	}
+	if len(hw.Rolling) != len(rolling) {
+		t.Fatalf("rolling = %+v, want %+v", hw.Rolling, rolling)
+	}
+	for i, want := range rolling {
+		got := hw.Rolling[i]
+		if !got.Date.Equal(want.Date) || got.Points != want.Points || !closeTo(got.MAE, want.MAE) || !closeTo(got.MAPE, want.MAPE) {
+			t.Errorf("rolling %d = %+v, want %+v", i, got, want)
+		}
+	}
+
+	// A window of all-zero actual values has no MAPE
+	zero := MeasureForecastAccuracy([]models.Forecast{{Model: VolumeModelNaive, Points: []models.ForecastPoint{
+		{TargetDate: day(2), Value: 1, Actual: actual(0)},
+	}}}, 7)
+	if !math.IsNaN(zero[0].MAPE) || !math.IsNaN(zero[0].Rolling[0].MAPE) || zero[0].MAE != 1 {
+		t.Errorf("zero actuals: %+v", zero[0])
+	}
+}
@@ -0,0 +1,66 @@
+package views
+
+import (
+	"time"
+
+	"github.com/trendscout/backend/internal/models"
+)
+
+// AnomalyListResponse represents the anomalies detected in a keyword's metrics
+type AnomalyListResponse struct {
+	KeywordID int                `json:"keyword_id"`
+	StartDate string             `json:"start_date"`
+	EndDate   string             `json:"end_date"`
+	Anomalies []*AnomalyResponse `json:"anomalies"` // newest first
+	Count     int                `json:"count"`
+}
+
+// AnomalyResponse represents a spike or drop with the items that explain it
+type AnomalyResponse struct {
+	ID         int                  `json:"id"`
+	Metric     string               `json:"metric"`
+	Date       string               `json:"date"`
+	Kind       string               `json:"kind"`     // spike or drop
+	Severity   string               `json:"severity"` // low, medium or high
+	Method     string               `json:"method"`   // mad: robust z-score of the seasonal decomposition residual; iqr: quartile fences
+	Value      float64              `json:"value"`
+	Expected   float64              `json:"expected"`
+	Score      float64              `json:"score"` // negative for drops
+	DetectedAt time.Time            `json:"detected_at"`
+	Items      []*TrendItemResponse `json:"items"` // most contributing first
+}
+
+// NewAnomalyListResponse creates an anomaly list response, highlighting
+// keyword in the item snippets and showing dates in loc
+func NewAnomalyListResponse(keywordID int, keyword string, start, end time.Time, anomalies []models.Anomaly, loc *time.Location) *AnomalyListResponse {
+	response := &AnomalyListResponse{
+		KeywordID: keywordID,
+		StartDate: start.In(loc).Format("2006-01-02"),
+		EndDate:   end.In(loc).Format("2006-01-02"),
+		Anomalies: []*AnomalyResponse{},
+		Count:     len(anomalies),
+	}
+
+	terms := []string{keyword}
+	for _, a := range anomalies {
+		anomaly := &AnomalyResponse{
+			ID:         a.ID,
+			Metric:     a.Metric,
+			Date:       a.Date.In(loc).Format("2006-01-02"),
+			Kind:       a.Kind,
+			Severity:   a.Severity,
+			Method:     a.Method,
+			Value:      a.Value,
+			Expected:   a.Expected,
+			Score:      a.Score,
+			DetectedAt: a.DetectedAt,
+			Items:      []*TrendItemResponse{},
+		}
+		for _, item := range a.Items {
+			anomaly.Items = append(anomaly.Items, newTrendItemResponse(item, terms, loc))
+		}
+		response.Anomalies = append(response.Anomalies, anomaly)
+	}
+
+	return response
+}
@@ -0,0 +1,132 @@
+package views
+
+import (
+	"time"
+
+	"github.com/trendscout/backend/internal/analysis"
+	"github.com/trendscout/backend/internal/trend"
+)
+
+// ChangepointResponse represents the regimes detected in a trend series
+type ChangepointResponse struct {
+	KeywordID     int                      `json:"keyword_id"`
+	Metric        string                   `json:"metric"`
+	Source        []string                 `json:"source,omitempty"` // source filter, if any
+	Method        string                   `json:"method"`
+	Penalty       float64                  `json:"penalty"`        // per changepoint, in units of the noise variance
+	NoiseStdDev   float64                  `json:"noise_std_dev"`  // of the seasonally adjusted series
+	Changepoints  []*ChangepointData       `json:"changepoints"`   // oldest first
+	Segments      []*RegimeSegmentResponse `json:"segments"`       // oldest first
+	CurrentRegime *RegimeSegmentResponse   `json:"current_regime"` // null without data
+	CUSUM         *CUSUMResponse           `json:"cusum"`
+}
+
+// ChangepointData represents the start of a new regime
+type ChangepointData struct {
+	Date        string  `json:"date"`      // first day of the new regime
+	Direction   string  `json:"direction"` // up or down: how the new regime departs from the previous one
+	LevelBefore float64 `json:"level_before"`
+	LevelAfter  float64 `json:"level_after"`
+	SlopeBefore float64 `json:"slope_before"` // per day
+	SlopeAfter  float64 `json:"slope_after"`
+}
+
+// RegimeSegmentResponse represents a regime with its own level and slope
+type RegimeSegmentResponse struct {
+	StartDate      string  `json:"start_date"`
+	EndDate        string  `json:"end_date"`
+	Points         int     `json:"points"`
+	Mean           float64 `json:"mean"`
+	Slope          float64 `json:"slope"` // per day
+	TrendDirection string  `json:"trend_direction"`
+}
+
+// CUSUMResponse represents the online CUSUM detector over the series
+type CUSUMResponse struct {
+	Alarms    []*CUSUMAlarmResponse `json:"alarms"`
+	Reference *float64              `json:"reference"` // current reference level; null while warming up
+	Upper     float64               `json:"upper"`     // statistics in standard deviations; an alarm is raised above threshold
+	Lower     float64               `json:"lower"`
+	Threshold float64               `json:"threshold"`
+}
+
+// CUSUMAlarmResponse represents a level shift signalled by the CUSUM detector
+type CUSUMAlarmResponse struct {
+	Date      string  `json:"date"`       // day the alarm was raised
+	StartDate string  `json:"start_date"` // estimated start of the shift
+	Direction string  `json:"direction"`
+	Reference float64 `json:"reference"` // level before the shift
+}
+
+// NewChangepointResponse creates a changepoint response with dates in loc
+func NewChangepointResponse(keywordID int, metric string, sources []string, method string, regimes *trend.RegimeAnalysis, loc *time.Location) *ChangepointResponse {
+	date := func(i int) string {
+		return regimes.Series[i].Date.In(loc).Format("2006-01-02")
+	}
+	result := regimes.Result
+
+	response := &ChangepointResponse{
+		KeywordID:    keywordID,
+		Metric:       metric,
+		Source:       sources,
+		Method:       method,
+		Penalty:      result.Penalty,
+		NoiseStdDev:  result.NoiseStdDev,
+		Changepoints: []*ChangepointData{},
+		Segments:     []*RegimeSegmentResponse{},
+		CUSUM: &CUSUMResponse{
+			Alarms:    []*CUSUMAlarmResponse{},
+			Reference: finiteValue(regimes.CUSUM.Reference),
+			Upper:     regimes.CUSUM.Upper,
+			Lower:     regimes.CUSUM.Lower,
+			Threshold: regimes.CUSUM.Threshold,
+		},
+	}
+
+	for _, segment := range result.Segments {
+		response.Segments = append(response.Segments, &RegimeSegmentResponse{
+			StartDate:      date(segment.Start),
+			EndDate:        date(segment.End - 1),
+			Points:         segment.Len(),
+			Mean:           segment.Mean,
+			Slope:          segment.Slope,
+			TrendDirection: trend.SlopeDirection(segment.Slope, segment.Mean),
+		})
+	}
+	if len(response.Segments) > 0 {
+		response.CurrentRegime = response.Segments[len(response.Segments)-1]
+	}
+
+	for i := 1; i < len(result.Segments); i++ {
+		before, after := result.Segments[i-1], result.Segments[i]
+		response.Changepoints = append(response.Changepoints, &ChangepointData{
+			Date:        date(after.Start),
+			Direction:   departure(before, after),
+			LevelBefore: before.ValueAt(after.Start),
+			LevelAfter:  after.ValueAt(after.Start),
+			SlopeBefore: before.Slope,
+			SlopeAfter:  after.Slope,
+		})
+	}
+
+	for _, alarm := range regimes.Alarms {
+		response.CUSUM.Alarms = append(response.CUSUM.Alarms, &CUSUMAlarmResponse{
+			Date:      date(alarm.Index),
+			StartDate: date(alarm.Start),
+			Direction: alarm.Direction,
+			Reference: alarm.Reference,
+		})
+	}
+
+	return response
+}
+
+// departure tells whether a regime ends above or below where the previous
+// regime's line would have been, covering both level and slope changes
+func departure(before, after analysis.Segment) string {
+	last := after.End - 1
+	if after.ValueAt(last) >= before.ValueAt(last) {
+		return analysis.DirectionUp
+	}
+	return analysis.DirectionDown
+}
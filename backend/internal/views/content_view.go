@@ -0,0 +1,178 @@
+package views
+
+import (
+	"html"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/trendscout/backend/internal/models"
+)
+
+// snippetRadius is the number of characters kept on each side of the first match
+const snippetRadius = 80
+
+// ContentSearchResultResponse represents a single search hit in API responses
+type ContentSearchResultResponse struct {
+	Type        string    `json:"type"`
+	ID          string    `json:"id"`
+	KeywordID   int       `json:"keyword_id"`
+	Source      string    `json:"source"`
+	Title       string    `json:"title,omitempty"`
+	Snippet     string    `json:"snippet"` // HTML-escaped, matches wrapped in <mark>
+	URL         string    `json:"url,omitempty"`
+	ImageURL    string    `json:"image_url,omitempty"`
+	Language    string    `json:"language,omitempty"`
+	Provenance  string    `json:"provenance,omitempty"`
+	PublishedAt time.Time `json:"published_at"`
+	Score       float64   `json:"score,omitempty"`
+}
+
+// ContentSearchResponse represents a page of content search results
+type ContentSearchResponse struct {
+	Query      string                         `json:"query"`
+	Results    []*ContentSearchResultResponse `json:"results"`
+	Count      int                            `json:"count"`
+	NextCursor string                         `json:"next_cursor,omitempty"`
+}
+
+// NewContentSearchResponse creates a search response with highlighted snippets
+func NewContentSearchResponse(query string, results []models.ContentSearchResult, next *models.ContentCursor) *ContentSearchResponse {
+	terms := searchTerms(query)
+
+	responses := make([]*ContentSearchResultResponse, len(results))
+	for i, r := range results {
+		responses[i] = &ContentSearchResultResponse{
+			Type:        r.Type,
+			ID:          r.ID,
+			KeywordID:   r.KeywordID,
+			Source:      r.Source,
+			Title:       r.Title,
+			Snippet:     HighlightSnippet(r.Content, terms),
+			URL:         r.URL,
+			ImageURL:    r.ImageURL,
+			Language:    r.Language,
+			Provenance:  r.Provenance,
+			PublishedAt: r.PublishedAt,
+			Score:       r.Score,
+		}
+	}
+
+	response := &ContentSearchResponse{
+		Query:   query,
+		Results: responses,
+		Count:   len(responses),
+	}
+	if next != nil {
+		response.NextCursor = next.Encode()
+	}
+	return response
+}
+
+// searchTerms splits a query into the terms to highlight, dropping
+// quotes and negated terms
+func searchTerms(query string) []string {
+	var terms []string
+	for _, field := range strings.Fields(query) {
+		if strings.HasPrefix(field, "-") {
+			continue
+		}
+		field = strings.Trim(field, `"`)
+		if field != "" {
+			terms = append(terms, field)
+		}
+	}
+	return terms
+}
+
+// HighlightSnippet returns an HTML-escaped excerpt of text centred on the
+// first matching term, with every case-insensitive match wrapped in <mark>.
+// Without a match the start of the text is returned.
+func HighlightSnippet(text string, terms []string) string {
+	runes := []rune(text)
+	lower := []rune(strings.ToLower(text))
+	if len(lower) != len(runes) {
+		// Case folding changed the length; fall back to exact matching
+		lower = runes
+	}
+
+	lowerTerms := make([][]rune, 0, len(terms))
+	for _, t := range terms {
+		lowerTerms = append(lowerTerms, []rune(strings.ToLower(t)))
+	}
+
+	// Find match ranges
+	type span struct{ start, end int }
+	var matches []span
+	for i := 0; i < len(lower); {
+		matched := 0
+		for _, t := range lowerTerms {
+			if len(t) > matched && hasRunePrefix(lower[i:], t) {
+				matched = len(t)
+			}
+		}
+		if matched > 0 {
+			matches = append(matches, span{i, i + matched})
+			i += matched
+		} else {
+			i++
+		}
+	}
+
+	// Choose the excerpt window
+	start, end := 0, len(runes)
+	if len(matches) > 0 {
+		start = matches[0].start - snippetRadius
+		end = matches[0].end + snippetRadius
+	} else {
+		end = 2 * snippetRadius
+	}
+	if start < 0 {
+		start = 0
+	}
+	if end > len(runes) {
+		end = len(runes)
+	}
+	// Avoid cutting a word in half where the text is space-separated
+	for i := start; i > 0 && start-i < 15; i-- {
+		if unicode.IsSpace(runes[i-1]) {
+			start = i
+			break
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := start
+	for _, m := range matches {
+		if m.end <= start || m.start >= end {
+			continue
+		}
+		ms, me := max(m.start, start), min(m.end, end)
+		b.WriteString(html.EscapeString(string(runes[pos:ms])))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(string(runes[ms:me])))
+		b.WriteString("</mark>")
+		pos = me
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:end])))
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// hasRunePrefix reports whether s begins with prefix
+func hasRunePrefix(s, prefix []rune) bool {
+	if len(prefix) > len(s) {
+		return false
+	}
+	for i, r := range prefix {
+		if s[i] != r {
+			return false
+		}
+	}
+	return true
+}
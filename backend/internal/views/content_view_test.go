@@ -0,0 +1,97 @@
+package views
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSearchTerms(t *testing.T) {
+	got := searchTerms(`"quiet luxury" -logo  loafers ""`)
+	want := []string{"quiet", "luxury", "loafers"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("searchTerms = %q, want %q", got, want)
+	}
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		terms []string
+		want  string
+	}{
+		{
+			name:  "case-insensitive",
+			text:  "Ballet flats and BALLET pink",
+			terms: []string{"ballet"},
+			want:  "<mark>Ballet</mark> flats and <mark>BALLET</mark> pink",
+		},
+		{
+			name:  "longest overlapping term wins",
+			text:  "barrel jeans, barrel-leg jeans",
+			terms: []string{"barrel", "barrel-leg"},
+			want:  "<mark>barrel</mark> jeans, <mark>barrel-leg</mark> jeans",
+		},
+		{
+			name:  "HTML is escaped inside and outside marks",
+			text:  `<b>"Tom & Jerry"</b> tee`,
+			terms: []string{"tom & jerry"},
+			want:  "&lt;b&gt;&#34;<mark>Tom &amp; Jerry</mark>&#34;&lt;/b&gt; tee",
+		},
+		{
+			name:  "Japanese",
+			text:  "今年の秋はバレエコアが流行しています",
+			terms: []string{"バレエコア"},
+			want:  "今年の秋は<mark>バレエコア</mark>が流行しています",
+		},
+		{
+			// Runes whose lower case is encoded in fewer bytes must not shift the marks
+			name:  "case folding changes the byte length",
+			text:  "İSTANBUL street style in İstanbul",
+			terms: []string{"istanbul"},
+			want:  "<mark>İSTANBUL</mark> street style in <mark>İstanbul</mark>",
+		},
+		{
+			name:  "no match returns the start",
+			text:  "Mesh flats",
+			terms: []string{"loafers"},
+			want:  "Mesh flats",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := HighlightSnippet(tt.text, tt.terms); got != tt.want {
+			t.Errorf("%s: HighlightSnippet = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestHighlightSnippetPastRadius(t *testing.T) {
+	prefix := strings.Repeat("word ", 40) // 200 characters
+	suffix := strings.Repeat(" tail", 40)
+	got := HighlightSnippet(prefix+"Mary Janes"+suffix, []string{"mary janes"})
+
+	// The excerpt starts at a word boundary before the match and is
+	// elided on both sides
+	if !strings.HasPrefix(got, "…word ") {
+		t.Errorf("snippet should start with an ellipsis at a word boundary: %q", got)
+	}
+	if !strings.HasSuffix(got, "…") {
+		t.Errorf("snippet should end with an ellipsis: %q", got)
+	}
+	if !strings.Contains(got, "<mark>Mary Janes</mark>") {
+		t.Errorf("snippet should mark the match: %q", got)
+	}
+	before := strings.Index(got, "<mark>")
+	if n := len([]rune(got[len("…"):before])); n < snippetRadius || n > snippetRadius+15 {
+		t.Errorf("snippet keeps %d characters before the match, want %d to %d", n, snippetRadius, snippetRadius+15)
+	}
+
+	// Japanese text has no spaces to back off to
+	ja := strings.Repeat("あ", 200) + "厚底" + strings.Repeat("い", 200)
+	got = HighlightSnippet(ja, []string{"厚底"})
+	want := "…" + strings.Repeat("あ", snippetRadius) + "<mark>厚底</mark>" + strings.Repeat("い", snippetRadius) + "…"
+	if got != want {
+		t.Errorf("Japanese snippet = %q, want %q", got, want)
+	}
+}
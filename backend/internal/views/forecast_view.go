@@ -0,0 +1,155 @@
+package views
+
+import (
+	"math"
+	"time"
+
+	"github.com/trendscout/backend/internal/models"
+	"github.com/trendscout/backend/internal/trend"
+)
+
+// ForecastOverlayResponse represents past forecasts overlaid on the realized
+// values, with the accuracy of each model
+type ForecastOverlayResponse struct {
+	KeywordID int                         `json:"keyword_id"`
+	Metric    string                      `json:"metric"`
+	Source    []string                    `json:"source,omitempty"` // source filter, if any
+	StartDate string                      `json:"start_date"`       // first issue date included
+	EndDate   string                      `json:"end_date"`
+	Window    int                         `json:"window"` // days of the rolling accuracy
+	Actuals   []*ActualValueResponse      `json:"actuals"`
+	Forecasts []*IssuedForecastResponse   `json:"forecasts"`
+	Accuracy  []*ForecastAccuracyResponse `json:"accuracy"` // best MAE first
+}
+
+// ActualValueResponse represents a realized value of the metric
+type ActualValueResponse struct {
+	Date  string  `json:"date"`
+	Value float64 `json:"value"`
+}
+
+// IssuedForecastResponse represents a persisted forecast
+type IssuedForecastResponse struct {
+	ID             int                      `json:"id"`
+	RequestedModel string                   `json:"requested_model"`
+	Model          string                   `json:"model"`
+	Spec           string                   `json:"spec"`
+	IssueDate      string                   `json:"issue_date"`
+	IssuedAt       time.Time                `json:"issued_at"`
+	Horizon        int                      `json:"horizon"`
+	Points         []*ForecastPointResponse `json:"points"`
+}
+
+// ForecastPointResponse represents a forecast day and, once scored, its actual value
+type ForecastPointResponse struct {
+	Date      string                        `json:"date"`
+	Step      int                           `json:"step"`
+	Value     float64                       `json:"value"`
+	StdErr    float64                       `json:"std_err"`
+	Intervals []*PredictionIntervalResponse `json:"intervals"`
+	Actual    *float64                      `json:"actual"` // null until scored
+	Error     *float64                      `json:"error"`  // forecast minus actual
+}
+
+// ForecastAccuracyResponse represents the accuracy of the scored forecasts of a model
+type ForecastAccuracyResponse struct {
+	Model    string                      `json:"model"`
+	Points   int                         `json:"points"`
+	MAE      *float64                    `json:"mae"`
+	MAPE     *float64                    `json:"mape"`  // in percent
+	SMAPE    *float64                    `json:"smape"` // in percent
+	Coverage []*BacktestCoverageResponse `json:"coverage"`
+	Rolling  []*RollingAccuracyResponse  `json:"rolling"`
+}
+
+// RollingAccuracyResponse represents the accuracy over the window ending on a date
+type RollingAccuracyResponse struct {
+	Date   string   `json:"date"`
+	Points int      `json:"points"`
+	MAE    *float64 `json:"mae"`
+	MAPE   *float64 `json:"mape"` // in percent
+}
+
+// NewForecastOverlayResponse creates a forecast overlay response with dates in loc
+func NewForecastOverlayResponse(keywordID int, metric string, sources []string, start, end time.Time, window int,
+	actuals []models.TrendRecord, forecasts []models.Forecast, accuracies []trend.ForecastAccuracy, loc *time.Location) *ForecastOverlayResponse {
+	response := &ForecastOverlayResponse{
+		KeywordID: keywordID,
+		Metric:    metric,
+		Source:    sources,
+		StartDate: start.In(loc).Format("2006-01-02"),
+		EndDate:   end.In(loc).Format("2006-01-02"),
+		Window:    window,
+		Actuals:   []*ActualValueResponse{},
+		Forecasts: []*IssuedForecastResponse{},
+		Accuracy:  []*ForecastAccuracyResponse{},
+	}
+
+	for _, r := range actuals {
+		response.Actuals = append(response.Actuals, &ActualValueResponse{
+			Date:  r.Date.In(loc).Format("2006-01-02"),
+			Value: r.MetricValue(metric),
+		})
+	}
+
+	for _, f := range forecasts {
+		issued := &IssuedForecastResponse{
+			ID:             f.ID,
+			RequestedModel: f.RequestedModel,
+			Model:          f.Model,
+			Spec:           f.Spec,
+			IssueDate:      f.IssueDate.In(loc).Format("2006-01-02"),
+			IssuedAt:       f.IssuedAt,
+			Horizon:        f.Horizon,
+		}
+		for _, p := range f.Points {
+			point := &ForecastPointResponse{
+				Date:      p.TargetDate.In(loc).Format("2006-01-02"),
+				Step:      p.Step,
+				Value:     p.Value,
+				StdErr:    p.StdErr,
+				Intervals: make([]*PredictionIntervalResponse, len(p.Intervals)),
+				Actual:    p.Actual,
+			}
+			for i, interval := range p.Intervals {
+				point.Intervals[i] = &PredictionIntervalResponse{
+					Level: math.Round(interval.Level*1000) / 10,
+					Lower: interval.Lower,
+					Upper: interval.Upper,
+				}
+			}
+			if p.Actual != nil {
+				diff := p.Value - *p.Actual
+				point.Error = &diff
+			}
+			issued.Points = append(issued.Points, point)
+		}
+		response.Forecasts = append(response.Forecasts, issued)
+	}
+
+	for _, a := range accuracies {
+		accuracy := &ForecastAccuracyResponse{
+			Model:    a.Model,
+			Points:   a.Points,
+			MAE:      finiteValue(a.MAE),
+			MAPE:     finiteValue(a.MAPE),
+			SMAPE:    finiteValue(a.SMAPE),
+			Coverage: make([]*BacktestCoverageResponse, len(a.Coverage)),
+			Rolling:  make([]*RollingAccuracyResponse, len(a.Rolling)),
+		}
+		for i, c := range a.Coverage {
+			accuracy.Coverage[i] = &BacktestCoverageResponse{Level: math.Round(c.Level*1000) / 10, Coverage: c.Coverage}
+		}
+		for i, r := range a.Rolling {
+			accuracy.Rolling[i] = &RollingAccuracyResponse{
+				Date:   r.Date.In(loc).Format("2006-01-02"),
+				Points: r.Points,
+				MAE:    finiteValue(r.MAE),
+				MAPE:   finiteValue(r.MAPE),
+			}
+		}
+		response.Accuracy = append(response.Accuracy, accuracy)
+	}
+
+	return response
+}
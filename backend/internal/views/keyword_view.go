@@ -41,4 +41,137 @@ func NewKeywordListResponse(keywords []*models.Keyword) *KeywordListResponse {
 		Keywords: keywordResponses,
 		Count:    len(keywordResponses),
 	}
-} 
\ No newline at end of file
+}
+
+// KeywordUpdateResponse represents a changed keyword
+type KeywordUpdateResponse struct {
+	KeywordResponse
+	DefinitionChanged bool `json:"definition_changed"` // the text changed and a new version was recorded
+	Reaggregating     bool `json:"reaggregating"`      // history is being rescored against the new text
+}
+
+// NewKeywordUpdateResponse creates a keyword update response
+func NewKeywordUpdateResponse(keyword *models.Keyword, definitionChanged, reaggregating bool) *KeywordUpdateResponse {
+	return &KeywordUpdateResponse{
+		KeywordResponse:   *NewKeywordResponse(keyword),
+		DefinitionChanged: definitionChanged,
+		Reaggregating:     reaggregating,
+	}
+}
+
+// KeywordVersionResponse represents one definition of a keyword
+type KeywordVersionResponse struct {
+	Version       int       `json:"version"`
+	Keyword       string    `json:"keyword"`
+	EffectiveFrom time.Time `json:"effective_from"`
+}
+
+// KeywordVersionListResponse represents the definition history of a keyword
+type KeywordVersionListResponse struct {
+	KeywordID int                       `json:"keyword_id"`
+	Versions  []*KeywordVersionResponse `json:"versions"`
+	Count     int                       `json:"count"`
+}
+
+// NewKeywordVersionListResponse creates a keyword version list response
+func NewKeywordVersionListResponse(keywordID int, versions []models.KeywordVersion) *KeywordVersionListResponse {
+	responses := make([]*KeywordVersionResponse, len(versions))
+	for i, v := range versions {
+		responses[i] = &KeywordVersionResponse{
+			Version:       v.Version,
+			Keyword:       v.Keyword,
+			EffectiveFrom: v.EffectiveFrom,
+		}
+	}
+
+	return &KeywordVersionListResponse{
+		KeywordID: keywordID,
+		Versions:  responses,
+		Count:     len(responses),
+	}
+}
+
+// DeletedKeywordResponse represents a deleted keyword that can be restored
+type DeletedKeywordResponse struct {
+	KeywordResponse
+	DeletedAt    time.Time `json:"deleted_at"`
+	RestoreUntil time.Time `json:"restore_until"`
+}
+
+// NewDeletedKeywordResponse creates a deleted keyword response
+func NewDeletedKeywordResponse(keyword *models.Keyword, restoreUntil time.Time) *DeletedKeywordResponse {
+	response := &DeletedKeywordResponse{
+		KeywordResponse: *NewKeywordResponse(keyword),
+		RestoreUntil:    restoreUntil,
+	}
+	if keyword.DeletedAt != nil {
+		response.DeletedAt = *keyword.DeletedAt
+	}
+	return response
+}
+
+// DeletedKeywordListResponse represents the restorable keywords of a user
+type DeletedKeywordListResponse struct {
+	Keywords []*DeletedKeywordResponse `json:"keywords"`
+	Count    int                       `json:"count"`
+}
+
+// BackfillDayResponse represents the historical collection coverage of one day
+type BackfillDayResponse struct {
+	Date      string   `json:"date"`
+	Status    string   `json:"status"`
+	ItemCount int      `json:"item_count"`
+	Sources   []string `json:"sources"`
+}
+
+// BackfillCoverageResponse represents the historical collection coverage of a keyword
+type BackfillCoverageResponse struct {
+	KeywordID     int                    `json:"keyword_id"`
+	Status        string                 `json:"status"` // pending until the backfill has finished
+	From          string                 `json:"from,omitempty"`
+	To            string                 `json:"to,omitempty"`
+	Days          []*BackfillDayResponse `json:"days"`
+	CollectedDays int                    `json:"collected_days"`
+	EmptyDays     int                    `json:"empty_days"`
+	UncoveredDays int                    `json:"uncovered_days"`
+	CoverageRatio float64                `json:"coverage_ratio"` // share of days some source reached
+	ItemCount     int                    `json:"item_count"`
+}
+
+// NewBackfillCoverageResponse creates a backfill coverage response
+func NewBackfillCoverageResponse(keywordID int, days []models.BackfillCoverage) *BackfillCoverageResponse {
+	response := &BackfillCoverageResponse{
+		KeywordID: keywordID,
+		Status:    "pending",
+		Days:      make([]*BackfillDayResponse, len(days)),
+	}
+	if len(days) == 0 {
+		return response
+	}
+
+	response.Status = "complete"
+	response.From = days[0].Date.Format("2006-01-02")
+	response.To = days[len(days)-1].Date.Format("2006-01-02")
+
+	for i, day := range days {
+		response.Days[i] = &BackfillDayResponse{
+			Date:      day.Date.Format("2006-01-02"),
+			Status:    day.Status,
+			ItemCount: day.ItemCount,
+			Sources:   day.Sources,
+		}
+		response.ItemCount += day.ItemCount
+
+		switch day.Status {
+		case models.BackfillStatusCollected:
+			response.CollectedDays++
+		case models.BackfillStatusEmpty:
+			response.EmptyDays++
+		default:
+			response.UncoveredDays++
+		}
+	}
+
+	response.CoverageRatio = float64(response.CollectedDays+response.EmptyDays) / float64(len(days))
+	return response
+}
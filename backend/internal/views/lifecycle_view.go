@@ -0,0 +1,42 @@
+package views
+
+import (
+	"time"
+
+	"github.com/trendscout/backend/internal/trend"
+)
+
+// LifecycleResponse represents the lifecycle stage of a trend: emerging,
+// accelerating, peaking, declining, fad, stable or insufficient_data
+type LifecycleResponse struct {
+	Stage            string  `json:"stage"`
+	Reason           string  `json:"reason"`
+	GrowthRate       float64 `json:"growth_rate"`        // average weekly change over the last two weeks, as a fraction
+	Acceleration     float64 `json:"acceleration"`       // change in weekly log growth
+	DistanceFromPeak float64 `json:"distance_from_peak"` // fraction below the peak
+	PeakDate         *string `json:"peak_date"`          // null without enough data
+	DaysSincePeak    int     `json:"days_since_peak"`
+	PeakWidth        int     `json:"peak_width"`  // days above half of the peak height
+	Persistence      int     `json:"persistence"` // consecutive weeks changing in the same direction
+	Days             int     `json:"days"`        // days classified
+}
+
+// NewLifecycleResponse creates a lifecycle response with dates in loc
+func NewLifecycleResponse(lifecycle *trend.Lifecycle, loc *time.Location) *LifecycleResponse {
+	response := &LifecycleResponse{
+		Stage:            lifecycle.Stage,
+		Reason:           lifecycle.Reason,
+		GrowthRate:       lifecycle.GrowthRate,
+		Acceleration:     lifecycle.Acceleration,
+		DistanceFromPeak: lifecycle.DistanceFromPeak,
+		DaysSincePeak:    lifecycle.DaysSincePeak,
+		PeakWidth:        lifecycle.PeakWidth,
+		Persistence:      lifecycle.Persistence,
+		Days:             lifecycle.Days,
+	}
+	if !lifecycle.PeakDate.IsZero() {
+		date := lifecycle.PeakDate.In(loc).Format("2006-01-02")
+		response.PeakDate = &date
+	}
+	return response
+}
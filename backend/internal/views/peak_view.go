@@ -0,0 +1,132 @@
+package views
+
+import (
+	"time"
+
+	"github.com/trendscout/backend/internal/trend"
+)
+
+// PeakEstimateResponse represents when a trend peaks, how high and where its
+// cumulative volume saturates, from diffusion curves fitted to its volume
+type PeakEstimateResponse struct {
+	KeywordID  int                       `json:"keyword_id"`
+	Metric     string                    `json:"metric"`
+	Source     []string                  `json:"source,omitempty"` // source filter, if any
+	StartDate  string                    `json:"start_date"`
+	EndDate    string                    `json:"end_date"`
+	Status     string                    `json:"status"` // fitted, observed (the fit is poor but the trend already peaked) or no_estimate
+	Reason     string                    `json:"reason"`
+	Model      string                    `json:"model,omitempty"` // curve used when fitted
+	PeakDate   *string                   `json:"peak_date"`       // null without an estimate
+	PeakVolume *float64                  `json:"peak_volume"`     // daily volume at the peak
+	Saturation *float64                  `json:"saturation"`      // cumulative volume above the baseline at saturation; null unless fitted
+	Observed   float64                   `json:"observed"`        // cumulative volume above the baseline so far
+	Adoption   *float64                  `json:"adoption"`        // share of the saturation reached so far
+	Baseline   float64                   `json:"baseline"`        // daily background volume, excluded from the curves
+	FitQuality *DiffusionQualityResponse `json:"fit_quality"`     // of the curve used; null unless fitted
+	Candidates []*DiffusionCurveResponse `json:"candidates"`      // by AIC, best first
+	Projection []*PeakProjectionResponse `json:"projection"`      // days after the data, when fitted
+}
+
+// DiffusionQualityResponse represents how well a diffusion curve fits
+type DiffusionQualityResponse struct {
+	R2      *float64 `json:"r2"`       // of the cumulative volume
+	R2Daily *float64 `json:"r2_daily"` // of the daily volume
+	RMSE    *float64 `json:"rmse"`     // of the daily volume
+	AIC     *float64 `json:"aic"`
+}
+
+// DiffusionCurveResponse represents a fitted diffusion curve
+type DiffusionCurveResponse struct {
+	Model      string                    `json:"model"` // bass, logistic or gompertz
+	Spec       string                    `json:"spec"`
+	Parameters map[string]*float64       `json:"parameters"` // null when not finite
+	PeakDate   string                    `json:"peak_date"`
+	PeakVolume *float64                  `json:"peak_volume"`
+	Saturation *float64                  `json:"saturation"`
+	Quality    *DiffusionQualityResponse `json:"quality"`
+	Acceptable bool                      `json:"acceptable"`
+	Reason     string                    `json:"reason,omitempty"` // why the curve is not acceptable
+}
+
+// PeakProjectionResponse represents the volume a fitted curve projects for a day
+type PeakProjectionResponse struct {
+	Date       string  `json:"date"`
+	Volume     float64 `json:"volume"`
+	Cumulative float64 `json:"cumulative"` // above the baseline, from the first day
+}
+
+// NewPeakEstimateResponse creates a peak estimate response with dates in loc
+func NewPeakEstimateResponse(keywordID int, metric string, sources []string, start, end time.Time, estimate *trend.PeakEstimate, loc *time.Location) *PeakEstimateResponse {
+	response := &PeakEstimateResponse{
+		KeywordID:  keywordID,
+		Metric:     metric,
+		Source:     sources,
+		StartDate:  start.In(loc).Format("2006-01-02"),
+		EndDate:    end.In(loc).Format("2006-01-02"),
+		Status:     estimate.Status,
+		Reason:     estimate.Reason,
+		Observed:   estimate.Observed,
+		Baseline:   estimate.Baseline,
+		Candidates: []*DiffusionCurveResponse{},
+		Projection: []*PeakProjectionResponse{},
+	}
+
+	if estimate.Status != trend.PeakNone {
+		date := estimate.PeakDate.In(loc).Format("2006-01-02")
+		response.PeakDate = &date
+		response.PeakVolume = finiteValue(estimate.PeakVolume)
+	}
+	if estimate.Fit != nil {
+		response.Model = estimate.Fit.Model
+		response.Saturation = finiteValue(estimate.Saturation)
+		response.FitQuality = newDiffusionQualityResponse(estimate.Fit)
+		if estimate.Saturation > 0 {
+			response.Adoption = finiteValue(estimate.Observed / estimate.Saturation)
+		}
+	}
+
+	for i := range estimate.Candidates {
+		fit := &estimate.Candidates[i]
+		response.Candidates = append(response.Candidates, &DiffusionCurveResponse{
+			Model:      fit.Model,
+			Spec:       fit.Spec,
+			Parameters: finiteValues(fit.Parameters),
+			PeakDate:   fit.PeakDate.In(loc).Format("2006-01-02"),
+			PeakVolume: finiteValue(fit.PeakVolume),
+			Saturation: finiteValue(fit.Saturation),
+			Quality:    newDiffusionQualityResponse(fit),
+			Acceptable: fit.Acceptable,
+			Reason:     fit.Reason,
+		})
+	}
+
+	for _, p := range estimate.Projection {
+		response.Projection = append(response.Projection, &PeakProjectionResponse{
+			Date:       p.Date.In(loc).Format("2006-01-02"),
+			Volume:     p.Volume,
+			Cumulative: p.Cumulative,
+		})
+	}
+
+	return response
+}
+
+// newDiffusionQualityResponse creates the fit quality response of a curve
+func newDiffusionQualityResponse(fit *trend.DiffusionFit) *DiffusionQualityResponse {
+	return &DiffusionQualityResponse{
+		R2:      finiteValue(fit.R2),
+		R2Daily: finiteValue(fit.R2Daily),
+		RMSE:    finiteValue(fit.RMSE),
+		AIC:     finiteValue(fit.AIC),
+	}
+}
+
+// finiteValues returns the values of m that are finite, with the others null
+func finiteValues(m map[string]float64) map[string]*float64 {
+	values := make(map[string]*float64, len(m))
+	for k, v := range m {
+		values[k] = finiteValue(v)
+	}
+	return values
+}
@@ -0,0 +1,139 @@
+package views
+
+import (
+	"time"
+
+	"github.com/trendscout/backend/internal/models"
+)
+
+// TrendItemResponse represents an item that contributed to a trend point
+type TrendItemResponse struct {
+	ID           int64     `json:"id"`
+	Kind         string    `json:"kind"`
+	Source       string    `json:"source"`
+	URL          string    `json:"url,omitempty"`
+	Title        string    `json:"title,omitempty"`
+	Snippet      string    `json:"snippet"` // HTML-escaped, keyword matches wrapped in <mark>
+	ImageURL     string    `json:"image_url,omitempty"`
+	Author       string    `json:"author,omitempty"`
+	Language     string    `json:"language,omitempty"`
+	PublishedAt  time.Time `json:"published_at"`
+	LikeCount    int       `json:"like_count"`
+	CommentCount int       `json:"comment_count"`
+	RepostCount  int       `json:"repost_count"`
+	Relevance    float64   `json:"relevance"`
+	Engagement   float64   `json:"engagement"`
+	Sentiment    float64   `json:"sentiment"`
+	Provenance   string    `json:"provenance,omitempty"`
+}
+
+// TrendPointImageResponse represents an image attached to a contributing item
+type TrendPointImageResponse struct {
+	ItemID int64  `json:"item_id"`
+	URL    string `json:"url"`
+	Source string `json:"source"`
+}
+
+// TrendPointKindResponse summarises the contributing items of one kind
+type TrendPointKindResponse struct {
+	Kind       string  `json:"kind"`
+	Volume     int     `json:"volume"`
+	Engagement float64 `json:"engagement"`
+	Sentiment  float64 `json:"sentiment"`
+}
+
+// TrendPointItemsResponse represents the items behind a trend data point.
+// Volume, engagement and sentiment are recomputed from the contributing items
+// and match the point for records produced with item lineage.
+type TrendPointItemsResponse struct {
+	KeywordID   int                        `json:"keyword_id"`
+	Date        string                     `json:"date"`
+	Granularity string                     `json:"granularity"`
+	Start       time.Time                  `json:"start"`
+	End         time.Time                  `json:"end"`
+	OrderBy     string                     `json:"order_by"`
+	Volume      int                        `json:"volume"`
+	Engagement  float64                    `json:"engagement"`
+	Sentiment   *float64                   `json:"sentiment"` // nil when no items contributed
+	ByKind      []*TrendPointKindResponse  `json:"by_kind"`
+	Items       []*TrendItemResponse       `json:"items"`
+	Images      []*TrendPointImageResponse `json:"images"`
+	Count       int                        `json:"count"`
+	Offset      int                        `json:"offset"`
+}
+
+// NewTrendPointItemsResponse creates the drill-down response of a trend point,
+// highlighting keyword in the item snippets and showing times in loc
+func NewTrendPointItemsResponse(q models.TrendPointQuery, date, granularity, keyword string, summaries []models.TrendPointKindSummary, items []models.TrendItem, loc *time.Location) *TrendPointItemsResponse {
+	response := &TrendPointItemsResponse{
+		KeywordID:   q.KeywordID,
+		Date:        date,
+		Granularity: granularity,
+		Start:       q.Start.In(loc),
+		End:         q.End.In(loc),
+		OrderBy:     q.OrderBy,
+		ByKind:      []*TrendPointKindResponse{},
+		Items:       []*TrendItemResponse{},
+		Images:      []*TrendPointImageResponse{},
+		Count:       len(items),
+		Offset:      q.Offset,
+	}
+
+	var sentimentSum float64
+	for _, s := range summaries {
+		response.ByKind = append(response.ByKind, &TrendPointKindResponse{
+			Kind:       s.Kind,
+			Volume:     s.Volume,
+			Engagement: s.Engagement,
+			Sentiment:  s.Sentiment,
+		})
+		response.Volume += s.Volume
+		response.Engagement += s.Engagement
+		sentimentSum += s.Sentiment * float64(s.Volume)
+	}
+	if response.Volume > 0 {
+		sentiment := sentimentSum / float64(response.Volume)
+		response.Sentiment = &sentiment
+	}
+
+	terms := []string{keyword}
+	seenImages := make(map[string]bool)
+	for _, item := range items {
+		response.Items = append(response.Items, newTrendItemResponse(item, terms, loc))
+
+		if item.ImageURL != "" && !seenImages[item.ImageURL] {
+			seenImages[item.ImageURL] = true
+			response.Images = append(response.Images, &TrendPointImageResponse{
+				ItemID: item.ID,
+				URL:    item.ImageURL,
+				Source: item.Source,
+			})
+		}
+	}
+
+	return response
+}
+
+// newTrendItemResponse creates the response of an item, highlighting terms in
+// its snippet and showing times in loc
+func newTrendItemResponse(item models.TrendItem, terms []string, loc *time.Location) *TrendItemResponse {
+	return &TrendItemResponse{
+		ID:           item.ID,
+		Kind:         item.Kind,
+		Source:       item.Source,
+		URL:          item.URL,
+		Title:        item.Title,
+		Snippet:      HighlightSnippet(item.Content, terms),
+		ImageURL:     item.ImageURL,
+		Author:       item.Author,
+		Language:     item.Language,
+		PublishedAt:  item.PublishedAt.In(loc),
+		LikeCount:    item.LikeCount,
+		CommentCount: item.CommentCount,
+		RepostCount:  item.RepostCount,
+		Relevance:    item.Relevance,
+		Engagement:   item.Engagement,
+		Sentiment:    item.Sentiment,
+		Provenance:   item.Provenance,
+	}
+}
@@ -0,0 +1,81 @@
+package views
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/trendscout/backend/internal/models"
+)
+
+func TestNewTrendPointItemsResponse(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	start := time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC)
+	q := models.TrendPointQuery{KeywordID: 2, Start: start, End: start.AddDate(0, 0, 1), OrderBy: "engagement", Offset: 20}
+
+	summaries := []models.TrendPointKindSummary{
+		{Kind: "article", Volume: 3, Engagement: 4.5, Sentiment: 0.8},
+		{Kind: "post", Volume: 1, Engagement: 2, Sentiment: 0.4},
+	}
+	items := []models.TrendItem{
+		{ID: 1, Kind: "article", Source: "vogue.com", Content: "Denim is back", ImageURL: "https://img/a.jpg", PublishedAt: start.Add(time.Hour)},
+		{ID: 2, Kind: "article", Source: "elle.com", Content: "More denim", ImageURL: "https://img/a.jpg"},
+		{ID: 3, Kind: "post", Source: "bluesky", Content: "no image"},
+		{ID: 4, Kind: "article", Source: "wwd.com", ImageURL: "https://img/b.jpg"},
+	}
+
+	response := NewTrendPointItemsResponse(q, "2024-03-02", "day", "denim", summaries, items, jst)
+
+	if response.KeywordID != 2 || response.Date != "2024-03-02" || response.Granularity != "day" ||
+		response.OrderBy != "engagement" || response.Offset != 20 || response.Count != 4 {
+		t.Errorf("response header = %+v", response)
+	}
+	if response.Start.Location() != jst || response.Start.Hour() != 0 {
+		t.Errorf("start = %s, want midnight in JST", response.Start)
+	}
+
+	// Totals add up the kinds; sentiment is weighted by each kind's volume
+	if response.Volume != 4 || response.Engagement != 6.5 {
+		t.Errorf("volume = %d, engagement = %v, want 4 and 6.5", response.Volume, response.Engagement)
+	}
+	if want := (3*0.8 + 1*0.4) / 4; response.Sentiment == nil || math.Abs(*response.Sentiment-want) > 1e-9 {
+		t.Errorf("sentiment = %v, want %v", response.Sentiment, want)
+	}
+	if len(response.ByKind) != 2 || response.ByKind[1].Kind != "post" || response.ByKind[1].Volume != 1 {
+		t.Errorf("by kind = %+v", response.ByKind)
+	}
+
+	// Items keep their order; snippets highlight the keyword
+	if len(response.Items) != 4 || response.Items[0].ID != 1 || response.Items[3].ID != 4 {
+		t.Fatalf("items = %+v", response.Items)
+	}
+	if want := "<mark>Denim</mark> is back"; response.Items[0].Snippet != want {
+		t.Errorf("snippet = %q, want %q", response.Items[0].Snippet, want)
+	}
+	if response.Items[0].PublishedAt.Location() != jst {
+		t.Errorf("published at = %s, want JST", response.Items[0].PublishedAt)
+	}
+
+	// An image shared by several items is listed once, with its first item
+	if len(response.Images) != 2 {
+		t.Fatalf("got %d images, want 2", len(response.Images))
+	}
+	if img := response.Images[0]; img.ItemID != 1 || img.URL != "https://img/a.jpg" || img.Source != "vogue.com" {
+		t.Errorf("first image = %+v, want item 1 from vogue.com", img)
+	}
+	if img := response.Images[1]; img.ItemID != 4 || img.URL != "https://img/b.jpg" {
+		t.Errorf("second image = %+v, want item 4", img)
+	}
+}
+
+func TestNewTrendPointItemsResponseWithoutItems(t *testing.T) {
+	response := NewTrendPointItemsResponse(models.TrendPointQuery{}, "2024-03-02", "hour", "denim", nil, nil, time.UTC)
+
+	// Sentiment is unknown rather than neutral, and lists are empty, not null
+	if response.Volume != 0 || response.Sentiment != nil {
+		t.Errorf("volume = %d, sentiment = %v, want 0 and nil", response.Volume, response.Sentiment)
+	}
+	if response.ByKind == nil || response.Items == nil || response.Images == nil {
+		t.Errorf("lists should be empty, not nil: %+v", response)
+	}
+}
@@ -1,34 +1,125 @@
 package views
 
 import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/trendscout/backend/internal/metrics"
 	"github.com/trendscout/backend/internal/models"
+	"github.com/trendscout/backend/internal/prediction"
+	"github.com/trendscout/backend/internal/series"
+	"github.com/trendscout/backend/internal/trend"
 )
 
 // TrendAnalysisResponse represents the response for trend analysis
 type TrendAnalysisResponse struct {
-	KeywordID int                    `json:"keyword_id"`
-	StartDate string                 `json:"start_date"`
-	EndDate   string                 `json:"end_date"`
-	Data      []models.TrendRecord   `json:"data"`
-	Insights  map[string]interface{} `json:"insights"`
+	KeywordID int                     `json:"keyword_id"`
+	StartDate string                  `json:"start_date"`
+	EndDate   string                  `json:"end_date"`
+	Metric    string                  `json:"metric"`
+	Source    []string                `json:"source,omitempty"` // source filter, if any
+	Data      []models.TrendRecord    `json:"data"`
+	Sources   []*SourceSeriesResponse `json:"sources"`
+	Lifecycle *LifecycleResponse      `json:"lifecycle"`
+	Insights  map[string]interface{}  `json:"insights"`
 }
 
 // PredictionData represents a single prediction data point
 type PredictionData struct {
-	Date           string  `json:"date"`
-	Volume         int     `json:"volume"`
-	Sentiment      float64 `json:"sentiment"`
-	Confidence     float64 `json:"confidence"`
-	TrendDirection string  `json:"trend_direction"`
+	Date           string                        `json:"date"`
+	Volume         int                           `json:"volume"`
+	Value          float64                       `json:"value"`   // predicted value of the selected metric
+	StdErr         float64                       `json:"std_err"` // forecast standard error of value
+	Intervals      []*PredictionIntervalResponse `json:"intervals"`
+	Sentiment      float64                       `json:"sentiment"`
+	Confidence     float64                       `json:"confidence"` // score derived from the width of the 80% interval
+	TrendDirection string                        `json:"trend_direction"`
+}
+
+// PredictionIntervalResponse represents a prediction interval of the value
+type PredictionIntervalResponse struct {
+	Level float64 `json:"level"` // in percent
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+// NewPredictionIntervalResponses creates prediction interval responses
+func NewPredictionIntervalResponses(intervals []prediction.Interval) []*PredictionIntervalResponse {
+	responses := make([]*PredictionIntervalResponse, len(intervals))
+	for i, interval := range intervals {
+		responses[i] = &PredictionIntervalResponse{
+			Level: math.Round(interval.Level*1000) / 10,
+			Lower: interval.Lower,
+			Upper: interval.Upper,
+		}
+	}
+	return responses
 }
 
 // TrendPredictionResponse represents the response for trend prediction
 type TrendPredictionResponse struct {
 	KeywordID   int                    `json:"keyword_id"`
+	Metric      string                 `json:"metric"`
+	Source      []string               `json:"source,omitempty"`      // source filter, if any
+	ForecastID  int                    `json:"forecast_id,omitempty"` // persisted forecast, scored as actual values arrive
+	ModelFit    *ModelFitResponse      `json:"model_fit"`
 	Predictions []PredictionData       `json:"predictions"`
 	Insights    map[string]interface{} `json:"insights"`
 }
 
+// ModelFitResponse describes the model fitted for a prediction and why it was used
+type ModelFitResponse struct {
+	Model      string                    `json:"model"`
+	Spec       string                    `json:"spec"`       // fitted specification, such as HW(Ad,M)[7]
+	Parameters map[string]float64        `json:"parameters"` // member weights for an ensemble
+	AIC        *float64                  `json:"aic"`        // null for an ensemble
+	Selection  string                    `json:"selection"`  // requested, fallback, backtest, ensemble or default
+	Reason     string                    `json:"reason"`
+	Candidates []*ModelCandidateResponse `json:"candidates,omitempty"` // models compared by auto and ensemble, best first
+	// Volume models are trained on the current regime of the series only
+	TrainingStart  string `json:"training_start"`
+	TrainingPoints int    `json:"training_points"`
+	RegimeStart    string `json:"regime_start,omitempty"` // start of the current regime, if a change was detected
+}
+
+// ModelCandidateResponse represents the score of a model compared by automatic selection
+type ModelCandidateResponse struct {
+	Model  string   `json:"model"`
+	MASE   *float64 `json:"mase"` // null when not backtested or the model failed
+	AIC    *float64 `json:"aic"`  // null when the model could not be fitted
+	Weight float64  `json:"weight,omitempty"`
+}
+
+// NewModelFitResponse creates a model fit response with dates in loc
+func NewModelFitResponse(fit *trend.VolumeFit, loc *time.Location) *ModelFitResponse {
+	var candidates []*ModelCandidateResponse
+	for _, c := range fit.Candidates {
+		candidates = append(candidates, &ModelCandidateResponse{
+			Model:  c.Model,
+			MASE:   finiteValue(c.MASE),
+			AIC:    finiteValue(c.AIC),
+			Weight: c.Weight,
+		})
+	}
+
+	response := &ModelFitResponse{
+		Model:          fit.Model,
+		Spec:           fit.Spec,
+		Parameters:     fit.Parameters,
+		AIC:            finiteValue(fit.AIC),
+		Selection:      fit.Selection,
+		Reason:         fit.Reason,
+		Candidates:     candidates,
+		TrainingStart:  fit.TrainingStart.In(loc).Format("2006-01-02"),
+		TrainingPoints: fit.TrainingPoints,
+	}
+	if !fit.RegimeStart.IsZero() {
+		response.RegimeStart = fit.RegimeStart.In(loc).Format("2006-01-02")
+	}
+	return response
+}
+
 // SentimentAnalysisResponse represents the response for sentiment analysis
 type SentimentAnalysisResponse struct {
 	KeywordID        int                  `json:"keyword_id"`
@@ -42,60 +133,266 @@ type SentimentAnalysisResponse struct {
 
 // KeywordComparisonData represents trend data for a single keyword in comparison
 type KeywordComparisonData struct {
-	KeywordID    int                  `json:"keyword_id"`
-	Keyword      string               `json:"keyword"`
-	TotalVolume  int                  `json:"total_volume"`
-	AvgSentiment float64              `json:"avg_sentiment"`
-	DataPoints   int                  `json:"data_points"`
-	Trends       []models.TrendRecord `json:"trends"`
+	KeywordID         int                         `json:"keyword_id"`
+	Keyword           string                      `json:"keyword"`
+	TotalVolume       int                         `json:"total_volume"`
+	TotalEngagement   float64                     `json:"total_engagement"`
+	TotalValue        float64                     `json:"total_value"` // total of the selected metric
+	AvgSentiment      float64                     `json:"avg_sentiment"`
+	DataPoints        int                         `json:"data_points"`
+	Lifecycle         *LifecycleResponse          `json:"lifecycle"`
+	Trends            []models.TrendRecord        `json:"trends"`
+	Sources           []*SourceSeriesResponse     `json:"sources"`
+	DefinitionChanges []*DefinitionChangeResponse `json:"definition_changes"`
 }
 
 // MultiKeywordComparisonResponse represents the response for multi-keyword comparison
 type MultiKeywordComparisonResponse struct {
 	Period    int                     `json:"period"`
+	Metric    string                  `json:"metric"`
+	Source    []string                `json:"source,omitempty"` // source filter, if any
 	StartDate string                  `json:"start_date"`
 	EndDate   string                  `json:"end_date"`
 	Keywords  []KeywordComparisonData `json:"keywords"`
 	Insights  map[string]interface{}  `json:"insights"`
 }
 
-// TrendRecordResponse represents a trend record response
+// TrendRecordResponse represents a trend record, or one bucket of a
+// resampled series. Values are null for gaps under the "null" fill policy.
 type TrendRecordResponse struct {
-	ID        int     `json:"id"`
-	KeywordID int     `json:"keyword_id"`
-	Date      string  `json:"date"`
-	Volume    int     `json:"volume"`
-	Sentiment float64 `json:"sentiment"`
+	ID         int      `json:"id"`
+	KeywordID  int      `json:"keyword_id"`
+	Date       string   `json:"date"` // start of the bucket (RFC 3339 for hourly buckets)
+	Volume     *int     `json:"volume"`
+	Engagement *float64 `json:"engagement"`
+	Value      *float64 `json:"value"` // value of the selected metric
+	Sentiment  *float64 `json:"sentiment"`
+	Filled     bool     `json:"filled,omitempty"` // bucket had no data and was gap-filled
 }
 
 // TrendRecordListResponse represents a list of trend records
 type TrendRecordListResponse struct {
-	Records []*TrendRecordResponse `json:"records"`
-	Count   int                    `json:"count"`
+	Metric            string                      `json:"metric"`
+	Granularity       string                      `json:"granularity"`
+	Aggregation       string                      `json:"aggregation"`
+	Fill              string                      `json:"fill"`
+	Source            []string                    `json:"source,omitempty"` // source filter, if any
+	Records           []*TrendRecordResponse      `json:"records"`
+	Count             int                         `json:"count"`
+	Sources           []*SourceSeriesResponse     `json:"sources"`
+	DefinitionChanges []*DefinitionChangeResponse `json:"definition_changes"`
+	Lifecycle         *LifecycleResponse          `json:"lifecycle"` // of the daily series in the range
+}
+
+// DefinitionChangeResponse marks a change of the keyword text on the
+// timeline. Date is the label of the bucket the change falls into, so it
+// can be matched against the records.
+type DefinitionChangeResponse struct {
+	Version         int       `json:"version"`
+	Keyword         string    `json:"keyword"`
+	PreviousKeyword string    `json:"previous_keyword"`
+	EffectiveFrom   time.Time `json:"effective_from"`
+	Date            string    `json:"date"`
+}
+
+// NewDefinitionChangeResponses lists the keyword definition changes that took
+// effect between start and end, in buckets of granularity aligned in loc
+func NewDefinitionChangeResponses(versions []models.KeywordVersion, granularity string, start, end time.Time, loc *time.Location) []*DefinitionChangeResponse {
+	changes := []*DefinitionChangeResponse{}
+	for i := 1; i < len(versions); i++ {
+		v := versions[i]
+		if v.EffectiveFrom.Before(start) || v.EffectiveFrom.After(end) {
+			continue
+		}
+		changes = append(changes, &DefinitionChangeResponse{
+			Version:         v.Version,
+			Keyword:         v.Keyword,
+			PreviousKeyword: versions[i-1].Keyword,
+			EffectiveFrom:   v.EffectiveFrom,
+			Date:            bucketLabel(series.BucketStart(v.EffectiveFrom.In(loc), granularity), granularity),
+		})
+	}
+	return changes
 }
 
-// NewTrendRecordResponse creates a new trend record response
-func NewTrendRecordResponse(record *models.TrendRecord) *TrendRecordResponse {
+// NewTrendRecordResponse creates a new trend record response, dated in loc
+func NewTrendRecordResponse(record *models.TrendRecord, metric string, loc *time.Location) *TrendRecordResponse {
+	volume := record.Volume
+	engagement := record.Engagement
+	value := record.MetricValue(metric)
+	sentiment := record.Sentiment
 	return &TrendRecordResponse{
-		ID:        record.ID,
-		KeywordID: record.KeywordID,
-		Date:      record.Date.Format("2006-01-02"),
-		Volume:    record.Volume,
-		Sentiment: record.Sentiment,
+		ID:         record.ID,
+		KeywordID:  record.KeywordID,
+		Date:       record.Date.In(loc).Format("2006-01-02"),
+		Volume:     &volume,
+		Engagement: &engagement,
+		Value:      &value,
+		Sentiment:  &sentiment,
 	}
 }
 
-// NewTrendRecordListResponse creates a new trend record list response
-func NewTrendRecordListResponse(records []models.TrendRecord) TrendRecordListResponse {
+// NewTrendRecordListResponse creates a new trend record list response of raw
+// daily records. Daily records are days of the collection time zone; their
+// dates are shown in loc, which matches them when both zones are the same.
+func NewTrendRecordListResponse(records []models.TrendRecord, metric string, loc *time.Location) TrendRecordListResponse {
 	var responses []*TrendRecordResponse
 	for _, record := range records {
-		responses = append(responses, NewTrendRecordResponse(&record))
+		responses = append(responses, NewTrendRecordResponse(&record, metric, loc))
+	}
+
+	return TrendRecordListResponse{
+		Metric:      metric,
+		Granularity: series.Day,
+		Aggregation: metrics.Aggregation(metric),
+		Fill:        series.FillNone,
+		Records:     responses,
+		Count:       len(responses),
+	}
+}
+
+// NewResampledTrendRecordListResponse creates a trend record list response
+// with one record per bucket between start and end, with buckets aligned to
+// days in loc. Volume, engagement and the selected metric are combined with
+// aggregation; sentiment is always averaged. Buckets without data are handled
+// according to the fill policy.
+func NewResampledTrendRecordListResponse(records []models.TrendRecord, metric, granularity, aggregation, fill string, start, end time.Time, loc *time.Location) TrendRecordListResponse {
+	resample := func(value func(r *models.TrendRecord) float64, agg string) []series.Point {
+		points := make([]series.Point, len(records))
+		for i := range records {
+			points[i] = series.Point{Time: records[i].Date.In(loc), Value: value(&records[i]), Valid: true}
+		}
+		return series.FillGaps(series.Resample(points, granularity, agg), granularity, start.In(loc), end.In(loc), fill)
+	}
+
+	// Every record contributes to every field, so the series share their buckets
+	volumes := resample(func(r *models.TrendRecord) float64 { return float64(r.Volume) }, aggregation)
+	engagements := resample(func(r *models.TrendRecord) float64 { return r.Engagement }, aggregation)
+	values := resample(func(r *models.TrendRecord) float64 { return r.MetricValue(metric) }, aggregation)
+	sentiments := resample(func(r *models.TrendRecord) float64 { return r.Sentiment }, series.Avg)
+
+	keywordID := 0
+	if len(records) > 0 {
+		keywordID = records[0].KeywordID
+	}
+
+	responses := make([]*TrendRecordResponse, len(values))
+	for i := range values {
+		response := &TrendRecordResponse{
+			KeywordID:  keywordID,
+			Date:       bucketLabel(values[i].Time, granularity),
+			Engagement: optionalValue(engagements[i]),
+			Value:      optionalValue(values[i]),
+			Sentiment:  optionalValue(sentiments[i]),
+			Filled:     values[i].Filled,
+		}
+		if volumes[i].Valid {
+			volume := int(math.Round(volumes[i].Value))
+			response.Volume = &volume
+		}
+		responses[i] = response
 	}
 
 	return TrendRecordListResponse{
-		Records: responses,
-		Count:   len(responses),
+		Metric:      metric,
+		Granularity: granularity,
+		Aggregation: aggregation,
+		Fill:        fill,
+		Records:     responses,
+		Count:       len(responses),
+	}
+}
+
+// bucketLabel formats a bucket start: a date for daily and coarser buckets,
+// a full timestamp for hourly ones
+func bucketLabel(t time.Time, granularity string) string {
+	if granularity == series.Hour {
+		return t.Format(time.RFC3339)
+	}
+	return t.Format("2006-01-02")
+}
+
+// optionalValue returns the point's value, or nil for a missing value
+func optionalValue(p series.Point) *float64 {
+	if !p.Valid {
+		return nil
+	}
+	value := p.Value
+	return &value
+}
+
+// SourcePointResponse represents one day of a source's series
+type SourcePointResponse struct {
+	Date       string  `json:"date"`
+	Volume     int     `json:"volume"`
+	Engagement float64 `json:"engagement"`
+	Value      float64 `json:"value"` // value of the selected metric
+	Share      float64 `json:"share"` // share of the day's total across sources
+	Sentiment  float64 `json:"sentiment"`
+	FetchOK    bool    `json:"fetch_ok"`
+	FetchError string  `json:"fetch_error,omitempty"`
+}
+
+// SourceSeriesResponse represents the stacked series of a single source
+type SourceSeriesResponse struct {
+	Source     string                 `json:"source"`
+	TotalValue float64                `json:"total_value"`
+	Share      float64                `json:"share"` // share of the period total across sources
+	FailedDays int                    `json:"failed_days"`
+	Points     []*SourcePointResponse `json:"points"`
+}
+
+// NewSourceSeriesResponses groups per-source rows into one series per source,
+// ordered by descending total, with dates shown in loc
+func NewSourceSeriesResponses(rows []models.TrendRecordSource, metric string, loc *time.Location) []*SourceSeriesResponse {
+	dayTotals := make(map[string]float64)
+	var grandTotal float64
+	for _, row := range rows {
+		dayTotals[row.Date.In(loc).Format("2006-01-02")] += row.MetricValue(metric)
+		grandTotal += row.MetricValue(metric)
+	}
+
+	bySource := make(map[string]*SourceSeriesResponse)
+	series := []*SourceSeriesResponse{}
+	for _, row := range rows {
+		s, ok := bySource[row.Source]
+		if !ok {
+			s = &SourceSeriesResponse{Source: row.Source}
+			bySource[row.Source] = s
+			series = append(series, s)
+		}
+
+		date := row.Date.In(loc).Format("2006-01-02")
+		value := row.MetricValue(metric)
+		point := &SourcePointResponse{
+			Date:       date,
+			Volume:     row.Volume,
+			Engagement: row.Engagement,
+			Value:      value,
+			Sentiment:  row.Sentiment,
+			FetchOK:    row.FetchOK,
+			FetchError: row.FetchError,
+		}
+		if dayTotals[date] > 0 {
+			point.Share = value / dayTotals[date]
+		}
+		if !row.FetchOK {
+			s.FailedDays++
+		}
+		s.TotalValue += value
+		s.Points = append(s.Points, point)
 	}
+
+	for _, s := range series {
+		if grandTotal > 0 {
+			s.Share = s.TotalValue / grandTotal
+		}
+	}
+	sort.SliceStable(series, func(i, j int) bool {
+		return series[i].TotalValue > series[j].TotalValue
+	})
+
+	return series
 }
 
 // PredictionResult represents the result of a trend prediction (legacy compatibility)
@@ -131,4 +428,77 @@ type SentimentResult struct {
 // NewSentimentResponse creates a new sentiment response
 func NewSentimentResponse(result SentimentResult) SentimentResult {
 	return result
-} 
\ No newline at end of file
+}
+
+// BacktestCoverageResponse represents the interval coverage of one level
+type BacktestCoverageResponse struct {
+	Level    float64 `json:"level"`    // in percent
+	Coverage float64 `json:"coverage"` // share of actual values inside the interval
+}
+
+// BacktestModelResponse represents the backtest accuracy of one model.
+// Errors are null when undefined, such as MAPE on a series of zeros.
+type BacktestModelResponse struct {
+	Rank      int                         `json:"rank"`
+	Model     string                      `json:"model"`
+	MAE       *float64                    `json:"mae"`
+	RMSE      *float64                    `json:"rmse"`
+	MAPE      *float64                    `json:"mape"`  // in percent
+	SMAPE     *float64                    `json:"smape"` // in percent
+	MASE      *float64                    `json:"mase"`
+	Coverage  []*BacktestCoverageResponse `json:"coverage"`
+	Points    int                         `json:"points"`
+	Fallbacks int                         `json:"fallbacks"` // folds that fell back to Holt-Winters
+	Error     string                      `json:"error,omitempty"`
+}
+
+// BacktestResponse represents the response for forecast backtesting
+type BacktestResponse struct {
+	KeywordID int                      `json:"keyword_id"`
+	Metric    string                   `json:"metric"`
+	Source    []string                 `json:"source,omitempty"` // source filter, if any
+	Horizon   int                      `json:"horizon"`
+	Folds     int                      `json:"folds"`
+	Models    []*BacktestModelResponse `json:"models"` // best MASE first
+}
+
+// NewBacktestResponse creates a backtest response from results ordered best first
+func NewBacktestResponse(keywordID int, metric string, sources []string, horizon, folds int, results []trend.BacktestResult) *BacktestResponse {
+	responses := make([]*BacktestModelResponse, len(results))
+	for i, r := range results {
+		coverage := make([]*BacktestCoverageResponse, len(r.Coverage))
+		for j, c := range r.Coverage {
+			coverage[j] = &BacktestCoverageResponse{Level: math.Round(c.Level*1000) / 10, Coverage: c.Coverage}
+		}
+		responses[i] = &BacktestModelResponse{
+			Rank:      i + 1,
+			Model:     r.Model,
+			MAE:       finiteValue(r.MAE),
+			RMSE:      finiteValue(r.RMSE),
+			MAPE:      finiteValue(r.MAPE),
+			SMAPE:     finiteValue(r.SMAPE),
+			MASE:      finiteValue(r.MASE),
+			Coverage:  coverage,
+			Points:    r.Points,
+			Fallbacks: r.Fallbacks,
+			Error:     r.Err,
+		}
+	}
+
+	return &BacktestResponse{
+		KeywordID: keywordID,
+		Metric:    metric,
+		Source:    sources,
+		Horizon:   horizon,
+		Folds:     folds,
+		Models:    responses,
+	}
+}
+
+// finiteValue returns v, or nil when it is NaN or infinite
+func finiteValue(v float64) *float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil
+	}
+	return &v
+}
@@ -0,0 +1,119 @@
+package views
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/trendscout/backend/internal/metrics"
+	"github.com/trendscout/backend/internal/models"
+)
+
+func TestNewSourceSeriesResponses(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	// 15:00 UTC is the next day in Tokyo
+	day1 := time.Date(2026, 5, 1, 15, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	rows := []models.TrendRecordSource{
+		{Date: day1, Source: "bluesky", Volume: 1, FetchOK: true},
+		{Date: day1, Source: "vogue.com", Volume: 3, FetchOK: true},
+		{Date: day2, Source: "bluesky", Volume: 0, FetchOK: false, FetchError: "timeout"},
+		{Date: day2, Source: "vogue.com", Volume: 4, FetchOK: true},
+		// A day without any volume has no shares
+		{Date: day2.AddDate(0, 0, 1), Source: "vogue.com", Volume: 0, FetchOK: true},
+	}
+
+	series := NewSourceSeriesResponses(rows, metrics.Volume, tokyo)
+	if len(series) != 2 || series[0].Source != "vogue.com" || series[1].Source != "bluesky" {
+		t.Fatalf("series = %+v, want vogue.com then bluesky", series)
+	}
+	vogue, bluesky := series[0], series[1]
+
+	if vogue.TotalValue != 7 || bluesky.TotalValue != 1 {
+		t.Errorf("totals = %v, %v, want 7 and 1", vogue.TotalValue, bluesky.TotalValue)
+	}
+	if math.Abs(vogue.Share-7.0/8) > 1e-9 || math.Abs(bluesky.Share-1.0/8) > 1e-9 {
+		t.Errorf("shares = %v, %v, want 7/8 and 1/8", vogue.Share, bluesky.Share)
+	}
+	if bluesky.FailedDays != 1 || vogue.FailedDays != 0 {
+		t.Errorf("failed days = %d, %d, want 0 and 1", vogue.FailedDays, bluesky.FailedDays)
+	}
+
+	wantShares := []float64{0.75, 1, 0}
+	for i, p := range vogue.Points {
+		if math.Abs(p.Share-wantShares[i]) > 1e-9 {
+			t.Errorf("vogue.com point %d share = %v, want %v", i, p.Share, wantShares[i])
+		}
+	}
+	if vogue.Points[0].Date != "2026-05-02" {
+		t.Errorf("date = %s, want 2026-05-02 in Tokyo", vogue.Points[0].Date)
+	}
+	// The shares of each day add up to 1 across sources
+	if sum := vogue.Points[0].Share + bluesky.Points[0].Share; math.Abs(sum-1) > 1e-9 {
+		t.Errorf("day shares add up to %v", sum)
+	}
+
+	if got := NewSourceSeriesResponses(nil, metrics.Volume, tokyo); got == nil || len(got) != 0 {
+		t.Errorf("no rows = %v, want an empty list", got)
+	}
+}
+
+func TestNewDefinitionChangeResponses(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	v1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// Thursday 7 March 07:30 in Tokyo
+	v2 := time.Date(2024, 3, 6, 22, 30, 0, 0, time.UTC)
+	// Monday 1 April 01:00 in Tokyo, still March in UTC
+	v3 := time.Date(2024, 3, 31, 16, 0, 0, 0, time.UTC)
+	versions := []models.KeywordVersion{
+		{Version: 1, Keyword: "jeans", EffectiveFrom: v1},
+		{Version: 2, Keyword: "denim", EffectiveFrom: v2},
+		{Version: 3, Keyword: "wide denim", EffectiveFrom: v3},
+	}
+
+	tests := []struct {
+		name        string
+		granularity string
+		start, end  time.Time
+		loc         *time.Location
+		want        []string // "version:previous keyword:date"
+	}{
+		// The first version is not a change, even inside the range
+		{"day", "day", v1, v3.AddDate(0, 1, 0), tokyo, []string{"2:jeans:2024-03-07", "3:denim:2024-04-01"}},
+		{"day in UTC", "day", v1, v3.AddDate(0, 1, 0), time.UTC, []string{"2:jeans:2024-03-06", "3:denim:2024-03-31"}},
+		{"hour", "hour", v1, v3.AddDate(0, 1, 0), tokyo, []string{"2:jeans:2024-03-07T07:00:00+09:00", "3:denim:2024-04-01T01:00:00+09:00"}},
+		{"week", "week", v1, v3.AddDate(0, 1, 0), tokyo, []string{"2:jeans:2024-03-04", "3:denim:2024-04-01"}},
+		{"month", "month", v1, v3.AddDate(0, 1, 0), tokyo, []string{"2:jeans:2024-03-01", "3:denim:2024-04-01"}},
+		// Both ends of the range are inclusive
+		{"range ends on changes", "day", v2, v3, tokyo, []string{"2:jeans:2024-03-07", "3:denim:2024-04-01"}},
+		{"range starts after a change", "day", v2.Add(time.Second), v3, tokyo, []string{"3:denim:2024-04-01"}},
+		{"range ends before a change", "day", v2, v3.Add(-time.Second), tokyo, []string{"2:jeans:2024-03-07"}},
+		{"range without changes", "day", v2.Add(time.Second), v3.Add(-time.Second), tokyo, nil},
+	}
+
+	for _, tt := range tests {
+		changes := NewDefinitionChangeResponses(versions, tt.granularity, tt.start, tt.end, tt.loc)
+		var got []string
+		for _, c := range changes {
+			got = append(got, fmt.Sprintf("%d:%s:%s", c.Version, c.PreviousKeyword, c.Date))
+		}
+		if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+			t.Errorf("%s: changes = %v, want %v", tt.name, got, tt.want)
+		}
+		if changes == nil {
+			t.Errorf("%s: changes should be empty, not nil", tt.name)
+		}
+	}
+
+	// The change keeps its keyword and exact effective time
+	changes := NewDefinitionChangeResponses(versions, "day", v1, v3, tokyo)
+	if c := changes[1]; c.Keyword != "wide denim" || !c.EffectiveFrom.Equal(v3) {
+		t.Errorf("change = %+v, want wide denim effective from %s", c, v3)
+	}
+
+	if changes := NewDefinitionChangeResponses(nil, "day", v1, v3, tokyo); changes == nil || len(changes) != 0 {
+		t.Errorf("no versions: changes = %v, want empty", changes)
+	}
+}
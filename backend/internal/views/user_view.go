@@ -10,6 +10,7 @@ import (
 type UserResponse struct {
 	ID        int       `json:"id"`
 	Email     string    `json:"email"`
+	Timezone  string    `json:"timezone"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
@@ -18,6 +19,7 @@ func NewUserResponse(user *models.User) *UserResponse {
 	return &UserResponse{
 		ID:        user.ID,
 		Email:     user.Email,
+		Timezone:  user.Timezone,
 		CreatedAt: user.CreatedAt,
 	}
 }
@@ -39,4 +41,16 @@ func NewUserListResponse(users []*models.User) *UserListResponse {
 		Users: userResponses,
 		Count: len(userResponses),
 	}
-} 
\ No newline at end of file
+}
+
+// PreferencesResponse represents a user's preferences
+type PreferencesResponse struct {
+	Timezone string `json:"timezone"`
+}
+
+// NewPreferencesResponse creates a new preferences response from a user model
+func NewPreferencesResponse(user *models.User) *PreferencesResponse {
+	return &PreferencesResponse{
+		Timezone: user.Timezone,
+	}
+}
@@ -12,11 +12,18 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"github.com/trendscout/backend/internal/controllers"
+	"github.com/trendscout/backend/internal/migrate"
 	"github.com/trendscout/backend/internal/models"
 	"github.com/trendscout/backend/internal/scheduler"
 )
 
 func main() {
+	// "migrate" runs the migration command instead of the server
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+
 	// Load environment variables from .env file
 	if err := godotenv.Load(); err != nil {
 		log.Printf("No .env file found: %v", err)
@@ -28,6 +35,16 @@ func main() {
 	}
 	defer models.CloseDatabases()
 
+	// Apply pending schema migrations unless disabled
+	if os.Getenv("MIGRATE_ON_STARTUP") != "false" {
+		migrateCtx, migrateCancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		err := migrate.Run(migrateCtx, models.PgPool, models.MongoDB)
+		migrateCancel()
+		if err != nil {
+			log.Fatalf("Failed to migrate databases: %v", err)
+		}
+	}
+
 	// Initialize Redis
 	if err := models.InitRedis(); err != nil {
 		log.Fatalf("Failed to initialize Redis: %v", err)
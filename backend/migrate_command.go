@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/trendscout/backend/internal/migrate"
+	"github.com/trendscout/backend/internal/models"
+)
+
+const migrateUsage = `Usage: main migrate [flags] [up|down|status]
+
+  up      apply all pending migrations (default)
+  down    roll back the latest migrations (-steps, default 1)
+  status  list migrations and whether they are applied
+
+Flags:
+`
+
+// runMigrate runs the migrate subcommand
+func runMigrate(args []string) {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	database := flags.String("db", "all", "database to migrate: all, postgres or mongo")
+	dryRun := flags.Bool("dry-run", false, "list the migrations that would run without running them")
+	steps := flags.Int("steps", 1, "number of migrations to roll back with down")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), migrateUsage)
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	action := "up"
+	if flags.NArg() > 0 {
+		action = flags.Arg(0)
+	}
+	if action != "up" && action != "down" && action != "status" {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if *steps < 1 {
+		log.Fatal("-steps must be at least 1")
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Printf("No .env file found: %v", err)
+	}
+	if err := models.InitDatabases(); err != nil {
+		log.Fatalf("Failed to initialize databases: %v", err)
+	}
+	defer models.CloseDatabases()
+
+	migrators, err := migrate.Migrators(models.PgPool, models.MongoDB)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	failed := false
+	for _, m := range migrators {
+		if *database != "all" && *database != m.Database() {
+			continue
+		}
+
+		var err error
+		switch action {
+		case "status":
+			err = printMigrationStatus(ctx, m)
+		case "up":
+			var done []migrate.Migration
+			if done, err = m.Up(ctx, *dryRun); err == nil {
+				printMigrations(m.Database(), "apply", done, *dryRun)
+			}
+		case "down":
+			var done []migrate.Migration
+			if done, err = m.Down(ctx, *steps, *dryRun); err == nil {
+				printMigrations(m.Database(), "roll back", done, *dryRun)
+			}
+		}
+		if err != nil {
+			log.Printf("%s: %v", m.Database(), err)
+			failed = true
+		}
+	}
+
+	if failed {
+		models.CloseDatabases()
+		os.Exit(1)
+	}
+}
+
+// printMigrationStatus prints the status of every migration of a database
+func printMigrationStatus(ctx context.Context, m migrate.Migrator) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s:\n", m.Database())
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "  VERSION\tNAME\tSTATE\tAPPLIED AT")
+	for _, s := range statuses {
+		state, appliedAt := "pending", ""
+		if s.Applied {
+			state, appliedAt = "applied", s.AppliedAt.Format(time.RFC3339)
+		}
+		switch {
+		case s.Missing:
+			state = "applied, unknown to this binary"
+		case s.Modified:
+			state = "applied, modified since"
+		}
+		fmt.Fprintf(w, "  %04d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+	}
+	return w.Flush()
+}
+
+// printMigrations lists the migrations an up or down run applied or would apply
+func printMigrations(database, verb string, migrations []migrate.Migration, dryRun bool) {
+	if len(migrations) == 0 {
+		fmt.Printf("%s: nothing to %s\n", database, verb)
+		return
+	}
+	prefix := ""
+	if dryRun {
+		prefix = "would "
+	}
+	for _, m := range migrations {
+		fmt.Printf("%s: %s%s %04d_%s\n", database, prefix, verb, m.Version, m.Name)
+	}
+}